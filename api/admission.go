@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	gin "github.com/gin-gonic/gin"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// chatDispatchKey is the gin context key holding the *chatDispatch of an
+// admitted chat completion
+const chatDispatchKey = "chat_dispatch"
+
+// chatDispatch is where an admitted chat completion is sent
+type chatDispatch struct {
+	providerID types.Provider
+	provider   core.IProvider
+	// model is the model name sent to the provider
+	model string
+	// routedProvider and routedModel are the deployment a logical model was
+	// routed to, empty for requests naming a provider model
+	routedProvider string
+	routedModel    string
+}
+
+// ChatCompletionsAdmission returns the middleware admitting chat completions
+// before the middlewares answering them without the handler (the response
+// cache, the MCP agent), so that every answer goes through the same checks.
+func (router *RouterImpl) ChatCompletionsAdmission() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.Path != middlewares.ChatCompletionsPath {
+			c.Next()
+			return
+		}
+		if _, ok := router.admitChatCompletion(c); !ok {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// admitChatCompletion resolves the provider of a chat completion and applies
+// the checks and rewrites done before it is sent, see dispatchChat and
+// prepareChat. The admitted request replaces the shared request body
+// (keeping the requested model name, which later middlewares resolve on their
+// own) and the dispatch is stored on c, so a request is admitted once.
+//
+// It returns false after writing an error response for a rejected request.
+func (router *RouterImpl) admitChatCompletion(c *gin.Context) (*chatDispatch, bool) {
+	if v, ok := c.Get(chatDispatchKey); ok {
+		return v.(*chatDispatch), true
+	}
+
+	body := middlewares.GetRequestBody(c, router.cfg)
+	parsed, err := body.ChatCompletionRequest()
+	if err != nil {
+		if errors.Is(err, middlewares.ErrRequestBodyTooLarge) {
+			router.logger.Error("request body too large", err)
+			upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
+			return nil, false
+		}
+		router.logger.Error("failed to decode request", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+		return nil, false
+	}
+	// The decoded request is shared with the middlewares; work on a copy
+	req := *parsed
+	req.Messages = slices.Clone(req.Messages)
+
+	originalModel := req.Model
+	dispatch, ok := router.dispatchChat(c, originalModel, "openai/gpt-4")
+	if !ok {
+		return nil, false
+	}
+	req.Model = dispatch.model
+	if !router.prepareChat(c, dispatch.providerID, originalModel, &req) {
+		return nil, false
+	}
+
+	req.Model = originalModel
+	body.SetChatCompletionRequest(&req)
+	c.Set(chatDispatchKey, dispatch)
+	return dispatch, true
+}
+
+// dispatchChat resolves where a request for model is sent: to the provider of
+// the ?provider= query parameter, to the deployment the routing selector picks
+// for a logical model, or to the provider prefix of the model name, with
+// exampleModel suggested when none applies. The model allow-lists are checked
+// against the requested model.
+//
+// It returns false after writing an error response for a rejected request.
+func (router *RouterImpl) dispatchChat(c *gin.Context, model, exampleModel string) (*chatDispatch, bool) {
+	originalModel := model
+	providerID := types.Provider(c.Query("provider"))
+	dispatch := &chatDispatch{}
+
+	if router.selector != nil && providerID == "" {
+		if dep, ok := router.selector.Select(model); ok {
+			providerID = types.Provider(dep.Provider)
+			model = dep.Model
+			dispatch.routedProvider, dispatch.routedModel = dep.Provider, dep.Model
+			router.logger.Debug("routed logical model", "alias", originalModel, "provider", dep.Provider, "model", dep.Model)
+		}
+	}
+
+	if providerID == "" {
+		var providerPtr *types.Provider
+		providerPtr, model = routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., "+exampleModel+")."))
+			return nil, false
+		}
+		providerID = *providerPtr
+	}
+
+	if reason := router.modelDenied(originalModel); reason != "" {
+		upstreamError(c, deniedError(reason))
+		return nil, false
+	}
+
+	provider, err := router.registry.BuildProvider(providerID, router.client)
+	if err != nil {
+		if strings.Contains(err.Error(), "token not configured") {
+			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
+			return nil, false
+		}
+		router.logger.Error("provider not found or not supported", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
+		return nil, false
+	}
+
+	dispatch.providerID, dispatch.provider, dispatch.model = providerID, provider, model
+	return dispatch, true
+}
+
+// prepareChat applies the rewrites and checks of a chat completion for
+// originalModel, whose req names the model of providerID, before it is sent:
+// image stripping for non-vision models, request shaping, truncation and the
+// context-window check.
+//
+// It returns false after writing an error response for a rejected request.
+func (router *RouterImpl) prepareChat(c *gin.Context, providerID types.Provider, originalModel string, req *types.CreateChatCompletionRequest) bool {
+	if router.cfg.EnableVision {
+		hasImageContent := false
+		imageCount := 0
+		for _, message := range req.Messages {
+			if message.HasImageContent() {
+				hasImageContent = true
+				imageCount++
+			}
+		}
+
+		if hasImageContent {
+			if !core.ModelAcceptsImages(providerID, req.Model) {
+				router.logger.Info("filtering images from non-vision model request",
+					"provider", providerID,
+					"model", req.Model,
+					"messagesWithImages", imageCount)
+
+				for i := range req.Messages {
+					if req.Messages[i].HasImageContent() {
+						if err := req.Messages[i].StripImageContent(); err != nil {
+							router.logger.Error("failed to strip image content from message", err)
+							upstreamError(c, serverError(http.StatusInternalServerError, "Failed to process message content"))
+							return false
+						}
+					}
+				}
+
+				router.logger.Debug("images stripped from request, continuing with text-only content")
+			}
+		}
+	}
+
+	if !router.shape(c, providerID, originalModel, req) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
+	defer cancel()
+	if !router.truncate(ctx, c, providerID, originalModel, req) {
+		return false
+	}
+
+	if router.cfg.EnforceContextWindow {
+		if reason := router.contextWindowExceeded(ctx, providerID, *req); reason != "" {
+			upstreamError(c, contextLengthError(reason))
+			return false
+		}
+	}
+	return true
+}
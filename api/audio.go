@@ -0,0 +1,240 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gin "github.com/gin-gonic/gin"
+	otelapi "go.opentelemetry.io/otel"
+	codes "go.opentelemetry.io/otel/codes"
+	propagation "go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	trace "go.opentelemetry.io/otel/trace"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// audioFormFieldFile is the multipart field carrying the audio file of the
+// /audio/transcriptions and /audio/translations endpoints
+const audioFormFieldFile = "file"
+
+var (
+	audioTranscriptionsTarget = multipartTarget{
+		api:          "Audio",
+		enabled:      func(cfg config.Config) bool { return cfg.EnableAudio },
+		flag:         "ENABLE_AUDIO",
+		endpoint:     func(e types.Endpoints) *string { return e.AudioTranscriptions },
+		fileFields:   []string{audioFormFieldFile},
+		missingFile:  "The audio 'file' is required.",
+		exampleModel: "openai/whisper-1",
+	}
+	audioTranslationsTarget = multipartTarget{
+		api:          "Audio",
+		enabled:      func(cfg config.Config) bool { return cfg.EnableAudio },
+		flag:         "ENABLE_AUDIO",
+		endpoint:     func(e types.Endpoints) *string { return e.AudioTranslations },
+		fileFields:   []string{audioFormFieldFile},
+		missingFile:  "The audio 'file' is required.",
+		exampleModel: "openai/whisper-1",
+	}
+)
+
+// AudioTranscriptionsHandler implements POST /v1/audio/transcriptions
+// (multipart/form-data): https://platform.openai.com/docs/api-reference/audio/createTranscription
+//
+// The response is relayed in whichever format was asked for (json, text, srt,
+// vtt), and as server-sent events for stream=true.
+func (router *RouterImpl) AudioTranscriptionsHandler(c *gin.Context) {
+	router.handleMultipart(c, audioTranscriptionsTarget)
+}
+
+// AudioTranslationsHandler implements POST /v1/audio/translations
+// (multipart/form-data), transcribing audio into English.
+func (router *RouterImpl) AudioTranslationsHandler(c *gin.Context) {
+	router.handleMultipart(c, audioTranslationsTarget)
+}
+
+// AudioSpeechHandler implements an OpenAI-compatible POST /v1/audio/speech
+// endpoint: https://platform.openai.com/docs/api-reference/audio/createSpeech
+//
+// The request body is forwarded byte-for-byte (only the `model` field is
+// rewritten when the provider prefix is stripped). The audio is relayed as the
+// provider generates it, so clients can start playing it right away;
+// stream_format=sse responses are relayed as server-sent events.
+//
+// Like the other Audio endpoints, it is opt-in via ENABLE_AUDIO and only
+// served by providers with audio support.
+func (router *RouterImpl) AudioSpeechHandler(c *gin.Context) {
+	if !router.cfg.EnableAudio {
+		router.logger.Error("api not enabled", nil, "api", "Audio")
+		upstreamError(c, requestError(http.StatusNotFound, "The Audio API is not enabled. Set ENABLE_AUDIO=true to enable it."))
+		return
+	}
+
+	maxBodySize := router.cfg.Server.ResolveMaxRequestBodySize()
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)))
+	if err != nil {
+		router.logger.Error("failed to read request body", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to read request"))
+		return
+	}
+	if len(body) >= maxBodySize {
+		upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
+		return
+	}
+
+	var req struct {
+		Model string `json:"model"`
+		Input string `json:"input"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		router.logger.Error("failed to decode request", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+		return
+	}
+	if strings.TrimSpace(req.Input) == "" {
+		upstreamError(c, requestError(http.StatusBadRequest, "The 'input' field is required."))
+		return
+	}
+
+	originalModel := req.Model
+	model := req.Model
+	providerID := types.Provider(c.Query("provider"))
+	if providerID == "" {
+		var providerPtr *types.Provider
+		providerPtr, model = routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-4o-mini-tts)."))
+			return
+		}
+		providerID = *providerPtr
+	}
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(
+		semconv.GenAIProviderNameKey.String(string(providerID)),
+		semconv.GenAIRequestModel(originalModel),
+	)
+
+	if reason := router.modelDenied(originalModel); reason != "" {
+		upstreamError(c, deniedError(reason))
+		return
+	}
+
+	provider, err := router.registry.BuildProvider(providerID, router.client)
+	if err != nil {
+		if strings.Contains(err.Error(), "token not configured") {
+			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
+			return
+		}
+		router.logger.Error("provider not found or not supported", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
+		return
+	}
+
+	endpoint := provider.GetEndpoints().AudioSpeech
+	if endpoint == nil || *endpoint == "" {
+		router.logger.Error("api not supported by provider", nil, "api", "Audio", "provider", providerID)
+		upstreamError(c, requestError(http.StatusBadRequest, "The Audio API is not supported by this provider yet."))
+		return
+	}
+
+	if model != originalModel {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var payload map[string]any
+		if err := dec.Decode(&payload); err != nil {
+			router.logger.Error("failed to decode request", err)
+			upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+			return
+		}
+		payload["model"] = model
+		if body, err = json.Marshal(payload); err != nil {
+			router.logger.Error("failed to encode request", err)
+			upstreamError(c, serverError(http.StatusInternalServerError, "Failed to encode request"))
+			return
+		}
+	}
+
+	// The audio streams for as long as it takes to speak the input, so only
+	// the client bounds the request
+	ctx := c.Request.Context()
+	upstreamURL := strings.TrimSuffix(provider.GetURL(), "/") + *endpoint
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
+		router.logger.Error("unsupported auth type", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
+		return
+	}
+
+	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(upstreamReq.Header))
+
+	resp, err := router.client.Do(upstreamReq)
+	if err != nil {
+		router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
+		upstreamError(c, transportError(ctx, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+		c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, relayHeaders(resp))
+		return
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		middlewares.SetSSEHeaders(c)
+		router.relayStream(c, resp.Body, upstreamURL, chatIdleEvent, nil)
+		return
+	}
+	router.relayBody(ctx, c, resp, upstreamURL)
+}
+
+// relayBody copies a binary upstream response to the client as it arrives,
+// flushing after every read
+func (router *RouterImpl) relayBody(ctx context.Context, c *gin.Context, resp *http.Response, upstreamURL string) {
+	c.Header("Content-Type", resp.Header.Get("Content-Type"))
+	if resp.ContentLength >= 0 {
+		c.Header("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	c.Status(resp.StatusCode)
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+			if _, writeErr := c.Writer.Write(buf[:n]); writeErr != nil {
+				router.logger.Error("failed to write response", writeErr)
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				router.logger.Error("failed to read response", err, "url", upstreamURL)
+			}
+			return
+		}
+	}
+}
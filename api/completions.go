@@ -0,0 +1,378 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gin "github.com/gin-gonic/gin"
+	otelapi "go.opentelemetry.io/otel"
+	codes "go.opentelemetry.io/otel/codes"
+	propagation "go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	trace "go.opentelemetry.io/otel/trace"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// completionRequest is a legacy POST /v1/completions request. Providers with
+// a native completions API receive the body as-is; the fields are only read to
+// adapt the request to a chat completion for the other providers.
+type completionRequest struct {
+	Model            string                                  `json:"model"`
+	Prompt           json.RawMessage                         `json:"prompt"`
+	Suffix           *string                                 `json:"suffix"`
+	Echo             *bool                                   `json:"echo"`
+	Logprobs         *int                                    `json:"logprobs"`
+	BestOf           *int                                    `json:"best_of"`
+	MaxTokens        *int                                    `json:"max_tokens"`
+	Temperature      *float32                                `json:"temperature"`
+	TopP             *float32                                `json:"top_p"`
+	N                *int                                    `json:"n"`
+	Stop             *types.CreateChatCompletionRequest_Stop `json:"stop"`
+	PresencePenalty  *float32                                `json:"presence_penalty"`
+	FrequencyPenalty *float32                                `json:"frequency_penalty"`
+	LogitBias        *map[string]int                         `json:"logit_bias"`
+	Seed             *int                                    `json:"seed"`
+	User             *string                                 `json:"user"`
+	Stream           *bool                                   `json:"stream"`
+	StreamOptions    *types.ChatCompletionStreamOptions      `json:"stream_options"`
+}
+
+// completionChoice is a choice of a legacy completion or of one of its
+// streamed chunks
+type completionChoice struct {
+	Text         string  `json:"text"`
+	Index        int     `json:"index"`
+	Logprobs     any     `json:"logprobs"`
+	FinishReason *string `json:"finish_reason"`
+}
+
+// completionResponse is a legacy completion, or a chunk of one when streaming
+type completionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int                    `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []completionChoice     `json:"choices"`
+	Usage   *types.CompletionUsage `json:"usage,omitempty"`
+}
+
+// CompletionsHandler implements the legacy OpenAI-compatible POST
+// /v1/completions endpoint:
+// https://platform.openai.com/docs/api-reference/completions/create
+//
+// Providers with a native completions API (e.g. llama.cpp, Ollama, OpenAI and
+// DeepSeek's FIM beta) receive the body byte-for-byte, only the `model` field
+// is rewritten when the provider prefix is stripped, and the response is
+// relayed verbatim. For every other provider the prompt is sent as a single
+// user message through the chat completions API, and the choices - or the
+// chunks when streaming - are translated back to `text_completion` objects.
+// Prompt batches, token prompts, `suffix`, `echo`, `logprobs` and `best_of`
+// have no chat equivalent and need a native completions API.
+//
+// Both paths resolve the model like chat completions (the `provider` query
+// parameter, routing aliases and the model allow-lists). Adapted requests are
+// then shaped, truncated and checked against the context window like any
+// other chat completion.
+func (router *RouterImpl) CompletionsHandler(c *gin.Context) {
+	body, err := middlewares.GetRequestBody(c, router.cfg).Bytes()
+	if err != nil {
+		if errors.Is(err, middlewares.ErrRequestBodyTooLarge) {
+			router.logger.Error("request body too large", err)
+			upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
+			return
+		}
+		router.logger.Error("failed to read request body", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to read request"))
+		return
+	}
+
+	var req completionRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		router.logger.Error("failed to decode request", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+		return
+	}
+	if len(req.Prompt) == 0 || string(req.Prompt) == "null" {
+		upstreamError(c, requestError(http.StatusBadRequest, "The 'prompt' field is required."))
+		return
+	}
+
+	originalModel := req.Model
+	dispatch, ok := router.dispatchChat(c, originalModel, "llamacpp/qwen2.5-coder")
+	if !ok {
+		return
+	}
+	providerID, provider, model := dispatch.providerID, dispatch.provider, dispatch.model
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(
+		semconv.GenAIProviderNameKey.String(string(providerID)),
+		semconv.GenAIRequestModel(originalModel),
+	)
+	if dispatch.routedProvider != "" {
+		c.Header("X-Selected-Provider", dispatch.routedProvider)
+		c.Header("X-Selected-Model", dispatch.routedModel)
+	}
+
+	isStreaming := req.Stream != nil && *req.Stream
+
+	if endpoint := provider.GetEndpoints().Completions; endpoint != nil && *endpoint != "" {
+		if model != originalModel {
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			var payload map[string]any
+			if err := dec.Decode(&payload); err != nil {
+				router.logger.Error("failed to decode request", err)
+				upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+				return
+			}
+			payload["model"] = model
+			if body, err = json.Marshal(payload); err != nil {
+				router.logger.Error("failed to encode request", err)
+				upstreamError(c, serverError(http.StatusInternalServerError, "Failed to encode request"))
+				return
+			}
+		}
+		query := c.Request.URL.Query()
+		query.Del("provider")
+		upstreamURL, err := core.BuildURL(provider, *endpoint, query.Encode())
+		if err != nil {
+			router.logger.Error("failed to build upstream url", err, "provider", providerID)
+			upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+			return
+		}
+		router.relayCompletions(c, providerID, provider, upstreamURL.String(), body, isStreaming)
+		return
+	}
+
+	chatReq, reason := completionToChat(req, model)
+	if reason != "" {
+		router.logger.Error("completion request cannot be adapted to a chat completion", nil, "provider", providerID, "reason", reason)
+		upstreamError(c, requestError(http.StatusBadRequest, reason))
+		return
+	}
+	if !router.prepareChat(c, providerID, originalModel, &chatReq) {
+		return
+	}
+
+	if isStreaming {
+		middlewares.SetSSEHeaders(c)
+
+		streamCh, err := provider.StreamChatCompletions(c.Request.Context(), chatReq)
+		if err != nil {
+			router.logger.Error("failed to start streaming", err, "provider", providerID)
+			upstreamError(c, err)
+			return
+		}
+
+		router.writeChatStream(c, streamCh, providerID, completionChunkSSE)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
+	defer cancel()
+
+	response, err := provider.ChatCompletions(ctx, chatReq)
+	if err != nil {
+		if err == context.DeadlineExceeded || ctx.Err() == context.DeadlineExceeded {
+			router.logger.Error("request timed out", err, "provider", providerID)
+			upstreamError(c, transportError(ctx, err))
+			return
+		}
+		router.logger.Error("failed to generate tokens", err, "provider", providerID)
+		upstreamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, completionFromChat(response))
+}
+
+// relayCompletions forwards a completion request to a provider's native
+// completions API and relays the response, streamed or not, verbatim
+func (router *RouterImpl) relayCompletions(c *gin.Context, providerID types.Provider, provider core.IProvider, upstreamURL string, body []byte, isStreaming bool) {
+	ctx := c.Request.Context()
+	if !isStreaming {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, router.cfg.Server.ReadTimeout)
+		defer cancel()
+	}
+
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	if isStreaming {
+		upstreamReq.Header.Set("Accept", "text/event-stream")
+	} else {
+		upstreamReq.Header.Set("Accept", "application/json")
+	}
+
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
+		router.logger.Error("unsupported auth type", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
+		return
+	}
+
+	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(upstreamReq.Header))
+
+	resp, err := router.client.Do(upstreamReq)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			router.logger.Error("request timed out", err, "provider", providerID)
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
+		}
+		upstreamError(c, transportError(ctx, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetStatus(codes.Error, resp.Status)
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "text/event-stream") {
+		c.DataFromReader(resp.StatusCode, resp.ContentLength, contentType, resp.Body, relayHeaders(resp))
+		return
+	}
+
+	middlewares.SetSSEHeaders(c)
+	router.relayStream(c, resp.Body, upstreamURL, chatIdleEvent, nil)
+}
+
+// completionToChat adapts a completion request to a chat completion sending
+// the prompt as a single user message. It returns the client-facing reason
+// when the request uses a feature chat completions have no equivalent for.
+func completionToChat(req completionRequest, model string) (types.CreateChatCompletionRequest, string) {
+	var chatReq types.CreateChatCompletionRequest
+
+	prompt, ok := completionPrompt(req.Prompt)
+	if !ok {
+		return chatReq, "This provider has no native completions API: 'prompt' must be a single string."
+	}
+	unsupported := []struct {
+		field string
+		set   bool
+	}{
+		{"suffix", req.Suffix != nil},
+		{"echo", req.Echo != nil && *req.Echo},
+		{"logprobs", req.Logprobs != nil},
+		{"best_of", req.BestOf != nil && *req.BestOf > 1},
+	}
+	for _, u := range unsupported {
+		if u.set {
+			return chatReq, fmt.Sprintf("This provider has no native completions API: the '%s' field is not supported.", u.field)
+		}
+	}
+
+	message := types.Message{Role: types.User}
+	if err := message.Content.FromMessageContent0(prompt); err != nil {
+		return chatReq, "Failed to decode request"
+	}
+
+	chatReq = types.CreateChatCompletionRequest{
+		Model:            model,
+		Messages:         []types.Message{message},
+		MaxTokens:        req.MaxTokens,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		N:                req.N,
+		Stop:             req.Stop,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+		LogitBias:        req.LogitBias,
+		Seed:             req.Seed,
+		User:             req.User,
+		Stream:           req.Stream,
+		StreamOptions:    req.StreamOptions,
+	}
+	return chatReq, ""
+}
+
+// completionPrompt returns the text of a prompt given as a string or as an
+// array holding a single string
+func completionPrompt(raw json.RawMessage) (string, bool) {
+	var prompt string
+	if err := json.Unmarshal(raw, &prompt); err == nil {
+		return prompt, true
+	}
+	var prompts []string
+	if err := json.Unmarshal(raw, &prompts); err == nil && len(prompts) == 1 {
+		return prompts[0], true
+	}
+	return "", false
+}
+
+// completionFromChat translates a chat completion to a legacy completion
+func completionFromChat(resp types.CreateChatCompletionResponse) completionResponse {
+	completion := completionResponse{
+		ID:      resp.ID,
+		Object:  "text_completion",
+		Created: resp.Created,
+		Model:   resp.Model,
+		Choices: make([]completionChoice, 0, len(resp.Choices)),
+		Usage:   resp.Usage,
+	}
+	for _, choice := range resp.Choices {
+		completion.Choices = append(completion.Choices, completionChoice{
+			Text:         choice.Message.TextContent(),
+			Index:        choice.Index,
+			FinishReason: finishReason(choice.FinishReason),
+		})
+	}
+	return completion
+}
+
+// completionChunkSSE encodes a chat completion stream event as a legacy
+// completion chunk; errors keep the OpenAI error envelope
+func completionChunkSSE(event core.StreamEvent) []byte {
+	if event.Err != nil || event.Chunk == nil {
+		return event.SSE()
+	}
+
+	chunk := completionResponse{
+		ID:      event.Chunk.ID,
+		Object:  "text_completion",
+		Created: event.Chunk.Created,
+		Model:   event.Chunk.Model,
+		Choices: make([]completionChoice, 0, len(event.Chunk.Choices)),
+		Usage:   event.Usage,
+	}
+	for _, choice := range event.Chunk.Choices {
+		chunk.Choices = append(chunk.Choices, completionChoice{
+			Text:         choice.Delta.Content,
+			Index:        choice.Index,
+			FinishReason: finishReason(choice.FinishReason),
+		})
+	}
+
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return core.StreamEvent{Err: err}.SSE()
+	}
+	return fmt.Appendf(nil, "data: %s\n\n", data)
+}
+
+// finishReason is reason as a nullable JSON string, null while generating
+func finishReason(reason types.FinishReason) *string {
+	if reason == "" {
+		return nil
+	}
+	s := string(reason)
+	return &s
+}
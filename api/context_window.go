@@ -12,14 +12,99 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
 // maxRuntimeLookups bounds concurrent runtime metadata calls per request.
 const maxRuntimeLookups = 4
 
+const (
+	// runtimeWindowTTL is how long a context window read from a local runtime
+	// is reused by the request checks before the runtime is asked again
+	runtimeWindowTTL = 5 * time.Minute
+	// runtimeWindowRetry is how long a failed runtime lookup is remembered, so
+	// an unreachable runtime is not asked on every request
+	runtimeWindowRetry = 30 * time.Second
+)
+
+// runtimeWindows memoizes the context windows of models served by local
+// runtimes for the request checks (context-window enforcement, truncation,
+// tokenize), which cannot afford a runtime call per request
+type runtimeWindows struct {
+	mu      sync.Mutex
+	entries map[string]runtimeWindow
+}
+
+type runtimeWindow struct {
+	tokens  int
+	ok      bool
+	expires time.Time
+}
+
+func (rw *runtimeWindows) get(key string) (runtimeWindow, bool) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	entry, ok := rw.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return runtimeWindow{}, false
+	}
+	return entry, true
+}
+
+func (rw *runtimeWindows) set(key string, entry runtimeWindow) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.entries == nil {
+		rw.entries = make(map[string]runtimeWindow)
+	}
+	rw.entries[key] = entry
+}
+
+func (rw *runtimeWindows) invalidate() {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.entries = nil
+}
+
+// runtimeContextWindow returns the context window a local runtime (llama.cpp,
+// Ollama) serves a model with, through the same lookups as
+// resolveContextWindows. Results, failures included, are memoized. It returns
+// false for other providers and when the runtime does not report a window.
+func (router *RouterImpl) runtimeContextWindow(ctx context.Context, providerID types.Provider, model string) (int, bool) {
+	var fetch func(ctx context.Context) (int, error)
+	key := string(providerID)
+	switch providerID {
+	case constants.LlamacppID:
+		// llama.cpp serves a single context size for every model
+		fetch = func(ctx context.Context) (int, error) {
+			return router.fetchLlamacppContextWindow(ctx, providerID)
+		}
+	case constants.OllamaID:
+		key += "/" + model
+		fetch = func(ctx context.Context) (int, error) {
+			return router.fetchOllamaContextWindow(ctx, providerID, model)
+		}
+	default:
+		return 0, false
+	}
+
+	if entry, ok := router.runtimeWindows.get(key); ok {
+		return entry.tokens, entry.ok
+	}
+	tokens, err := fetch(ctx)
+	if err != nil {
+		router.logger.Debug("failed to resolve runtime context window", "provider", providerID, "model", model, "error", err)
+		router.runtimeWindows.set(key, runtimeWindow{expires: time.Now().Add(runtimeWindowRetry)})
+		return 0, false
+	}
+	router.runtimeWindows.set(key, runtimeWindow{tokens: tokens, ok: true, expires: time.Now().Add(runtimeWindowTTL)})
+	return tokens, true
+}
+
 // resolveContextWindows fills the effective context window for models served by
 // local runtimes (llama.cpp, Ollama). The runtime value overrides any
 // provider-published one because it reflects what the server is actually
@@ -166,7 +251,7 @@ func (router *RouterImpl) runtimeAPICall(ctx context.Context, providerID types.P
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	if err := applyProviderAuth(req, provider); err != nil {
+	if err := core.ApplyAuth(req, provider); err != nil {
 		return err
 	}
 
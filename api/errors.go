@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+)
+
+// upstreamError writes an upstream failure in the OpenAI error envelope, see
+// core.ClassifyError, relaying the Retry-After header and the request ID of
+// the provider
+func upstreamError(c *gin.Context, err error) {
+	upstreamErr := core.ClassifyError(err)
+	setHeaders(c, upstreamErr.Headers())
+	c.JSON(upstreamErr.StatusCode, upstreamErr.OpenAI())
+}
+
+// messagesUpstreamError writes an upstream failure in the Anthropic error
+// envelope, see upstreamError
+func messagesUpstreamError(c *gin.Context, err error) {
+	upstreamErr := core.ClassifyError(err)
+	setHeaders(c, upstreamErr.Headers())
+	c.JSON(upstreamErr.StatusCode, upstreamErr.Anthropic())
+}
+
+// deniedError is the error of a request for a model blocked by the model
+// allow-lists, see modelDenied
+func deniedError(reason string) *core.UpstreamError {
+	return &core.UpstreamError{Code: core.ErrorCodeInvalidRequest, StatusCode: http.StatusForbidden, Message: reason}
+}
+
+// contextLengthError is the error of a request that does not fit the context
+// window of its model, see contextWindowExceeded
+func contextLengthError(reason string) *core.UpstreamError {
+	return &core.UpstreamError{Code: core.ErrorCodeContextLength, StatusCode: http.StatusBadRequest, Message: reason}
+}
+
+// shapingError is the error of a request the shaping rules could not be
+// applied to
+var shapingError = serverError(http.StatusInternalServerError, "Failed to apply request shaping")
+
+// requestError is the error of a request the gateway rejects before sending
+// it to a provider
+func requestError(status int, message string) *core.UpstreamError {
+	return &core.UpstreamError{Code: core.ErrorCodeInvalidRequest, StatusCode: status, Message: message}
+}
+
+// providerError is the error of a request for a provider the gateway cannot
+// call as configured: unknown, missing its API key or its auth type
+func providerError(status int, message string) *core.UpstreamError {
+	return &core.UpstreamError{Code: core.ErrorCodeProviderNotConfigured, StatusCode: status, Message: message}
+}
+
+// serverError is the error of a request the gateway failed to relay
+func serverError(status int, message string) *core.UpstreamError {
+	return &core.UpstreamError{Code: core.ErrorCodeUpstream, StatusCode: status, Message: message}
+}
+
+// transportError is the failure of a request that never got a response from
+// the provider: it timed out with ctx, or the provider could not be reached
+func transportError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &core.UpstreamError{Code: core.ErrorCodeTimeout, StatusCode: http.StatusGatewayTimeout, Message: "Request timed out"}
+	}
+	return &core.UpstreamError{Code: core.ErrorCodeUpstream, StatusCode: http.StatusBadGateway, Message: "Failed to reach upstream server"}
+}
+
+// relayHeaders are the headers of an upstream response relayed along its
+// body: Retry-After and the request ID of the provider, for failures
+func relayHeaders(resp *http.Response) map[string]string {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	return core.UpstreamHeaders(resp.Header)
+}
+
+func setHeaders(c *gin.Context, headers map[string]string) {
+	for name, value := range headers {
+		c.Header(name, value)
+	}
+}
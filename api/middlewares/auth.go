@@ -7,11 +7,17 @@ import (
 
 	oidcV3 "github.com/coreos/go-oidc/v3/oidc"
 	gin "github.com/gin-gonic/gin"
+	websocket "github.com/gorilla/websocket"
 	config "github.com/inference-gateway/inference-gateway/config"
 	logger "github.com/inference-gateway/inference-gateway/logger"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
+// WebSocketTokenProtocol prefixes the bearer token offered as a WebSocket
+// subprotocol, for browsers, which cannot set the Authorization header of an
+// upgrade request
+const WebSocketTokenProtocol = "bearer."
+
 type OIDCAuthenticator interface {
 	Middleware() gin.HandlerFunc
 }
@@ -59,14 +65,13 @@ func (a *OIDCAuthenticatorImpl) Middleware() gin.HandlerFunc {
 			return
 		}
 
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		token := bearerToken(c.Request)
+		if token == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			c.Abort()
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
 		idToken, err := a.verifier.Verify(c.Request.Context(), token)
 		if err != nil {
 			a.logger.Error("failed to verify id token", err)
@@ -75,13 +80,31 @@ func (a *OIDCAuthenticatorImpl) Middleware() gin.HandlerFunc {
 			return
 		}
 
-		ctx := context.WithValue(c.Request.Context(), types.AuthTokenContextKey, token)
 		var claims map[string]any
 		if err := idToken.Claims(&claims); err == nil {
-			ctx = context.WithValue(ctx, types.ClaimsContextKey, claims)
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), types.ClaimsContextKey, claims))
 		}
-		c.Request = c.Request.WithContext(ctx)
 
 		c.Next()
 	}
 }
+
+// bearerToken returns the token of the Authorization header, or the token
+// subprotocol offered by a WebSocket upgrade request without one. Other
+// requests only authenticate with the Authorization header.
+func bearerToken(r *http.Request) string {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		return strings.TrimPrefix(authHeader, "Bearer ")
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		return ""
+	}
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for protocol := range strings.SplitSeq(header, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), WebSocketTokenProtocol); ok {
+				return token
+			}
+		}
+	}
+	return ""
+}
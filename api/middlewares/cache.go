@@ -0,0 +1,342 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+	cache "github.com/inference-gateway/inference-gateway/internal/cache"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+const (
+	// CacheStatusHeader reports how the response cache served a chat
+	// completion: HIT, SEMANTIC-HIT, MISS or BYPASS
+	CacheStatusHeader = "X-Cache"
+	// CacheSimilarityHeader carries the cosine similarity of a semantic hit
+	CacheSimilarityHeader = "X-Cache-Similarity"
+
+	CacheStatusHit         = "HIT"
+	CacheStatusSemanticHit = "SEMANTIC-HIT"
+	CacheStatusMiss        = "MISS"
+	CacheStatusBypass      = "BYPASS"
+)
+
+// ResponseCacheMiddleware serves repeated chat completions from the response
+// cache
+type ResponseCacheMiddleware interface {
+	Middleware() gin.HandlerFunc
+}
+
+// ResponseCacheMiddlewareImpl caches successful chat completions keyed on the
+// canonicalized request. Streamed responses are stored as the aggregated
+// completion, so a cached entry can be served to both streaming and
+// non-streaming clients.
+//
+// With the semantic cache enabled, an exact miss falls back to the most
+// similar earlier request in the same scope (see cache.SemanticPrompt).
+type ResponseCacheMiddlewareImpl struct {
+	store                     cache.Store
+	ttl                       time.Duration
+	deterministicOnly         bool
+	semanticDeterministicOnly bool
+	embedder                  cache.Embedder
+	index                     *cache.SemanticIndex
+	threshold                 float64
+	telemetry                 otel.OpenTelemetry
+	logger                    logger.Logger
+	cfg                       config.Config
+}
+
+// semanticEntry is the embedded prompt of a request and its index scope
+type semanticEntry struct {
+	scope  string
+	vector []float32
+}
+
+// NoopResponseCacheMiddlewareImpl is used when the response cache is disabled
+type NoopResponseCacheMiddlewareImpl struct{}
+
+// NewResponseCacheMiddleware creates the response cache middleware and its
+// storage backend. Returns a Noop implementation when the cache is disabled.
+// The registry and client are used to compute embeddings for the semantic
+// cache.
+func NewResponseCacheMiddleware(cfg config.Config, providerRegistry registry.ProviderRegistry, httpClient client.Client, telemetry otel.OpenTelemetry, log logger.Logger) (ResponseCacheMiddleware, error) {
+	if cfg.Cache == nil || !cfg.Cache.Enabled {
+		return &NoopResponseCacheMiddlewareImpl{}, nil
+	}
+
+	store, err := cache.New(cfg.Cache.Backend, cfg.Cache.Dir, cfg.Cache.MaxEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ResponseCacheMiddlewareImpl{
+		store:             store,
+		ttl:               cfg.Cache.Ttl,
+		deterministicOnly: cfg.Cache.DeterministicOnly,
+		telemetry:         telemetry,
+		logger:            log,
+		cfg:               cfg,
+	}
+	if cfg.Cache.SemanticEnabled {
+		m.embedder = cache.NewProviderEmbedder(providerRegistry, httpClient, types.Provider(cfg.Cache.SemanticProvider), cfg.Cache.SemanticModel)
+		m.index = cache.NewSemanticIndex(cfg.Cache.MaxEntries)
+		m.threshold = cfg.Cache.SemanticThreshold
+		m.semanticDeterministicOnly = cfg.Cache.SemanticDeterministicOnly
+	}
+	return m, nil
+}
+
+// Middleware returns the no-op middleware handler
+func (n *NoopResponseCacheMiddlewareImpl) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+	}
+}
+
+// cacheResponseWriter captures the response body while writing it through.
+// Bodies over maxCapturedResponseBytes are not cached.
+type cacheResponseWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *cacheResponseWriter) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(b) > maxCapturedResponseBytes {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *cacheResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Middleware returns the response cache middleware handler
+func (m *ResponseCacheMiddlewareImpl) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.Path != ChatCompletionsPath {
+			c.Next()
+			return
+		}
+
+		// Errors are reported by the handler
+		req, err := GetRequestBody(c, m.cfg).ChatCompletionRequest()
+		if err != nil {
+			c.Next()
+			return
+		}
+		// The exact and the semantic cache each have their own
+		// deterministic-only switch
+		deterministic := cache.Deterministic(*req)
+		exact := !m.deterministicOnly || deterministic
+		semanticOn := m.index != nil && (!m.semanticDeterministicOnly || deterministic)
+		if !exact && !semanticOn {
+			c.Next()
+			return
+		}
+
+		provider := types.Provider(c.Query("provider"))
+		if provider == "" {
+			if detected, _ := routing.DetermineProviderAndModelName(req.Model); detected != nil {
+				provider = *detected
+			}
+		}
+		key, err := cache.ChatCompletionKey(provider, *req)
+		if err != nil {
+			m.logger.Error("failed to build cache key", err, "model", req.Model)
+			c.Next()
+			return
+		}
+
+		noCache, noStore := cacheControl(c.GetHeader("Cache-Control"))
+		streaming := req.Stream != nil && *req.Stream
+
+		var semantic *semanticEntry
+		if noCache {
+			m.record(c, provider, req.Model, cache.ResultBypass)
+			c.Header(CacheStatusHeader, CacheStatusBypass)
+		} else if cached, ok := m.lookup(key, exact); ok && m.serve(c, cached, streaming, CacheStatusHit) {
+			m.logger.Debug("serving cached chat completion", "model", req.Model, "stream", streaming)
+			m.record(c, provider, req.Model, cache.ResultHit)
+			c.Abort()
+			return
+		} else {
+			if semanticOn {
+				semantic = m.embed(c, provider, *req)
+			}
+			if semantic != nil {
+				cached, similarity, found := m.index.Nearest(semantic.scope, semantic.vector)
+				if found && similarity >= m.threshold {
+					c.Header(CacheSimilarityHeader, strconv.FormatFloat(similarity, 'f', 4, 64))
+					if m.serve(c, cached, streaming, CacheStatusSemanticHit) {
+						m.logger.Debug("serving semantically cached chat completion", "model", req.Model, "stream", streaming, "similarity", similarity)
+						m.record(c, provider, req.Model, cache.ResultSemanticHit)
+						c.Abort()
+						return
+					}
+					c.Writer.Header().Del(CacheSimilarityHeader)
+				}
+			}
+			m.record(c, provider, req.Model, cache.ResultMiss)
+			c.Header(CacheStatusHeader, CacheStatusMiss)
+		}
+
+		w := &cacheResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		c.Writer = w.ResponseWriter
+		if noStore || w.overflow || w.Status() != http.StatusOK {
+			return
+		}
+
+		value := w.body.Bytes()
+		if streaming {
+			resp, ok := cache.AggregateStream(value)
+			if !ok {
+				return
+			}
+			if value, err = json.Marshal(resp); err != nil {
+				return
+			}
+		} else if !json.Valid(value) {
+			return
+		}
+
+		value = bytes.Clone(value)
+		if exact {
+			if err := m.store.Set(key, value, m.ttl); err != nil {
+				m.logger.Error("failed to store chat completion in cache", err, "model", req.Model)
+			}
+		}
+		if semanticOn {
+			if semantic == nil {
+				semantic = m.embed(c, provider, *req)
+			}
+			if semantic != nil {
+				m.index.Add(semantic.scope, semantic.vector, value, m.ttl)
+			}
+		}
+	}
+}
+
+// lookup returns the exactly cached response stored under key, when the exact
+// cache applies to the request
+func (m *ResponseCacheMiddlewareImpl) lookup(key string, exact bool) ([]byte, bool) {
+	if !exact {
+		return nil, false
+	}
+	return m.store.Get(key)
+}
+
+// embed returns the embedded prompt of req for the semantic cache, or nil
+// when the semantic cache is disabled, the request does not qualify or the
+// embeddings provider fails.
+func (m *ResponseCacheMiddlewareImpl) embed(c *gin.Context, provider types.Provider, req types.CreateChatCompletionRequest) *semanticEntry {
+	if m.index == nil {
+		return nil
+	}
+
+	scope, prompt, err := cache.SemanticPrompt(provider, callerIdentity(c), req)
+	if err != nil || prompt == "" {
+		return nil
+	}
+	vector, err := m.embedder.Embed(c.Request.Context(), prompt)
+	if err != nil {
+		m.logger.Warn("failed to embed prompt for the semantic cache", "error", err.Error(), "model", req.Model)
+		return nil
+	}
+	return &semanticEntry{scope: scope, vector: vector}
+}
+
+// callerIdentity returns the OIDC subject of the caller, or "" for
+// unauthenticated requests, which share one scope.
+func callerIdentity(c *gin.Context) string {
+	claims, _ := c.Request.Context().Value(types.ClaimsContextKey).(map[string]any)
+	subject, _ := claims["sub"].(string)
+	return subject
+}
+
+// serve writes a cached completion with the given cache status, replaying it
+// as server-sent events when the client asked for a stream. It returns false
+// when the entry cannot be decoded, in which case the request goes upstream.
+func (m *ResponseCacheMiddlewareImpl) serve(c *gin.Context, cached []byte, streaming bool, status string) bool {
+	if !streaming {
+		c.Header(CacheStatusHeader, status)
+		c.Data(http.StatusOK, "application/json", cached)
+		return true
+	}
+
+	var resp types.CreateChatCompletionResponse
+	if err := json.Unmarshal(cached, &resp); err != nil {
+		m.logger.Error("failed to decode cached chat completion", err)
+		return false
+	}
+
+	c.Header(CacheStatusHeader, status)
+	SetSSEHeaders(c)
+	c.Status(http.StatusOK)
+	for _, chunk := range cache.ReplayStream(resp) {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			m.logger.Error("failed to encode cached chunk", err)
+			return true
+		}
+		if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+			return true
+		}
+	}
+	_, _ = c.Writer.WriteString("data: [DONE]\n\n")
+	c.Writer.Flush()
+	return true
+}
+
+func (m *ResponseCacheMiddlewareImpl) record(c *gin.Context, provider types.Provider, model, result string) {
+	if m.telemetry == nil {
+		return
+	}
+	if provider == "" {
+		provider = "unknown"
+	}
+	m.telemetry.RecordCacheLookup(c.Request.Context(), otel.SourceGateway, string(provider), model, result)
+}
+
+// cacheControl reports the no-cache and no-store directives of a request's
+// Cache-Control header. no-cache skips the lookup but still refreshes the
+// entry; no-store keeps the response out of the cache.
+func cacheControl(header string) (noCache, noStore bool) {
+	for directive := range strings.SplitSeq(header, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache":
+			noCache = true
+		case "no-store":
+			noStore = true
+		}
+	}
+	return noCache, noStore
+}
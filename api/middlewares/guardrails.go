@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"io"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -20,6 +20,31 @@ import (
 // GuardrailsMiddleware defines the interface for guardrails middleware.
 type GuardrailsMiddleware interface {
 	Middleware() gin.HandlerFunc
+	GuardrailsChecker
+}
+
+// GuardrailsChecker runs the guardrails on chat completions that do not go
+// through the middleware chain, such as the turns of WebSocket sessions.
+type GuardrailsChecker interface {
+	// CheckChatCompletion evaluates a chat completion request (pre_call) or
+	// response (post_call) body as if it were sent to /v1/chat/completions.
+	// It returns the body to use, redacted when the guardrails say so, or a
+	// *GuardrailBlockedError when they block it.
+	CheckChatCompletion(ctx context.Context, phase guardrails.Phase, model string, body []byte) ([]byte, error)
+}
+
+// GuardrailBlockedError is the error of a chat completion blocked by the
+// guardrails, see GuardrailsChecker
+type GuardrailBlockedError struct {
+	Phase   guardrails.Phase
+	Message string
+}
+
+func (e *GuardrailBlockedError) Error() string {
+	if e.Phase == guardrails.PhasePostCall {
+		return "response blocked by guardrails: " + e.Message
+	}
+	return "request blocked by guardrails: " + e.Message
 }
 
 // GuardrailsMiddlewareImpl implements the guardrails middleware.
@@ -27,6 +52,7 @@ type GuardrailsMiddlewareImpl struct {
 	evaluator      *guardrails.Evaluator
 	externalClient *guardrails.ExternalClient
 	detectors      []guardrails.Detector
+	moderation     *guardrails.Moderation
 	logger         logger.Logger
 	telemetry      otel.OpenTelemetry
 	cfg            config.Config
@@ -36,11 +62,13 @@ type GuardrailsMiddlewareImpl struct {
 type NoopGuardrailsMiddlewareImpl struct{}
 
 // NewGuardrailsMiddleware creates a new guardrails middleware instance.
-// Returns a Noop implementation when guardrails are disabled.
+// Returns a Noop implementation when guardrails are disabled. The moderation
+// check is optional.
 func NewGuardrailsMiddleware(
 	evaluator *guardrails.Evaluator,
 	externalClient *guardrails.ExternalClient,
 	detectors []guardrails.Detector,
+	moderation *guardrails.Moderation,
 	log logger.Logger,
 	telemetry otel.OpenTelemetry,
 	cfg config.Config,
@@ -54,6 +82,7 @@ func NewGuardrailsMiddleware(
 		evaluator:      evaluator,
 		externalClient: externalClient,
 		detectors:      detectors,
+		moderation:     moderation,
 		logger:         log,
 		telemetry:      telemetry,
 		cfg:            cfg,
@@ -72,17 +101,20 @@ func (m *GuardrailsMiddlewareImpl) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		path := c.Request.URL.Path
 
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(m.cfg.Server.MaxRequestBodySize))
-		bodyBytes, err := c.GetRawData()
+		body := GetRequestBody(c, m.cfg)
+		bodyBytes, err := body.Bytes()
 		if err != nil {
 			m.logger.Error("guardrails: failed to read request body", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			if errors.Is(err, ErrRequestBodyTooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			}
 			c.Abort()
 			return
 		}
-		c.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 
-		model := extractModel(bodyBytes, path)
+		model, streaming := requestModel(body, path)
 		claims, _ := c.Request.Context().Value(types.ClaimsContextKey).(map[string]any)
 		input := &guardrails.Input{
 			Method: c.Request.Method,
@@ -95,7 +127,7 @@ func (m *GuardrailsMiddlewareImpl) Middleware() gin.HandlerFunc {
 			Identity: claims,
 		}
 
-		dec, err := m.evaluate(c.Request.Context(), input)
+		dec, err := m.evaluate(c.Request.Context(), input, c.GetHeader("Content-Type"))
 		if err != nil {
 			m.logger.Error("guardrails: pre_call evaluation error", err)
 			if m.cfg.Guardrails.FailMode == guardrails.FailModeClosed {
@@ -126,7 +158,7 @@ func (m *GuardrailsMiddlewareImpl) Middleware() gin.HandlerFunc {
 
 		if dec.Action == guardrails.ActionRedact {
 			redacted := guardrails.RedactSensitive(string(bodyBytes), m.detectors)
-			c.Request.Body = http.MaxBytesReader(c.Writer, io.NopCloser(bytes.NewReader([]byte(redacted))), int64(m.cfg.Server.MaxRequestBodySize))
+			body.SetBytes([]byte(redacted))
 			m.logger.Debug("guardrails: request body redacted", "path", path)
 		}
 
@@ -134,7 +166,7 @@ func (m *GuardrailsMiddlewareImpl) Middleware() gin.HandlerFunc {
 			m.telemetry.RecordGuardrail(c.Request.Context(), otel.SourceGateway, string(guardrails.PhasePreCall), dec.Action, path, model)
 		}
 
-		if path == ChatCompletionsPath && !isStreamingRequest(bodyBytes) {
+		if path == ChatCompletionsPath && !streaming {
 			customWriter := &customResponseWriter{
 				ResponseWriter: c.Writer,
 				body:           &bytes.Buffer{},
@@ -162,7 +194,7 @@ func (m *GuardrailsMiddlewareImpl) Middleware() gin.HandlerFunc {
 				Identity: claims,
 			}
 
-			respDec, respErr := m.evaluate(c.Request.Context(), respInput)
+			respDec, respErr := m.evaluate(c.Request.Context(), respInput, customWriter.Header().Get("Content-Type"))
 			if respErr != nil {
 				m.logger.Error("guardrails: post_call evaluation error", respErr)
 				if m.cfg.Guardrails.FailMode == guardrails.FailModeClosed {
@@ -212,8 +244,53 @@ func (m *GuardrailsMiddlewareImpl) Middleware() gin.HandlerFunc {
 	}
 }
 
-// evaluate runs the policy evaluator and external guardrail check.
-func (m *GuardrailsMiddlewareImpl) evaluate(ctx context.Context, input *guardrails.Input) (guardrails.Decision, error) {
+// CheckChatCompletion returns body as it is.
+func (n *NoopGuardrailsMiddlewareImpl) CheckChatCompletion(ctx context.Context, phase guardrails.Phase, model string, body []byte) ([]byte, error) {
+	return body, nil
+}
+
+// CheckChatCompletion runs the guardrails of the middleware on a chat
+// completion body, honoring the fail mode like the middleware does.
+func (m *GuardrailsMiddlewareImpl) CheckChatCompletion(ctx context.Context, phase guardrails.Phase, model string, body []byte) ([]byte, error) {
+	claims, _ := ctx.Value(types.ClaimsContextKey).(map[string]any)
+	input := &guardrails.Input{
+		Method: http.MethodPost,
+		Path:   ChatCompletionsPath,
+		Phase:  phase,
+		Request: &guardrails.Req{
+			Body:  string(body),
+			Model: model,
+		},
+		Identity: claims,
+	}
+
+	dec, err := m.evaluate(ctx, input, "application/json")
+	if err != nil {
+		m.logger.Error("guardrails: evaluation error", err, "phase", phase)
+		if m.cfg.Guardrails.FailMode == guardrails.FailModeClosed {
+			return nil, &GuardrailBlockedError{Phase: phase, Message: "guardrail evaluation failed"}
+		}
+		m.logger.Warn("guardrails: evaluation error, allowing in open mode", "phase", phase, "error", err.Error())
+		return body, nil
+	}
+
+	if m.telemetry != nil {
+		m.telemetry.RecordGuardrail(ctx, otel.SourceGateway, string(phase), dec.Action, ChatCompletionsPath, model)
+	}
+	switch dec.Action {
+	case guardrails.ActionBlock:
+		m.logger.Info("guardrails: chat completion blocked", "phase", phase, "message", dec.Message)
+		return nil, &GuardrailBlockedError{Phase: phase, Message: dec.Message}
+	case guardrails.ActionRedact:
+		return []byte(guardrails.RedactSensitive(string(body), m.detectors)), nil
+	}
+	return body, nil
+}
+
+// evaluate runs the policy evaluator, external guardrail check and moderation
+// check of a body of contentType. Requests to the Moderations API are not
+// moderated themselves, as classifying flagged content is what they are for.
+func (m *GuardrailsMiddlewareImpl) evaluate(ctx context.Context, input *guardrails.Input, contentType string) (guardrails.Decision, error) {
 	dec, err := m.evaluator.Eval(ctx, input)
 	if err != nil {
 		return guardrails.Decision{}, err
@@ -229,29 +306,42 @@ func (m *GuardrailsMiddlewareImpl) evaluate(ctx context.Context, input *guardrai
 		}
 	}
 
+	if m.moderation != nil && dec.Action != guardrails.ActionBlock && input.Path != ModerationsPath && input.Request != nil {
+		modDec, modErr := m.moderation.Check(ctx, input.Phase, contentType, input.Request.Body)
+		if modErr != nil {
+			return guardrails.Decision{}, modErr
+		}
+		if modDec.Action == guardrails.ActionBlock {
+			return modDec, nil
+		}
+	}
+
 	return dec, nil
 }
 
-// extractModel attempts to extract the model name from the request body or path.
-func extractModel(body []byte, path string) string {
-	if path == ChatCompletionsPath || strings.Contains(path, ResponsesPath) {
+// requestModel extracts the model name and whether streaming was requested
+// from the shared request body.
+func requestModel(body *RequestBody, path string) (string, bool) {
+	switch {
+	case path == ChatCompletionsPath:
+		req, err := body.ChatCompletionRequest()
+		if err != nil {
+			return "", false
+		}
+		return req.Model, req.Stream != nil && *req.Stream
+	case strings.Contains(path, ResponsesPath):
+		raw, err := body.Bytes()
+		if err != nil {
+			return "", false
+		}
 		var req struct {
-			Model string `json:"model"`
+			Model  string `json:"model"`
+			Stream *bool  `json:"stream"`
 		}
-		if err := json.Unmarshal(body, &req); err == nil && req.Model != "" {
-			return req.Model
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return "", false
 		}
+		return req.Model, req.Stream != nil && *req.Stream
 	}
-	return ""
-}
-
-// isStreamingRequest checks if the request has stream=true.
-func isStreamingRequest(body []byte) bool {
-	var req struct {
-		Stream *bool `json:"stream"`
-	}
-	if err := json.Unmarshal(body, &req); err == nil && req.Stream != nil && *req.Stream {
-		return true
-	}
-	return false
+	return "", false
 }
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,18 +22,10 @@ import (
 )
 
 const (
-	// MCPBypassHeader marks internal MCP requests to prevent middleware loops
+	// MCPBypassHeader lets clients skip MCP tool injection for a request
 	MCPBypassHeader = "X-MCP-Bypass"
 )
 
-// mcpContextKey is a custom type for context keys to avoid collisions
-type mcpContextKey string
-
-const (
-	// mcpBypassKey is the context key for marking to bypass MCP middleware
-	mcpBypassKey mcpContextKey = MCPBypassHeader
-)
-
 // MCPProviderModelResult contains the result of provider and model determination
 type MCPProviderModelResult struct {
 	Provider      core.IProvider
@@ -97,13 +90,21 @@ func (m *MCPMiddlewareImpl) Middleware() gin.HandlerFunc {
 		}
 
 		m.logger.Debug("mcp middleware invoked", "path", c.Request.URL.Path)
-		var originalRequestBody types.CreateChatCompletionRequest
-		if err := c.ShouldBindJSON(&originalRequestBody); err != nil {
+		body := GetRequestBody(c, m.config)
+		parsed, err := body.ChatCompletionRequest()
+		if err != nil {
 			m.logger.Error("failed to parse request body", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			if errors.Is(err, ErrRequestBodyTooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			}
 			c.Abort()
 			return
 		}
+		// Copy the shared request: the injected tools must not leak into the
+		// view of middlewares that already read it.
+		originalRequestBody := *parsed
 
 		if !m.mcpClient.IsInitialized() {
 			c.Next()
@@ -138,7 +139,7 @@ func (m *MCPMiddlewareImpl) Middleware() gin.HandlerFunc {
 		m.logger.Debug("added mcp tools to request", "tool_count", len(availableTools), "tool_mode", m.config.MCP.ToolMode)
 		originalRequestBody.Tools = &availableTools
 
-		c.Set(string(mcpBypassKey), &originalRequestBody)
+		body.SetChatCompletionRequest(&originalRequestBody)
 
 		result, err := m.getProviderAndModel(c, originalRequestBody.Model)
 		if err != nil {
@@ -255,6 +256,11 @@ func (m *MCPMiddlewareImpl) handleMCPStreamingRequest(c *gin.Context, request *t
 		}
 	}()
 
+	// The agent fails idle provider streams itself, so only keep-alives are
+	// paced here; they also cover the time spent executing tools.
+	watchdog := NewStreamWatchdog(m.config.Server.StreamKeepaliveInterval, 0)
+	defer watchdog.Stop()
+
 	c.Stream(func(w io.Writer) bool {
 		select {
 		case line, ok := <-processedChunk:
@@ -262,10 +268,11 @@ func (m *MCPMiddlewareImpl) handleMCPStreamingRequest(c *gin.Context, request *t
 				m.logger.Debug("mcp agent stream channel closed unexpectedly")
 				return false
 			}
+			watchdog.Sent()
 
 			ResetWriteDeadline(c, m.config.Server.WriteTimeout)
 
-			if bytes.Equal(line, []byte("data: [DONE]\n\n")) {
+			if bytes.Equal(line, core.StreamDone) {
 				m.logger.Debug("mcp agent completed all iterations, sending [DONE]")
 				_, err := w.Write(line)
 				if err != nil {
@@ -277,11 +284,9 @@ func (m *MCPMiddlewareImpl) handleMCPStreamingRequest(c *gin.Context, request *t
 			m.logger.Debug("processed chunk", "line", string(line))
 
 			if strings.HasPrefix(string(line), "data: {") && strings.Contains(string(line), "\"error\"") {
-				var errMsg struct {
-					Error string `json:"error"`
-				}
+				var errMsg core.OpenAIErrorBody
 				if err := json.Unmarshal(line[6:], &errMsg); err == nil {
-					m.logger.Error("upstream provider error", fmt.Errorf("%s", errMsg.Error))
+					m.logger.Error("upstream provider error", errors.New(errMsg.Error.Message), "code", errMsg.Error.Code)
 					c.Writer.WriteHeader(http.StatusServiceUnavailable)
 				}
 			}
@@ -292,10 +297,18 @@ func (m *MCPMiddlewareImpl) handleMCPStreamingRequest(c *gin.Context, request *t
 				return false
 			}
 			return true
+		case <-watchdog.KeepAlive():
+			ResetWriteDeadline(c, m.config.Server.WriteTimeout)
+			if err := WriteKeepAlive(w); err != nil {
+				m.logger.Error("failed to write keep-alive", err)
+				return false
+			}
+			watchdog.Sent()
+			return true
 		case err := <-errCh:
 			m.logger.Error("mcp agent streaming error", err)
 			c.Writer.WriteHeader(http.StatusServiceUnavailable)
-			if _, writeErr := fmt.Fprintf(w, "data: {\"error\": \"%s\"}\n\n", err.Error()); writeErr != nil {
+			if _, writeErr := w.Write(core.StreamEvent{Err: err}.SSE()); writeErr != nil {
 				m.logger.Error("failed to write error to stream", writeErr)
 			}
 			return false
@@ -0,0 +1,160 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	gin "github.com/gin-gonic/gin"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// requestBodyKey is the gin context key holding the shared *RequestBody
+const requestBodyKey = "inference-gateway.request-body"
+
+// ErrRequestBodyTooLarge is returned by RequestBody when the body exceeds the
+// configured server limit.
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// RequestBody is the request body of a single request, read at most once and
+// shared by every middleware and handler. The raw bytes are read on first use
+// and the chat completion request is decoded lazily, so requests that never
+// look at their body (e.g. GET /v1/models) pay nothing.
+//
+// Consumers must treat the returned values as read-only. A consumer that
+// changes the request (guardrail redaction, MCP tool injection) installs the
+// new version with SetBytes or SetChatCompletionRequest instead.
+type RequestBody struct {
+	c        *gin.Context
+	maxBytes int
+
+	readOnce sync.Once
+	raw      []byte
+	readErr  error
+
+	mu      sync.Mutex
+	decoded bool
+	chat    *types.CreateChatCompletionRequest
+	chatErr error
+}
+
+// RequestBodyMiddleware installs the shared RequestBody for every request
+type RequestBodyMiddleware interface {
+	Middleware() gin.HandlerFunc
+}
+
+type RequestBodyMiddlewareImpl struct {
+	maxBytes int
+}
+
+// NewRequestBodyMiddleware creates the request decoding stage. It should run
+// before any middleware that inspects the request body.
+func NewRequestBodyMiddleware(cfg config.Config) RequestBodyMiddleware {
+	return &RequestBodyMiddlewareImpl{maxBytes: cfg.Server.ResolveMaxRequestBodySize()}
+}
+
+// Middleware returns the request body middleware handler
+func (m *RequestBodyMiddlewareImpl) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(requestBodyKey, &RequestBody{c: c, maxBytes: m.maxBytes})
+		c.Next()
+	}
+}
+
+// GetRequestBody returns the shared request body of c. When the request body
+// middleware is not installed (e.g. a handler mounted on its own in tests), a
+// RequestBody limited by cfg is created and stored on first use.
+func GetRequestBody(c *gin.Context, cfg config.Config) *RequestBody {
+	if v, ok := c.Get(requestBodyKey); ok {
+		if body, ok := v.(*RequestBody); ok {
+			return body
+		}
+	}
+	body := &RequestBody{c: c, maxBytes: cfg.Server.ResolveMaxRequestBodySize()}
+	c.Set(requestBodyKey, body)
+	return body
+}
+
+// Bytes returns the raw request body. After the first read the request's Body
+// is replaced with a reader over the buffered bytes so handlers that stream
+// the body upstream (e.g. /proxy) still see it.
+func (b *RequestBody) Bytes() ([]byte, error) {
+	b.readOnce.Do(func() {
+		body := b.c.Request.Body
+		if body == nil || body == http.NoBody {
+			return
+		}
+		raw, err := io.ReadAll(io.LimitReader(body, int64(b.maxBytes)+1))
+		_ = body.Close()
+		if err != nil {
+			b.readErr = err
+			return
+		}
+		if len(raw) > b.maxBytes {
+			b.readErr = ErrRequestBodyTooLarge
+			return
+		}
+		b.raw = raw
+	})
+	if b.readErr != nil {
+		return nil, b.readErr
+	}
+
+	b.mu.Lock()
+	raw := b.raw
+	b.mu.Unlock()
+	b.rewind(raw)
+	return raw, nil
+}
+
+// ChatCompletionRequest returns the body decoded as a chat completion request.
+// The result is decoded once and shared; callers must not modify it.
+func (b *RequestBody) ChatCompletionRequest() (*types.CreateChatCompletionRequest, error) {
+	raw, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.decoded {
+		var req types.CreateChatCompletionRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			b.chatErr = err
+		} else {
+			b.chat = &req
+		}
+		b.decoded = true
+	}
+	return b.chat, b.chatErr
+}
+
+// SetBytes replaces the raw body, e.g. with a redacted version, and discards
+// any previously decoded request.
+func (b *RequestBody) SetBytes(raw []byte) {
+	b.readOnce.Do(func() {})
+	b.mu.Lock()
+	b.raw, b.readErr = raw, nil
+	b.decoded, b.chat, b.chatErr = false, nil, nil
+	b.mu.Unlock()
+	b.rewind(raw)
+}
+
+// SetChatCompletionRequest replaces the decoded chat completion request seen
+// by downstream consumers without re-encoding the raw body.
+func (b *RequestBody) SetChatCompletionRequest(req *types.CreateChatCompletionRequest) {
+	b.mu.Lock()
+	b.decoded, b.chat, b.chatErr = true, req, nil
+	b.mu.Unlock()
+}
+
+// rewind points the current request's Body at raw. Middlewares may replace
+// c.Request (e.g. WithContext), so the request is looked up on every call.
+func (b *RequestBody) rewind(raw []byte) {
+	b.c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+}
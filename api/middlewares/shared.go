@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"bytes"
+	"io"
 	"net/http"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 const (
 	ChatCompletionsPath = "/v1/chat/completions"
 	ResponsesPath       = "/v1/responses"
+	ModerationsPath     = "/v1/moderations"
 )
 
 // SetSSEHeaders sets the response headers required for server-sent event streaming
@@ -36,6 +38,89 @@ func resetWriteDeadline(w http.ResponseWriter, d time.Duration) {
 	_ = http.NewResponseController(w).SetWriteDeadline(deadline)
 }
 
+// KeepAliveComment is the server-sent event comment written on quiet streams
+// so that proxies do not drop the idle connection. Clients ignore comments.
+var KeepAliveComment = []byte(": keep-alive\n\n")
+
+// StreamWatchdog paces a server-sent event stream. KeepAlive fires once
+// nothing was written to the client for the keep-alive interval, Idle once
+// nothing was received from the upstream for the idle timeout. A zero
+// duration disables the matching timer, whose channel then never fires.
+type StreamWatchdog struct {
+	keepAliveInterval time.Duration
+	idleTimeout       time.Duration
+	keepAlive         *time.Timer
+	idle              *time.Timer
+}
+
+// NewStreamWatchdog starts a watchdog; call Stop once the stream ends
+func NewStreamWatchdog(keepAliveInterval, idleTimeout time.Duration) *StreamWatchdog {
+	w := &StreamWatchdog{keepAliveInterval: keepAliveInterval, idleTimeout: idleTimeout}
+	if keepAliveInterval > 0 {
+		w.keepAlive = time.NewTimer(keepAliveInterval)
+	}
+	if idleTimeout > 0 {
+		w.idle = time.NewTimer(idleTimeout)
+	}
+	return w
+}
+
+// KeepAlive fires when a keep-alive comment is due
+func (w *StreamWatchdog) KeepAlive() <-chan time.Time {
+	if w.keepAlive == nil {
+		return nil
+	}
+	return w.keepAlive.C
+}
+
+// Idle fires when the upstream has been silent for the idle timeout
+func (w *StreamWatchdog) Idle() <-chan time.Time {
+	if w.idle == nil {
+		return nil
+	}
+	return w.idle.C
+}
+
+// IdleTimeout is the idle timeout of the watchdog
+func (w *StreamWatchdog) IdleTimeout() time.Duration {
+	return w.idleTimeout
+}
+
+// Received records data received from the upstream
+func (w *StreamWatchdog) Received() {
+	if w.idle != nil {
+		w.idle.Reset(w.idleTimeout)
+	}
+}
+
+// Sent records data written to the client
+func (w *StreamWatchdog) Sent() {
+	if w.keepAlive != nil {
+		w.keepAlive.Reset(w.keepAliveInterval)
+	}
+}
+
+// Stop releases the timers of the watchdog
+func (w *StreamWatchdog) Stop() {
+	if w.keepAlive != nil {
+		w.keepAlive.Stop()
+	}
+	if w.idle != nil {
+		w.idle.Stop()
+	}
+}
+
+// WriteKeepAlive writes and flushes a keep-alive comment
+func WriteKeepAlive(w io.Writer) error {
+	if _, err := w.Write(KeepAliveComment); err != nil {
+		return err
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
 // DeadlineResetWriter resets the write deadline before every write so that
 // proxied streaming responses are not cut off by the server's write timeout.
 // Wrap the writer handed to httputil.ReverseProxy, which offers no per-write hook.
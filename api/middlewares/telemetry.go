@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"io"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,6 +18,7 @@ import (
 	config "github.com/inference-gateway/inference-gateway/config"
 	logger "github.com/inference-gateway/inference-gateway/logger"
 	otel "github.com/inference-gateway/inference-gateway/otel"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	registry "github.com/inference-gateway/inference-gateway/providers/registry"
 	routing "github.com/inference-gateway/inference-gateway/providers/routing"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
@@ -41,10 +42,7 @@ func NewTelemetryMiddleware(cfg config.Config, telemetry otel.OpenTelemetry, log
 	}, nil
 }
 
-const (
-	maxCapturedResponseBytes = 1 << 20
-	maxTelemetryRequestBytes = 32 << 20
-)
+const maxCapturedResponseBytes = 1 << 20
 
 // responseBodyWriter is a wrapper for the response writer that captures the body
 type responseBodyWriter struct {
@@ -82,21 +80,21 @@ func (t *TelemetryImpl) Middleware() gin.HandlerFunc {
 			return
 		}
 
-		var requestBody types.CreateChatCompletionRequest
-		bodyBytes, err := io.ReadAll(io.LimitReader(c.Request.Body, maxTelemetryRequestBytes+1))
-		if err != nil {
-			t.logger.Error("failed to read request body", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		body := GetRequestBody(c, t.cfg)
+		if _, err := body.Bytes(); err != nil {
+			if errors.Is(err, ErrRequestBodyTooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			} else {
+				t.logger.Error("failed to read request body", err)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			}
 			c.Abort()
 			return
 		}
-		if len(bodyBytes) > maxTelemetryRequestBytes {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
-			c.Abort()
-			return
+		var requestBody types.CreateChatCompletionRequest
+		if parsed, err := body.ChatCompletionRequest(); err == nil {
+			requestBody = *parsed
 		}
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		_ = json.Unmarshal(bodyBytes, &requestBody)
 		model := requestBody.Model
 
 		provider := "unknown"
@@ -108,11 +106,22 @@ func (t *TelemetryImpl) Middleware() gin.HandlerFunc {
 			}
 		}
 
-		w := &responseBodyWriter{
-			ResponseWriter: c.Writer,
-			body:           &bytes.Buffer{},
+		// Streamed completions are recorded from their typed events, other
+		// responses from the captured body
+		streaming := requestBody.Stream != nil && *requestBody.Stream
+		var w *responseBodyWriter
+		var streamUsage *core.StreamUsage
+		if streaming {
+			var ctx context.Context
+			ctx, streamUsage = core.WithStreamUsage(c.Request.Context())
+			c.Request = c.Request.WithContext(ctx)
+		} else {
+			w = &responseBodyWriter{
+				ResponseWriter: c.Writer,
+				body:           &bytes.Buffer{},
+			}
+			c.Writer = w
 		}
-		c.Writer = w
 
 		c.Next()
 
@@ -146,7 +155,17 @@ func (t *TelemetryImpl) Middleware() gin.HandlerFunc {
 		team := otel.TeamUnknown
 		t.telemetry.RecordRequestDuration(c.Request.Context(), otel.SourceGateway, team, provider, model, errorType, duration)
 
-		respData := t.parseResponseData(w.body.Bytes(), requestBody.Stream != nil && *requestBody.Stream, provider, model)
+		// Cached responses did not reach the provider and used no tokens
+		if status := c.Writer.Header().Get(CacheStatusHeader); status == CacheStatusHit || status == CacheStatusSemanticHit {
+			return
+		}
+
+		var respData *responseData
+		if streaming {
+			respData = streamResponseData(streamUsage)
+		} else {
+			respData = t.parseResponseData(w.body.Bytes(), provider, model)
+		}
 
 		promptTokens := respData.PromptTokens
 		completionTokens := respData.CompletionTokens
@@ -178,56 +197,24 @@ func (t *TelemetryImpl) Middleware() gin.HandlerFunc {
 	}
 }
 
-// parseResponseData extracts all needed information from response in a single pass
-func (t *TelemetryImpl) parseResponseData(responseBytes []byte, isStreaming bool, provider, model string) *responseData {
-	data := &responseData{}
-
-	if isStreaming {
-		data.ToolCalls = t.parseStreamingResponse(responseBytes, &data.PromptTokens, &data.CompletionTokens, &data.TotalTokens, provider, model)
-	} else {
-		data.ToolCalls = t.parseNonStreamingResponse(responseBytes, &data.PromptTokens, &data.CompletionTokens, &data.TotalTokens, provider, model)
+// streamResponseData returns the usage and tool calls collected from the
+// typed events of a streamed completion
+func streamResponseData(usage *core.StreamUsage) *responseData {
+	data := &responseData{ToolCalls: usage.ToolCalls()}
+	if total, ok := usage.Usage(); ok {
+		data.PromptTokens = total.PromptTokens
+		data.CompletionTokens = total.CompletionTokens
+		data.TotalTokens = total.TotalTokens
 	}
-
 	return data
 }
 
-// parseStreamingResponse handles streaming response parsing for both tokens and tool calls
-func (t *TelemetryImpl) parseStreamingResponse(responseBytes []byte, promptTokens, completionTokens, totalTokens *int64, provider, model string) []types.ChatCompletionMessageToolCall {
-	responseStr := string(responseBytes)
-	chunks := strings.Split(responseStr, "\n\n")
-
-	usageChunks := chunks
-	if len(chunks) > 4 {
-		usageChunks = chunks[len(chunks)-4:]
-	}
-
-	for _, chunk := range usageChunks {
-		if chunk == "" || !strings.HasPrefix(chunk, "data: ") {
-			continue
-		}
-
-		chunk = strings.TrimPrefix(chunk, "data: ")
-		if chunk == "[DONE]" {
-			continue
-		}
-
-		var streamResponse types.CreateChatCompletionStreamResponse
-		if err := json.Unmarshal([]byte(chunk), &streamResponse); err != nil {
-			t.logger.Error("failed to unmarshal streaming response chunk", err,
-				"provider", provider,
-				"model", model,
-				"chunk_length", len(chunk))
-			continue
-		}
-
-		if streamResponse.Usage != nil {
-			*promptTokens = streamResponse.Usage.PromptTokens
-			*completionTokens = streamResponse.Usage.CompletionTokens
-			*totalTokens = streamResponse.Usage.TotalTokens
-		}
-	}
-
-	return types.AccumulateStreamingToolCalls(responseStr)
+// parseResponseData extracts all needed information from a non-streaming
+// response in a single pass
+func (t *TelemetryImpl) parseResponseData(responseBytes []byte, provider, model string) *responseData {
+	data := &responseData{}
+	data.ToolCalls = t.parseNonStreamingResponse(responseBytes, &data.PromptTokens, &data.CompletionTokens, &data.TotalTokens, provider, model)
+	return data
 }
 
 // parseNonStreamingResponse handles non-streaming response parsing for both tokens and tool calls
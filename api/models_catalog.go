@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	l "github.com/inference-gateway/inference-gateway/logger"
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// modelCatalog caches the model list of each provider for GET /v1/models,
+// including the pricing and runtime context windows resolved for it. A list
+// older than ttl is stale: it is still served while a background refresh
+// replaces it, so only a provider that has never listed successfully (or an
+// explicit refresh) makes a request wait on the upstream call.
+type modelCatalog struct {
+	registry registry.ProviderRegistry
+	client   client.Client
+	logger   l.Logger
+	ttl      time.Duration
+	timeout  time.Duration
+	// enrich resolves per-model metadata that needs extra upstream calls
+	enrich func(ctx context.Context, models []types.Model)
+	now    func() time.Time
+
+	mu         sync.Mutex
+	entries    map[types.Provider]*catalogEntry
+	generation uint64 // bumped by invalidate
+}
+
+type catalogEntry struct {
+	models      []types.Model
+	lastSuccess time.Time
+	err         error
+	// invalid marks a list fetched with a configuration that was since
+	// reloaded
+	invalid bool
+	// refreshing is closed when the in-flight refresh finishes; nil when idle
+	refreshing chan struct{}
+}
+
+func newModelCatalog(providerRegistry registry.ProviderRegistry, httpClient client.Client, logger l.Logger, ttl, timeout time.Duration, enrich func(context.Context, []types.Model)) *modelCatalog {
+	return &modelCatalog{
+		registry: providerRegistry,
+		client:   httpClient,
+		logger:   logger,
+		ttl:      ttl,
+		timeout:  timeout,
+		enrich:   enrich,
+		now:      time.Now,
+		entries:  make(map[types.Provider]*catalogEntry),
+	}
+}
+
+// run refreshes every configured provider now and then every ttl until ctx
+// is done
+func (mc *modelCatalog) run(ctx context.Context) {
+	ticker := time.NewTicker(mc.ttl)
+	defer ticker.Stop()
+
+	for {
+		mc.mu.Lock()
+		for id := range mc.registry.GetProviders() {
+			mc.refreshLocked(id)
+		}
+		mc.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// invalidate marks every cached list stale, e.g. after the provider
+// configuration was reloaded
+func (mc *modelCatalog) invalidate() {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.generation++
+	for _, entry := range mc.entries {
+		entry.invalid = true
+	}
+}
+
+// list returns the cached models of the given providers and the freshness of
+// each list. Providers without a list yet, or all of them when force is set,
+// are fetched before returning unless ctx is done first; stale lists are
+// returned as they are and refreshed in the background.
+func (mc *modelCatalog) list(ctx context.Context, ids []types.Provider, force bool) ([]types.Model, []types.ProviderModelsStatus) {
+	var pending []chan struct{}
+	mc.mu.Lock()
+	for _, id := range ids {
+		entry := mc.entry(id)
+		switch {
+		case force || entry.lastSuccess.IsZero():
+			pending = append(pending, mc.refreshLocked(id))
+		case mc.staleLocked(entry):
+			mc.refreshLocked(id)
+		}
+	}
+	mc.mu.Unlock()
+
+	for _, done := range pending {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	models := make([]types.Model, 0)
+	statuses := make([]types.ProviderModelsStatus, 0, len(ids))
+	for _, id := range ids {
+		entry := mc.entry(id)
+		status := types.ProviderModelsStatus{
+			Provider: id,
+			Stale:    mc.staleLocked(entry),
+		}
+		if !entry.lastSuccess.IsZero() {
+			lastSuccess := entry.lastSuccess
+			status.LastSuccess = &lastSuccess
+			models = append(models, entry.models...)
+		}
+		if entry.err != nil {
+			msg := entry.err.Error()
+			status.Error = &msg
+		}
+		statuses = append(statuses, status)
+	}
+	// Callers own the returned models; the cached lists are never modified
+	return slices.Clone(models), statuses
+}
+
+// contextWindow returns the context window of a model from the last
+// successful listing of its provider, without refreshing it
+func (mc *modelCatalog) contextWindow(provider types.Provider, model string) (int, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	entry, ok := mc.entries[provider]
+	if !ok {
+		return 0, false
+	}
+	id := string(provider) + "/" + model
+	for _, m := range entry.models {
+		if m.ID == id && m.ContextWindow != nil && m.ContextWindow.Tokens > 0 {
+			return m.ContextWindow.Tokens, true
+		}
+	}
+	return 0, false
+}
+
+// providers returns the IDs of the configured providers in a stable order
+func (mc *modelCatalog) providers() []types.Provider {
+	return slices.Sorted(maps.Keys(mc.registry.GetProviders()))
+}
+
+func (mc *modelCatalog) entry(id types.Provider) *catalogEntry {
+	entry, ok := mc.entries[id]
+	if !ok {
+		entry = &catalogEntry{}
+		mc.entries[id] = entry
+	}
+	return entry
+}
+
+func (mc *modelCatalog) staleLocked(entry *catalogEntry) bool {
+	return entry.invalid || entry.lastSuccess.IsZero() || mc.now().Sub(entry.lastSuccess) >= mc.ttl
+}
+
+// refreshLocked starts fetching the models of a provider unless a fetch is
+// already in flight, and returns a channel closed once it finishes. The fetch
+// is not tied to any request so a client giving up does not waste it.
+func (mc *modelCatalog) refreshLocked(id types.Provider) chan struct{} {
+	entry := mc.entry(id)
+	if entry.refreshing != nil {
+		return entry.refreshing
+	}
+	done := make(chan struct{})
+	entry.refreshing = done
+	generation := mc.generation
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
+		defer cancel()
+		models, err := mc.fetch(ctx, id)
+
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+		if err != nil {
+			mc.logger.Warn("failed to refresh models", "provider", id, "error", err.Error())
+			entry.err = err
+		} else {
+			entry.models = models
+			entry.lastSuccess = mc.now()
+			entry.err = nil
+			entry.invalid = entry.invalid && generation != mc.generation
+		}
+		entry.refreshing = nil
+		close(done)
+	}()
+	return done
+}
+
+func (mc *modelCatalog) fetch(ctx context.Context, id types.Provider) ([]types.Model, error) {
+	provider, err := mc.registry.BuildProvider(id, mc.client)
+	if err != nil {
+		return nil, err
+	}
+	response, err := provider.ListModels(ctx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errors.New("request timed out")
+		}
+		return nil, err
+	}
+	if response.Data == nil {
+		response.Data = make([]types.Model, 0)
+	}
+	if mc.enrich != nil {
+		mc.enrich(ctx, response.Data)
+	}
+	return response.Data, nil
+}
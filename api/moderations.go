@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gin "github.com/gin-gonic/gin"
+	otelapi "go.opentelemetry.io/otel"
+	codes "go.opentelemetry.io/otel/codes"
+	propagation "go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	trace "go.opentelemetry.io/otel/trace"
+
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// ModerationsHandler implements an OpenAI-compatible POST /v1/moderations
+// endpoint: https://platform.openai.com/docs/api-reference/moderations/create
+//
+// The request body is forwarded byte-for-byte (only the `model` field is
+// rewritten when the provider prefix is stripped) to providers with a
+// Moderations API. The same backend can classify every request and response
+// as a guardrail, see GUARDRAILS_MODERATION_ENABLED.
+func (router *RouterImpl) ModerationsHandler(c *gin.Context) {
+	maxBodySize := router.cfg.Server.ResolveMaxRequestBodySize()
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)))
+	if err != nil {
+		router.logger.Error("failed to read request body", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to read request"))
+		return
+	}
+	if len(body) >= maxBodySize {
+		upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
+		return
+	}
+
+	var req struct {
+		Model string          `json:"model"`
+		Input json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		router.logger.Error("failed to decode request", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+		return
+	}
+	if len(req.Input) == 0 || string(req.Input) == "null" {
+		upstreamError(c, requestError(http.StatusBadRequest, "The 'input' field is required."))
+		return
+	}
+
+	originalModel := req.Model
+	model := req.Model
+	providerID := types.Provider(c.Query("provider"))
+	if providerID == "" && model != "" {
+		var providerPtr *types.Provider
+		providerPtr, model = routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/omni-moderation-latest)."))
+			return
+		}
+		providerID = *providerPtr
+	}
+	if providerID == "" {
+		router.logger.Error("no provider specified for moderations request", nil)
+		upstreamError(c, requestError(http.StatusBadRequest, "No provider specified. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/omni-moderation-latest)."))
+		return
+	}
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(
+		semconv.GenAIProviderNameKey.String(string(providerID)),
+		semconv.GenAIRequestModel(originalModel),
+	)
+
+	if reason := router.modelDenied(originalModel); reason != "" {
+		upstreamError(c, deniedError(reason))
+		return
+	}
+
+	provider, err := router.registry.BuildProvider(providerID, router.client)
+	if err != nil {
+		if strings.Contains(err.Error(), "token not configured") {
+			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
+			return
+		}
+		router.logger.Error("provider not found or not supported", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
+		return
+	}
+
+	endpoint := provider.GetEndpoints().Moderations
+	if endpoint == nil || *endpoint == "" {
+		router.logger.Error("api not supported by provider", nil, "api", "Moderations", "provider", providerID)
+		upstreamError(c, requestError(http.StatusBadRequest, "The Moderations API is not supported by this provider yet."))
+		return
+	}
+
+	if model != originalModel {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var payload map[string]any
+		if err := dec.Decode(&payload); err != nil {
+			router.logger.Error("failed to decode request", err)
+			upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+			return
+		}
+		payload["model"] = model
+		if body, err = json.Marshal(payload); err != nil {
+			router.logger.Error("failed to encode request", err)
+			upstreamError(c, serverError(http.StatusInternalServerError, "Failed to encode request"))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
+	defer cancel()
+
+	upstreamURL := strings.TrimSuffix(provider.GetURL(), "/") + *endpoint
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	upstreamReq.Header.Set("Accept", "application/json")
+
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
+		router.logger.Error("unsupported auth type", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
+		return
+	}
+
+	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(upstreamReq.Header))
+
+	resp, err := router.client.Do(upstreamReq)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			router.logger.Error("request timed out", err, "provider", providerID)
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
+		}
+		upstreamError(c, transportError(ctx, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+	}
+
+	c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, relayHeaders(resp))
+}
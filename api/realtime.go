@@ -0,0 +1,393 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	websocket "github.com/gorilla/websocket"
+	otelapi "go.opentelemetry.io/otel"
+	propagation "go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	trace "go.opentelemetry.io/otel/trace"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// openAIKeyProtocol prefixes the API key browsers offer OpenAI as a
+// subprotocol; the gateway authenticates upstream with its own key
+const openAIKeyProtocol = "openai-insecure-api-key."
+
+// realtimeSession is the state of a relayed Realtime API session
+type realtimeSession struct {
+	provider types.Provider
+	started  time.Time
+
+	mu    sync.Mutex
+	model string
+}
+
+func (s *realtimeSession) currentModel() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.model
+}
+
+// realtimeErrorEvent is the error event of the Realtime API
+type realtimeErrorEvent struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Param   string `json:"param,omitempty"`
+		EventID string `json:"event_id,omitempty"`
+	} `json:"error"`
+}
+
+// RealtimeHandler implements a relay of the OpenAI Realtime API at GET
+// /v1/realtime: https://platform.openai.com/docs/api-reference/realtime
+//
+// The client authenticates to the gateway on the upgrade request like on any
+// other request; the gateway then connects to the provider with the provider
+// key and relays the events of the session both ways, untouched except for
+// the model of session.update events, which is checked against the model
+// allow-lists and stripped of its provider prefix. Session durations and the
+// token usage of response.done events are recorded in telemetry.
+//
+// Only providers that natively implement the Realtime API are supported
+// (currently OpenAI).
+func (router *RouterImpl) RealtimeHandler(c *gin.Context) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		upstreamError(c, requestError(http.StatusBadRequest, "Expected a WebSocket upgrade request"))
+		return
+	}
+	if !router.checkWebSocketOrigin(c.Request) {
+		upstreamError(c, requestError(http.StatusForbidden, "Origin not allowed"))
+		return
+	}
+
+	originalModel := c.Query("model")
+	if originalModel == "" {
+		upstreamError(c, requestError(http.StatusBadRequest, "The model query parameter is required"))
+		return
+	}
+	model := originalModel
+	providerID := types.Provider(c.Query("provider"))
+	if providerID == "" {
+		var providerPtr *types.Provider
+		providerPtr, model = routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-realtime)."))
+			return
+		}
+		providerID = *providerPtr
+	}
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(
+		semconv.GenAIProviderNameKey.String(string(providerID)),
+		semconv.GenAIRequestModel(originalModel),
+	)
+
+	if reason := router.modelDenied(string(providerID) + "/" + model); reason != "" {
+		upstreamError(c, deniedError(reason))
+		return
+	}
+
+	if providerID != constants.OpenaiID {
+		router.logger.Error("realtime api not supported by provider", nil, "provider", providerID)
+		upstreamError(c, requestError(http.StatusBadRequest, "The Realtime API is not supported by this provider yet."))
+		return
+	}
+
+	provider, err := router.registry.BuildProvider(providerID, router.client)
+	if err != nil {
+		if strings.Contains(err.Error(), "token not configured") {
+			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
+			return
+		}
+		router.logger.Error("provider not found or not supported", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
+		return
+	}
+
+	query := c.Request.URL.Query()
+	query.Del("provider")
+	query.Set("model", model)
+	upstreamURL, err := core.BuildURL(provider, "/realtime", query.Encode())
+	if err != nil {
+		router.logger.Error("failed to build upstream url", err, "provider", providerID)
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+		return
+	}
+	upstreamURL.Scheme = strings.Replace(upstreamURL.Scheme, "http", "ws", 1)
+
+	ctx := c.Request.Context()
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodGet, upstreamURL.String(), nil)
+	if err != nil {
+		router.logger.Error("failed to create upstream request", err, "url", upstreamURL.String())
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+		return
+	}
+	if beta := c.GetHeader("OpenAI-Beta"); beta != "" {
+		upstreamReq.Header.Set("OpenAI-Beta", beta)
+	}
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
+		router.logger.Error("unsupported auth type", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
+		return
+	}
+	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(upstreamReq.Header))
+
+	dialer := *router.dialer
+	dialer.Subprotocols = realtimeSubprotocols(c.Request)
+	upstream, resp, err := dialer.DialContext(ctx, upstreamReq.URL.String(), upstreamReq.Header)
+	if err != nil {
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			router.logger.Error("upstream rejected realtime session", err, "provider", providerID, "status", resp.StatusCode)
+			headers := core.UpstreamHeaders(resp.Header)
+			upstreamError(c, &core.HTTPError{
+				StatusCode: resp.StatusCode,
+				Message:    string(body),
+				RetryAfter: headers["Retry-After"],
+				RequestID:  headers[core.UpstreamRequestIDHeader],
+			})
+			return
+		}
+		router.logger.Error("failed to reach upstream server", err, "url", upstreamURL.Host)
+		upstreamError(c, transportError(ctx, err))
+		return
+	}
+	defer upstream.Close()
+
+	upgrader := websocket.Upgrader{
+		// The origin was checked above
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	if protocol := upstream.Subprotocol(); protocol != "" {
+		upgrader.Subprotocols = []string{protocol}
+	}
+	conn, err := router.upgradeWebSocket(c, upgrader)
+	if err != nil {
+		router.logger.Error("failed to upgrade websocket", err)
+		return
+	}
+	defer conn.Close()
+
+	session := &realtimeSession{provider: providerID, model: model, started: time.Now()}
+	router.logger.Info("realtime session started", "provider", providerID, "model", model)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	client := &wsConn{conn: conn, writeTimeout: router.cfg.Server.WriteTimeout}
+	if interval := router.cfg.Server.StreamKeepaliveInterval; interval > 0 {
+		go router.pingWebSocket(ctx, client, interval)
+	}
+
+	errCh := make(chan error, 2)
+	go func() { errCh <- router.relayRealtimeClient(client, upstream, session) }()
+	go func() { errCh <- router.relayRealtimeUpstream(ctx, upstream, client, session) }()
+
+	// Whichever side closes first, the other one is closed with its status
+	code, text, errorType := realtimeCloseStatus(<-errCh)
+	deadline := time.Now().Add(time.Second)
+	closeMessage := websocket.FormatCloseMessage(code, text)
+	_ = conn.WriteControl(websocket.CloseMessage, closeMessage, deadline)
+	_ = upstream.WriteControl(websocket.CloseMessage, closeMessage, deadline)
+	conn.Close()
+	upstream.Close()
+	<-errCh
+
+	duration := time.Since(session.started).Seconds()
+	router.logger.Info("realtime session closed", "provider", providerID, "model", session.currentModel(), "duration_seconds", duration, "close_code", code)
+	if router.telemetry != nil {
+		router.telemetry.RecordRealtimeSession(context.WithoutCancel(ctx), otel.SourceGateway, otel.TeamUnknown, string(providerID), session.currentModel(), errorType, duration)
+	}
+}
+
+// relayRealtimeClient relays the events of the client to the provider until
+// either connection fails
+func (router *RouterImpl) relayRealtimeClient(client *wsConn, upstream *websocket.Conn, session *realtimeSession) error {
+	for {
+		messageType, data, err := client.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if messageType == websocket.TextMessage {
+			var rejection *realtimeErrorEvent
+			data, rejection = router.checkRealtimeEvent(data, session)
+			if rejection != nil {
+				event, err := json.Marshal(rejection)
+				if err != nil {
+					return err
+				}
+				if err := client.write(websocket.TextMessage, event); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if router.cfg.Server.WriteTimeout > 0 {
+			_ = upstream.SetWriteDeadline(time.Now().Add(router.cfg.Server.WriteTimeout))
+		}
+		if err := upstream.WriteMessage(messageType, data); err != nil {
+			return err
+		}
+	}
+}
+
+// relayRealtimeUpstream relays the events of the provider to the client until
+// either connection fails, recording the token usage of every response
+func (router *RouterImpl) relayRealtimeUpstream(ctx context.Context, upstream *websocket.Conn, client *wsConn, session *realtimeSession) error {
+	for {
+		messageType, data, err := upstream.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if messageType == websocket.TextMessage {
+			router.recordRealtimeUsage(ctx, data, session)
+		}
+		if err := client.write(messageType, data); err != nil {
+			return err
+		}
+	}
+}
+
+// checkRealtimeEvent checks the model a session.update event switches the
+// session to against the model allow-lists, and strips its provider prefix.
+// Other events are returned as they are.
+func (router *RouterImpl) checkRealtimeEvent(data []byte, session *realtimeSession) ([]byte, *realtimeErrorEvent) {
+	var event struct {
+		Type    string `json:"type"`
+		EventID string `json:"event_id"`
+		Session struct {
+			Model string `json:"model"`
+		} `json:"session"`
+	}
+	if json.Unmarshal(data, &event) != nil || event.Type != "session.update" || event.Session.Model == "" {
+		return data, nil
+	}
+
+	reject := func(message string) ([]byte, *realtimeErrorEvent) {
+		rejection := &realtimeErrorEvent{Type: "error"}
+		rejection.Error.Type = "invalid_request_error"
+		rejection.Error.Code = "model_not_allowed"
+		rejection.Error.Message = message
+		rejection.Error.Param = "session.model"
+		rejection.Error.EventID = event.EventID
+		return nil, rejection
+	}
+
+	model := event.Session.Model
+	if providerPtr, providerModel := routing.DetermineProviderAndModelName(model); providerPtr != nil {
+		if *providerPtr != session.provider {
+			return reject("The model of a Realtime session cannot switch providers.")
+		}
+		model = providerModel
+	}
+	if reason := router.modelDenied(string(session.provider) + "/" + model); reason != "" {
+		return reject(reason)
+	}
+
+	if model != event.Session.Model {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var payload map[string]any
+		if err := dec.Decode(&payload); err != nil {
+			return data, nil
+		}
+		if sessionPayload, ok := payload["session"].(map[string]any); ok {
+			sessionPayload["model"] = model
+		}
+		rewritten, err := json.Marshal(payload)
+		if err != nil {
+			return data, nil
+		}
+		data = rewritten
+	}
+
+	session.mu.Lock()
+	session.model = model
+	session.mu.Unlock()
+	return data, nil
+}
+
+// recordRealtimeUsage records the token usage of a response.done event
+func (router *RouterImpl) recordRealtimeUsage(ctx context.Context, data []byte, session *realtimeSession) {
+	// Most events are audio deltas; only decode the ones that may be usage
+	if !bytes.Contains(data, []byte(`"response.done"`)) {
+		return
+	}
+	var event struct {
+		Type     string `json:"type"`
+		Response struct {
+			Usage *struct {
+				InputTokens  int64 `json:"input_tokens"`
+				OutputTokens int64 `json:"output_tokens"`
+			} `json:"usage"`
+		} `json:"response"`
+	}
+	if json.Unmarshal(data, &event) != nil || event.Type != "response.done" || event.Response.Usage == nil {
+		return
+	}
+
+	model := session.currentModel()
+	router.logger.Debug("realtime token usage recorded",
+		"provider", session.provider,
+		"model", model,
+		"input_tokens", event.Response.Usage.InputTokens,
+		"output_tokens", event.Response.Usage.OutputTokens)
+	if router.telemetry != nil {
+		router.telemetry.RecordTokenUsage(ctx, otel.SourceGateway, otel.TeamUnknown, string(session.provider), model, event.Response.Usage.InputTokens, event.Response.Usage.OutputTokens)
+	}
+}
+
+// realtimeSubprotocols are the subprotocols offered by the client to relay to
+// the provider, without the credentials offered as subprotocols
+func realtimeSubprotocols(r *http.Request) []string {
+	var protocols []string
+	for _, protocol := range websocket.Subprotocols(r) {
+		if strings.HasPrefix(protocol, middlewares.WebSocketTokenProtocol) || strings.HasPrefix(protocol, openAIKeyProtocol) {
+			continue
+		}
+		protocols = append(protocols, protocol)
+	}
+	return protocols
+}
+
+// realtimeCloseStatus is the close status to relay for the error ending a
+// relayed session, and the error type to record for it: none for normal
+// closures, the close code otherwise
+func realtimeCloseStatus(err error) (code int, text, errorType string) {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return websocket.CloseGoingAway, "", strconv.Itoa(websocket.CloseAbnormalClosure)
+	}
+	switch closeErr.Code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway:
+		return closeErr.Code, closeErr.Text, ""
+	case websocket.CloseNoStatusReceived:
+		// Not a status that can be sent
+		return websocket.CloseNormalClosure, "", ""
+	case websocket.CloseAbnormalClosure, websocket.CloseTLSHandshake:
+		return websocket.CloseGoingAway, "", strconv.Itoa(closeErr.Code)
+	}
+	return closeErr.Code, closeErr.Text, strconv.Itoa(closeErr.Code)
+}
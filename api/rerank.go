@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gin "github.com/gin-gonic/gin"
+	otelapi "go.opentelemetry.io/otel"
+	codes "go.opentelemetry.io/otel/codes"
+	propagation "go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	trace "go.opentelemetry.io/otel/trace"
+
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// cohereRerankRequest is the request of Cohere's native POST /v2/rerank
+type cohereRerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      *int     `json:"top_n,omitempty"`
+}
+
+// cohereRerankResponse is the response of Cohere's native POST /v2/rerank,
+// which bills in search units and never returns the documents
+type cohereRerankResponse struct {
+	ID      string `json:"id"`
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+	Meta struct {
+		BilledUnits struct {
+			SearchUnits int64 `json:"search_units"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// RerankHandler implements POST /v1/rerank, ranking documents by their
+// relevance to a query.
+//
+// Every provider takes the same request and returns the same response: Cohere
+// requests are translated to its native /v2/rerank API, while other providers
+// serve a Jina-compatible /rerank endpoint and receive the body as-is (only the
+// `model` field is rewritten when the provider prefix is stripped). The billed
+// tokens or search units are recorded in telemetry.
+func (router *RouterImpl) RerankHandler(c *gin.Context) {
+	maxBodySize := router.cfg.Server.ResolveMaxRequestBodySize()
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)))
+	if err != nil {
+		router.logger.Error("failed to read request body", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to read request"))
+		return
+	}
+	if len(body) >= maxBodySize {
+		upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
+		return
+	}
+
+	var req types.RerankRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		router.logger.Error("failed to decode request", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		upstreamError(c, requestError(http.StatusBadRequest, "The 'query' field is required."))
+		return
+	}
+	if len(req.Documents) == 0 {
+		upstreamError(c, requestError(http.StatusBadRequest, "The 'documents' field is required."))
+		return
+	}
+
+	originalModel := req.Model
+	model := req.Model
+	providerID := types.Provider(c.Query("provider"))
+	if providerID == "" {
+		var providerPtr *types.Provider
+		providerPtr, model = routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., cohere/rerank-v3.5)."))
+			return
+		}
+		providerID = *providerPtr
+	}
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(
+		semconv.GenAIProviderNameKey.String(string(providerID)),
+		semconv.GenAIRequestModel(originalModel),
+	)
+
+	if reason := router.modelDenied(originalModel); reason != "" {
+		upstreamError(c, deniedError(reason))
+		return
+	}
+
+	provider, err := router.registry.BuildProvider(providerID, router.client)
+	if err != nil {
+		if strings.Contains(err.Error(), "token not configured") {
+			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
+			return
+		}
+		router.logger.Error("provider not found or not supported", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
+		return
+	}
+
+	endpoint := provider.GetEndpoints().Rerank
+	if endpoint == nil || *endpoint == "" {
+		router.logger.Error("api not supported by provider", nil, "api", "Rerank", "provider", providerID)
+		upstreamError(c, requestError(http.StatusBadRequest, "The Rerank API is not supported by this provider yet."))
+		return
+	}
+
+	cohere := providerID == constants.CohereID
+	switch {
+	case cohere:
+		body, err = json.Marshal(cohereRerankRequest{Model: model, Query: req.Query, Documents: req.Documents, TopN: req.TopN})
+	case model != originalModel:
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var payload map[string]any
+		if err := dec.Decode(&payload); err != nil {
+			router.logger.Error("failed to decode request", err)
+			upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
+			return
+		}
+		payload["model"] = model
+		body, err = json.Marshal(payload)
+	}
+	if err != nil {
+		router.logger.Error("failed to encode request", err)
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to encode request"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
+	defer cancel()
+
+	upstreamURL := strings.TrimSuffix(provider.GetURL(), "/") + *endpoint
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	upstreamReq.Header.Set("Accept", "application/json")
+
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
+		router.logger.Error("unsupported auth type", err, "provider", providerID)
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
+		return
+	}
+
+	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(upstreamReq.Header))
+
+	resp, err := router.client.Do(upstreamReq)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			router.logger.Error("request timed out", err, "provider", providerID)
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
+		}
+		upstreamError(c, transportError(ctx, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+		c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, relayHeaders(resp))
+		return
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		router.logger.Error("failed to read response", err, "url", upstreamURL)
+		upstreamError(c, transportError(ctx, err))
+		return
+	}
+
+	if !cohere {
+		var ranked types.RerankResponse
+		if err := json.Unmarshal(respBody, &ranked); err == nil && ranked.Usage != nil {
+			router.recordRerankUsage(ctx, providerID, model, ranked.Usage)
+		}
+		c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), respBody)
+		return
+	}
+
+	var native cohereRerankResponse
+	if err := json.Unmarshal(respBody, &native); err != nil {
+		router.logger.Error("failed to decode response", err, "provider", providerID)
+		upstreamError(c, serverError(http.StatusBadGateway, "Failed to decode provider response"))
+		return
+	}
+	ranked := rerankFromCohere(native, model, req)
+	router.recordRerankUsage(ctx, providerID, model, ranked.Usage)
+	c.JSON(resp.StatusCode, ranked)
+}
+
+// rerankFromCohere translates a Cohere /v2/rerank response, filling in the
+// documents from the request when they were asked for
+func rerankFromCohere(native cohereRerankResponse, model string, req types.RerankRequest) types.RerankResponse {
+	returnDocuments := req.ReturnDocuments != nil && *req.ReturnDocuments
+	results := make([]types.RerankResult, 0, len(native.Results))
+	for _, r := range native.Results {
+		result := types.RerankResult{Index: r.Index, RelevanceScore: r.RelevanceScore}
+		if returnDocuments && r.Index >= 0 && r.Index < len(req.Documents) {
+			result.Document = &struct {
+				Text string `json:"text"`
+			}{Text: req.Documents[r.Index]}
+		}
+		results = append(results, result)
+	}
+
+	ranked := types.RerankResponse{Model: &model, Results: results}
+	if native.ID != "" {
+		ranked.ID = &native.ID
+	}
+	if units := native.Meta.BilledUnits.SearchUnits; units > 0 {
+		ranked.Usage = &types.RerankUsage{SearchUnits: &units}
+	}
+	return ranked
+}
+
+func (router *RouterImpl) recordRerankUsage(ctx context.Context, providerID types.Provider, model string, usage *types.RerankUsage) {
+	if router.telemetry == nil || usage == nil {
+		return
+	}
+	var tokens, searchUnits int64
+	if usage.TotalTokens != nil {
+		tokens = *usage.TotalTokens
+	}
+	if usage.SearchUnits != nil {
+		searchUnits = *usage.SearchUnits
+	}
+	router.telemetry.RecordRerankUsage(context.WithoutCancel(ctx), otel.SourceGateway, otel.TeamUnknown, string(providerID), model, tokens, searchUnits)
+}
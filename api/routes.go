@@ -1,7 +1,6 @@
 package api
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -12,13 +11,14 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/textproto"
-	"net/url"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	gin "github.com/gin-gonic/gin"
+	websocket "github.com/gorilla/websocket"
 	otelhttp "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	otelapi "go.opentelemetry.io/otel"
 	codes "go.opentelemetry.io/otel/codes"
@@ -30,6 +30,9 @@ import (
 	config "github.com/inference-gateway/inference-gateway/config"
 	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
 	proxymodifier "github.com/inference-gateway/inference-gateway/internal/proxy"
+	shaping "github.com/inference-gateway/inference-gateway/internal/shaping"
+	tokens "github.com/inference-gateway/inference-gateway/internal/tokens"
+	truncation "github.com/inference-gateway/inference-gateway/internal/truncation"
 	l "github.com/inference-gateway/inference-gateway/logger"
 	otel "github.com/inference-gateway/inference-gateway/otel"
 	client "github.com/inference-gateway/inference-gateway/providers/client"
@@ -44,16 +47,39 @@ import (
 type Router interface {
 	ListModelsHandler(c *gin.Context)
 	ChatCompletionsHandler(c *gin.Context)
+	// ChatCompletionsAdmission checks chat completions before the middlewares
+	// that may answer them without the handler
+	ChatCompletionsAdmission() gin.HandlerFunc
+	CompletionsHandler(c *gin.Context)
 	MessagesHandler(c *gin.Context)
+	MessagesCountTokensHandler(c *gin.Context)
 	ResponsesHandler(c *gin.Context)
 	ImagesHandler(c *gin.Context)
 	ImagesEditsHandler(c *gin.Context)
 	ImagesVariationsHandler(c *gin.Context)
+	AudioTranscriptionsHandler(c *gin.Context)
+	AudioTranslationsHandler(c *gin.Context)
+	AudioSpeechHandler(c *gin.Context)
+	RerankHandler(c *gin.Context)
+	ModerationsHandler(c *gin.Context)
 	ListToolsHandler(c *gin.Context)
 	MetricsIngestionHandler(c *gin.Context)
 	ProxyHandler(c *gin.Context)
+	TokenizeHandler(c *gin.Context)
+	WebSocketHandler(c *gin.Context)
+	RealtimeHandler(c *gin.Context)
 	HealthcheckHandler(c *gin.Context)
 	NotFoundHandler(c *gin.Context)
+	// Reload swaps the reloadable settings (model allow-lists) for new requests
+	Reload(cfg config.Config)
+	// StartModelRefresh keeps the model catalog cache warm until ctx is done.
+	// It returns immediately and does nothing when the cache is disabled.
+	StartModelRefresh(ctx context.Context)
+	// SetAgentFactory sets how WebSocket sessions build their MCP agent
+	SetAgentFactory(factory func() mcp.Agent)
+	// SetGuardrails sets the guardrails the turns of WebSocket sessions go
+	// through
+	SetGuardrails(checker middlewares.GuardrailsChecker)
 }
 
 type RouterImpl struct {
@@ -64,6 +90,29 @@ type RouterImpl struct {
 	mcpClient mcp.MCPClientInterface
 	telemetry otel.OpenTelemetry
 	selector  *routing.Selector
+	shaper    *shaping.Shaper
+	models    atomic.Pointer[modelLists]
+	catalog   *modelCatalog
+	// summarizer writes the summaries of the summarize_middle truncation
+	// strategy; nil when TRUNCATION_SUMMARY_MODEL is not set
+	summarizer truncation.Summarizer
+	// newAgent builds the MCP agent of a WebSocket session; nil when MCP is
+	// disabled
+	newAgent func() mcp.Agent
+	// guardrails checks the turns of WebSocket sessions; nil when guardrails
+	// are disabled
+	guardrails middlewares.GuardrailsChecker
+	// runtimeWindows memoizes the context windows read from local runtimes
+	runtimeWindows runtimeWindows
+	// dialer opens the upstream connections of the Realtime API relay
+	dialer *websocket.Dialer
+}
+
+// modelLists holds the ALLOWED_MODELS / DISALLOWED_MODELS settings, which can
+// be swapped at runtime by Reload
+type modelLists struct {
+	allowed    string
+	disallowed string
 }
 
 type ErrorResponse struct {
@@ -82,16 +131,47 @@ func NewRouter(
 	mcpClient mcp.MCPClientInterface,
 	telemetry otel.OpenTelemetry,
 	selector *routing.Selector,
+	shaper *shaping.Shaper,
 ) Router {
-	return &RouterImpl{
-		cfg,
-		logger,
-		providerRegistry,
-		httpClient,
-		mcpClient,
-		telemetry,
-		selector,
+	router := &RouterImpl{
+		cfg:       cfg,
+		logger:    logger,
+		registry:  providerRegistry,
+		client:    httpClient,
+		mcpClient: mcpClient,
+		telemetry: telemetry,
+		selector:  selector,
+		shaper:    shaper,
+		dialer:    client.NewWebSocketDialer(cfg.Client),
+	}
+	if cfg.Cache != nil && cfg.Cache.ModelsEnabled {
+		router.catalog = newModelCatalog(providerRegistry, httpClient, logger, cfg.Cache.ModelsTtl, cfg.Server.ReadTimeout, router.resolveContextWindows)
+	}
+	if cfg.Truncation != nil && cfg.Truncation.Enabled && cfg.Truncation.SummaryModel != "" {
+		if provider, model := routing.DetermineProviderAndModelName(cfg.Truncation.SummaryModel); provider != nil {
+			router.summarizer = truncation.NewProviderSummarizer(providerRegistry, httpClient, *provider, model, cfg.Truncation.SummaryMaxTokens)
+		}
+	}
+	router.Reload(cfg)
+	return router
+}
+
+func (router *RouterImpl) Reload(cfg config.Config) {
+	router.models.Store(&modelLists{
+		allowed:    cfg.AllowedModels,
+		disallowed: cfg.DisallowedModels,
+	})
+	if router.catalog != nil {
+		router.catalog.invalidate()
+	}
+	router.runtimeWindows.invalidate()
+}
+
+func (router *RouterImpl) StartModelRefresh(ctx context.Context) {
+	if router.catalog == nil {
+		return
 	}
+	go router.catalog.run(ctx)
 }
 
 func (router *RouterImpl) NotFoundHandler(c *gin.Context) {
@@ -113,7 +193,7 @@ func (router *RouterImpl) ProxyHandler(c *gin.Context) {
 		return
 	}
 
-	if err := applyProviderAuth(c.Request, provider); err != nil {
+	if err := core.ApplyAuth(c.Request, provider); err != nil {
 		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: "Unsupported auth type"})
 		return
 	}
@@ -133,7 +213,7 @@ func (router *RouterImpl) ProxyHandler(c *gin.Context) {
 func handleStreamingRequest(c *gin.Context, provider core.IProvider, router *RouterImpl) {
 	middlewares.SetSSEHeaders(c)
 
-	fullURL, err := constructProviderURL(provider, c.Param("path"), c.Request.URL.RawQuery)
+	fullURL, err := core.BuildURL(provider, c.Param("path"), c.Request.URL.RawQuery)
 	if err != nil {
 		router.logger.Error("failed to construct provider url", err, "provider", provider.GetName())
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to construct URL"})
@@ -166,65 +246,36 @@ func handleStreamingRequest(c *gin.Context, provider core.IProvider, router *Rou
 	resp, err := router.client.Do(upstreamReq)
 	if err != nil {
 		router.logger.Error("failed to make upstream request", err, "url", fullURL.String())
-		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "Failed to reach upstream server"})
+		upstreamError(c, transportError(ctx, err))
 		return
 	}
 	defer resp.Body.Close()
 
-	reader := bufio.NewReaderSize(resp.Body, 4096)
-
-	c.Stream(func(w io.Writer) bool {
-		middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
-
-		line, err := reader.ReadBytes('\n')
-		if err != nil {
-			if err != io.EOF {
-				router.logger.Error("failed to read stream", err,
-					"url", fullURL.String(),
-					"method", c.Request.Method)
-			}
-			return false
-		}
-
-		if len(line) == 0 {
-			return true
-		}
-
-		if router.cfg.Environment == "development" {
+	var logChunk func([]byte)
+	if router.cfg.Environment == "development" {
+		logChunk = func(line []byte) {
 			shouldLog := len(line) > 512 ||
-				(c.Param("provider") != "" && len(line) > 0 && (len(line)%10 == 0))
-
-			if shouldLog {
-				router.logger.Debug("stream chunk",
-					"provider", c.Param("provider"),
-					"bytes", len(line),
-					"data_preview", func() string {
-						preview := string(bytes.TrimSpace(line))
-						if len(preview) > 200 {
-							return preview[:200] + "... (truncated)"
-						}
-						return preview
-					}(),
-				)
+				(c.Param("provider") != "" && len(line)%10 == 0)
+			if !shouldLog {
+				return
 			}
+			preview := string(bytes.TrimSpace(line))
+			if len(preview) > 200 {
+				preview = preview[:200] + "... (truncated)"
+			}
+			router.logger.Debug("stream chunk",
+				"provider", c.Param("provider"),
+				"bytes", len(line),
+				"data_preview", preview,
+			)
 		}
+	}
 
-		if _, err := w.Write(line); err != nil {
-			router.logger.Error("failed to write response", err,
-				"bytes", len(line))
-			return false
-		}
-
-		if flusher, ok := w.(http.Flusher); ok {
-			flusher.Flush()
-		}
-
-		return true
-	})
+	router.relayStream(c, resp.Body, fullURL.String(), chatIdleEvent, logChunk)
 }
 
 func handleProxyRequest(c *gin.Context, provider core.IProvider, router *RouterImpl) {
-	fullURL, err := constructProviderURL(provider, c.Param("path"), c.Request.URL.RawQuery)
+	fullURL, err := core.BuildURL(provider, c.Param("path"), c.Request.URL.RawQuery)
 	if err != nil {
 		router.logger.Error("failed to construct provider url", err, "provider", provider.GetName())
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to construct URL"})
@@ -236,12 +287,10 @@ func handleProxyRequest(c *gin.Context, provider core.IProvider, router *RouterI
 
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
 		router.logger.Error("proxy request failed", err, "url", fullURL.String())
+		upstreamErr := core.ClassifyError(transportError(r.Context(), err))
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		err = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: fmt.Sprintf("Failed to reach upstream server: %v", err),
-		})
-		if err != nil {
+		w.WriteHeader(upstreamErr.StatusCode)
+		if err := json.NewEncoder(w).Encode(upstreamErr.OpenAI()); err != nil {
 			router.logger.Error("failed to write error response", err)
 		}
 	}
@@ -272,63 +321,6 @@ func handleProxyRequest(c *gin.Context, provider core.IProvider, router *RouterI
 	proxy.ServeHTTP(&middlewares.DeadlineResetWriter{ResponseWriter: c.Writer, Timeout: router.cfg.Server.WriteTimeout}, c.Request)
 }
 
-// applyProviderAuth sets the provider's auth credential (header or query
-// param) and extra headers on req. An unrecognized auth type is returned as an
-// error so misconfigured providers fail loudly instead of sending
-// unauthenticated requests upstream.
-//
-// The caller's inbound Authorization header is always removed first so it is
-// never forwarded to the upstream provider: the client authenticates to the
-// gateway (and the gateway's self-proxy hop carries that token so OIDC can
-// re-verify /proxy), but only the provider's own credential must leave the
-// gateway. Bearer providers overwrite the header below; the others (x-api-key,
-// query key, none) authenticate elsewhere, so without this removal the caller's
-// bearer/OIDC token would leak to third-party providers.
-func applyProviderAuth(req *http.Request, provider core.IProvider) error {
-	req.Header.Del("Authorization")
-
-	token := provider.GetToken()
-	switch provider.GetAuthType() {
-	case constants.AuthTypeBearer:
-		req.Header.Set("Authorization", "Bearer "+token)
-	case constants.AuthTypeXheader:
-		req.Header.Set("x-api-key", token)
-	case constants.AuthTypeQuery:
-		query := req.URL.Query()
-		query.Set("key", token)
-		req.URL.RawQuery = query.Encode()
-	case constants.AuthTypeNone:
-		// Do Nothing
-	default:
-		return fmt.Errorf("unsupported auth type %q", provider.GetAuthType())
-	}
-
-	for key, values := range provider.GetExtraHeaders() {
-		for _, value := range values {
-			req.Header.Add(key, value)
-		}
-	}
-	return nil
-}
-
-// constructProviderURL builds the provider URL consistently to avoid path duplication.
-// It ensures that the path from the provider URL is handled correctly with the path parameter.
-func constructProviderURL(provider core.IProvider, pathParam, rawQuery string) (*url.URL, error) {
-	providerURL, err := url.Parse(provider.GetURL())
-	if err != nil {
-		return nil, err
-	}
-
-	url := &url.URL{
-		Scheme:   providerURL.Scheme,
-		Host:     providerURL.Host,
-		Path:     strings.TrimSuffix(providerURL.Path, "/") + "/" + strings.TrimPrefix(pathParam, "/"),
-		RawQuery: rawQuery,
-	}
-
-	return url, nil
-}
-
 func (router *RouterImpl) HealthcheckHandler(c *gin.Context) {
 	router.logger.Debug("healthcheck")
 	c.JSON(http.StatusOK, ResponseJSON{Message: "OK"})
@@ -436,6 +428,12 @@ func (router *RouterImpl) renderModelsResponse(c *gin.Context, resp types.ListMo
 //     fields to include (context_window, pricing). Keys are trimmed and
 //     de-duplicated; an unknown key returns 400. Requested-but-unresolved keys are
 //     returned as explicit null. When omitted, no metadata fields are added.
+//   - refresh (query): Optional. With the model catalog cache enabled
+//     (CACHE_MODELS_ENABLED), true fetches the lists from the providers
+//     instead of serving the cached ones.
+//
+// With the model catalog cache enabled the response also carries a
+// "providers" array with the last successful listing of each provider.
 //
 // Response format:
 //
@@ -463,6 +461,14 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 		return
 	}
 
+	refresh := false
+	if raw := c.Query("refresh"); raw != "" {
+		if refresh, err = strconv.ParseBool(raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid refresh parameter, expected true or false"})
+			return
+		}
+	}
+
 	providerID := types.Provider(c.Query("provider"))
 	if providerID != "" {
 		provider, err := router.registry.BuildProvider(providerID, router.client)
@@ -477,6 +483,11 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 			return
 		}
 
+		if router.catalog != nil {
+			router.listCatalogModels(c, providerID, includeKeys, refresh)
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
 		defer cancel()
 
@@ -484,15 +495,16 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 		if err != nil {
 			if ctx.Err() == context.DeadlineExceeded {
 				router.logger.Error("request timed out", err, "provider", provider.GetName())
-				c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "Request timed out"})
+				upstreamError(c, transportError(ctx, err))
 				return
 			}
 			router.logger.Error("failed to list models", err, "provider", provider.GetName())
-			c.JSON(http.StatusBadGateway, ErrorResponse{Error: "Failed to list models"})
+			upstreamError(c, err)
 			return
 		}
 
-		response.Data = routing.FilterModels(response.Data, router.cfg.AllowedModels, router.cfg.DisallowedModels)
+		models := router.models.Load()
+		response.Data = routing.FilterModels(response.Data, models.allowed, models.disallowed)
 
 		if slices.Contains(includeKeys, string(types.ListModelsParamsIncludeContextWindow)) {
 			router.resolveContextWindows(ctx, response.Data)
@@ -500,8 +512,13 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 
 		router.renderModelsResponse(c, response, includeKeys)
 	} else {
+		if router.catalog != nil {
+			router.listCatalogModels(c, "", includeKeys, refresh)
+			return
+		}
+
 		var wg sync.WaitGroup
-		providersCfg := router.cfg.Providers
+		providersCfg := router.registry.GetProviders()
 
 		ch := make(chan types.ListModelsResponse, len(providersCfg))
 
@@ -548,7 +565,8 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 			allModels = make([]types.Model, 0)
 		}
 
-		allModels = routing.FilterModels(allModels, router.cfg.AllowedModels, router.cfg.DisallowedModels)
+		models := router.models.Load()
+		allModels = routing.FilterModels(allModels, models.allowed, models.disallowed)
 
 		if slices.Contains(includeKeys, string(types.ListModelsParamsIncludeContextWindow)) {
 			router.resolveContextWindows(ctx, allModels)
@@ -563,6 +581,48 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 	}
 }
 
+// listCatalogModels serves GET /v1/models from the model catalog cache, for
+// one provider or all of them when providerID is empty. The response lists
+// the freshness of each provider's models next to the data. A single
+// provider that has never listed its models fails as the uncached handler
+// would.
+func (router *RouterImpl) listCatalogModels(c *gin.Context, providerID types.Provider, includeKeys []string, refresh bool) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
+	defer cancel()
+
+	ids := []types.Provider{providerID}
+	if providerID == "" {
+		ids = router.catalog.providers()
+	}
+	models, statuses := router.catalog.list(ctx, ids, refresh)
+	response := types.ListModelsResponse{
+		Object:    "list",
+		Providers: &statuses,
+	}
+	if providerID != "" {
+		if status := statuses[0]; status.LastSuccess == nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				router.logger.Error("request timed out", ctx.Err(), "provider", providerID)
+				upstreamError(c, transportError(ctx, ctx.Err()))
+				return
+			}
+			err := errors.New("no models listed")
+			if status.Error != nil {
+				err = errors.New(*status.Error)
+			}
+			router.logger.Error("failed to list models", err, "provider", providerID)
+			upstreamError(c, &core.UpstreamError{Code: core.ErrorCodeUpstream, StatusCode: http.StatusBadGateway, Message: "Failed to list models"})
+			return
+		}
+		response.Provider = &providerID
+	}
+
+	list := router.models.Load()
+	response.Data = routing.FilterModels(models, list.allowed, list.disallowed)
+
+	router.renderModelsResponse(c, response, includeKeys)
+}
+
 // ChatCompletionsHandler implements an OpenAI-compatible API endpoint
 // that generates text completions in the standard OpenAI format.
 //
@@ -620,125 +680,42 @@ func (router *RouterImpl) ListModelsHandler(c *gin.Context) {
 // DISALLOWED_MODELS, returning the client-facing reason ("" when permitted).
 // ALLOWED_MODELS takes precedence: when it is set, DISALLOWED_MODELS is ignored.
 func (router *RouterImpl) modelDenied(model string) string {
-	if allowed := routing.ParseModelSet(router.cfg.AllowedModels); len(allowed) > 0 {
+	models := router.models.Load()
+	if allowed := routing.ParseModelSet(models.allowed); len(allowed) > 0 {
 		if !routing.ModelMatches(allowed, model) {
-			router.logger.Error("model not in allowed list", nil, "model", model, "allowed_models", router.cfg.AllowedModels)
+			router.logger.Error("model not in allowed list", nil, "model", model, "allowed_models", models.allowed)
 			return "Model not allowed. Please check the list of allowed models."
 		}
 		return ""
 	}
-	if disallowed := routing.ParseModelSet(router.cfg.DisallowedModels); len(disallowed) > 0 && routing.ModelMatches(disallowed, model) {
-		router.logger.Error("model is disallowed", nil, "model", model, "disallowed_models", router.cfg.DisallowedModels)
+	if disallowed := routing.ParseModelSet(models.disallowed); len(disallowed) > 0 && routing.ModelMatches(disallowed, model) {
+		router.logger.Error("model is disallowed", nil, "model", model, "disallowed_models", models.disallowed)
 		return "Model is disallowed. Please use a different model."
 	}
 	return ""
 }
 
 func (router *RouterImpl) ChatCompletionsHandler(c *gin.Context) {
-	var req types.CreateChatCompletionRequest
-
-	if mcpRequest, exists := c.Get(middlewares.MCPBypassHeader); exists {
-		if parsedRequest, ok := mcpRequest.(*types.CreateChatCompletionRequest); ok {
-			req = *parsedRequest
-		} else {
-			router.logger.Error("invalid mcp request type in context", nil)
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
-			return
-		}
-	} else {
-		maxBodySize := router.cfg.Server.ResolveMaxRequestBodySize()
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(maxBodySize))
-		if err := c.ShouldBindJSON(&req); err != nil {
-			var maxBytesErr *http.MaxBytesError
-			if errors.As(err, &maxBytesErr) {
-				router.logger.Error("request body too large", err)
-				c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body too large"})
-				return
-			}
-			router.logger.Error("failed to decode request", err)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to decode request"})
-			return
-		}
-	}
-
-	model := req.Model
-	originalModel := req.Model
-	providerID := types.Provider(c.Query("provider"))
-
-	var routedProvider, routedModel string
-	if router.selector != nil && providerID == "" {
-		if dep, ok := router.selector.Select(model); ok {
-			providerID = types.Provider(dep.Provider)
-			model = dep.Model
-			routedProvider, routedModel = dep.Provider, dep.Model
-			router.logger.Debug("routed logical model", "alias", originalModel, "provider", dep.Provider, "model", dep.Model)
-		}
-	}
-
-	if providerID == "" {
-		var providerPtr *types.Provider
-		providerPtr, model = routing.DetermineProviderAndModelName(model)
-		if providerPtr == nil {
-			router.logger.Error("unable to determine provider for model", nil, "model", req.Model)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-4)."})
-			return
-		}
-		providerID = *providerPtr
-	}
-	req.Model = model
-
-	if reason := router.modelDenied(originalModel); reason != "" {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: reason})
+	dispatch, ok := router.admitChatCompletion(c)
+	if !ok {
 		return
 	}
-
-	provider, err := router.registry.BuildProvider(providerID, router.client)
+	// The admitted request, including what the middlewares changed since
+	// (e.g. injected MCP tools), is shared with them; work on a copy
+	parsed, err := middlewares.GetRequestBody(c, router.cfg).ChatCompletionRequest()
 	if err != nil {
-		if strings.Contains(err.Error(), "token not configured") {
-			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider requires an API key. Please configure the provider's API key."})
-			return
-		}
-		router.logger.Error("provider not found or not supported", err, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider not found. Please check the list of supported providers."})
+		router.logger.Error("failed to decode request", err)
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
 		return
 	}
+	req := *parsed
+	req.Model = dispatch.model
+	providerID, provider := dispatch.providerID, dispatch.provider
+	routedProvider, routedModel := dispatch.routedProvider, dispatch.routedModel
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
 	defer cancel()
 
-	if router.cfg.EnableVision {
-		hasImageContent := false
-		imageCount := 0
-		for _, message := range req.Messages {
-			if message.HasImageContent() {
-				hasImageContent = true
-				imageCount++
-			}
-		}
-
-		if hasImageContent {
-			if !core.ModelAcceptsImages(providerID, req.Model) {
-				router.logger.Info("filtering images from non-vision model request",
-					"provider", providerID,
-					"model", req.Model,
-					"messagesWithImages", imageCount)
-
-				for i := range req.Messages {
-					if req.Messages[i].HasImageContent() {
-						if err := req.Messages[i].StripImageContent(); err != nil {
-							router.logger.Error("failed to strip image content from message", err)
-							c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process message content"})
-							return
-						}
-					}
-				}
-
-				router.logger.Debug("images stripped from request, continuing with text-only content")
-			}
-		}
-	}
-
 	router.logger.Debug("server read timeout", "timeout", router.cfg.Server.ReadTimeout)
 
 	if routedProvider != "" {
@@ -753,44 +730,11 @@ func (router *RouterImpl) ChatCompletionsHandler(c *gin.Context) {
 		streamCh, err := provider.StreamChatCompletions(streamCtx, req)
 		if err != nil {
 			router.logger.Error("failed to start streaming", err, "provider", providerID)
-
-			statusCode := http.StatusBadRequest
-			if httpErr, ok := err.(*core.HTTPError); ok {
-				statusCode = httpErr.StatusCode
-			}
-
-			c.JSON(statusCode, ErrorResponse{Error: err.Error()})
+			upstreamError(c, err)
 			return
 		}
 
-		c.Stream(func(w io.Writer) bool {
-			select {
-			case line, ok := <-streamCh:
-				if !ok {
-					router.logger.Debug("stream closed", "provider", providerID)
-					return false
-				}
-
-				middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
-
-				router.logger.Debug("stream chunk",
-					"provider", providerID,
-					"bytes", len(line),
-					"line", string(line))
-
-				if _, err := w.Write(line); err != nil {
-					router.logger.Error("failed to write chunk", err)
-					return false
-				}
-
-				if flusher, ok := w.(http.Flusher); ok {
-					flusher.Flush()
-				}
-				return true
-			case <-streamCtx.Done():
-				return false
-			}
-		})
+		router.writeChatStream(c, streamCh, providerID, core.StreamEvent.SSE)
 		return
 	}
 
@@ -799,23 +743,85 @@ func (router *RouterImpl) ChatCompletionsHandler(c *gin.Context) {
 	if err != nil {
 		if err == context.DeadlineExceeded || ctx.Err() == context.DeadlineExceeded {
 			router.logger.Error("request timed out", err, "provider", providerID)
-			c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "Request timed out"})
+			upstreamError(c, transportError(ctx, err))
 			return
 		}
 		router.logger.Error("failed to generate tokens", err, "provider", providerID)
-
-		statusCode := http.StatusBadRequest
-		if httpErr, ok := err.(*core.HTTPError); ok {
-			statusCode = httpErr.StatusCode
-		}
-
-		c.JSON(statusCode, ErrorResponse{Error: err.Error()})
+		upstreamError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// writeChatStream writes the events of a chat completion stream to the client,
+// encoded by sse, ending with the OpenAI [DONE] event. Keep-alive comments are
+// written while the provider is quiet, and the stream is aborted once it has
+// been silent for the stream idle timeout.
+func (router *RouterImpl) writeChatStream(c *gin.Context, streamCh <-chan core.StreamEvent, providerID types.Provider, sse func(core.StreamEvent) []byte) {
+	streamCtx := c.Request.Context()
+	usage := core.StreamUsageFrom(streamCtx)
+	defer usage.EndStream()
+	watchdog := router.newStreamWatchdog()
+	defer watchdog.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-streamCh:
+			watchdog.Received()
+			line := core.StreamDone
+			if ok {
+				line = sse(event)
+				usage.Observe(event)
+			}
+
+			middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+
+			router.logger.Debug("stream chunk",
+				"provider", providerID,
+				"bytes", len(line),
+				"line", string(line))
+
+			if _, err := w.Write(line); err != nil {
+				router.logger.Error("failed to write chunk", err)
+				return false
+			}
+
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			watchdog.Sent()
+
+			if !ok {
+				router.logger.Debug("stream closed", "provider", providerID)
+				return false
+			}
+			if event.Err != nil {
+				router.logger.Error("provider stream failed", event.Err, "provider", providerID)
+				return false
+			}
+			return true
+		case <-watchdog.KeepAlive():
+			middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+			if err := middlewares.WriteKeepAlive(w); err != nil {
+				router.logger.Error("failed to write keep-alive", err)
+				return false
+			}
+			watchdog.Sent()
+			return true
+		case <-watchdog.Idle():
+			router.logger.Warn("aborting idle stream", "provider", providerID, "idle_timeout", watchdog.IdleTimeout())
+			middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+			if _, err := w.Write(chatIdleEvent(watchdog.IdleTimeout())); err != nil {
+				router.logger.Error("failed to write chunk", err)
+			}
+			return false
+		case <-streamCtx.Done():
+			return false
+		}
+	})
+}
+
 // messagesError writes a gateway-generated error in the Anthropic error
 // envelope ({"type": "error", "error": {"type": ..., "message": ...}}), which
 // is what native Messages API clients expect to parse.
@@ -840,16 +846,79 @@ func messagesError(c *gin.Context, status int, errType, message string) {
 // (currently Anthropic); other providers receive a 400 in the Anthropic error
 // envelope, mirroring the schema's MessagesNotSupported response.
 func (router *RouterImpl) MessagesHandler(c *gin.Context) {
+	req, ok := router.resolveMessagesRequest(c)
+	if !ok {
+		return
+	}
+
+	if req.providerID != constants.AnthropicID {
+		router.logger.Error("messages api not supported by provider", nil, "provider", req.providerID)
+		messagesError(c, http.StatusBadRequest, "not_supported_error", "The Messages API is not supported by this provider yet.")
+		return
+	}
+
+	router.relayMessages(c, req, "/messages")
+}
+
+// MessagesCountTokensHandler implements an Anthropic-compatible
+// POST /v1/messages/count_tokens endpoint:
+// https://docs.anthropic.com/en/api/messages-count-tokens
+//
+// Requests for providers implementing the Messages API (currently Anthropic)
+// are relayed to the provider like MessagesHandler does. For any other
+// provider the input tokens are estimated locally with the same tokenizer as
+// POST /v1/tokenize: exactly for OpenAI-family models, from the text length
+// otherwise.
+func (router *RouterImpl) MessagesCountTokensHandler(c *gin.Context) {
+	req, ok := router.resolveMessagesRequest(c)
+	if !ok {
+		return
+	}
+
+	if req.providerID == constants.AnthropicID {
+		router.relayMessages(c, req, "/messages/count_tokens")
+		return
+	}
+
+	var count types.MessagesCountTokensRequest
+	if err := json.Unmarshal(req.body, &count); err != nil {
+		router.logger.Error("failed to decode request", err)
+		messagesError(c, http.StatusBadRequest, "invalid_request_error", "Failed to decode request")
+		return
+	}
+	if count.Messages == nil {
+		messagesError(c, http.StatusBadRequest, "invalid_request_error", "messages: Field required")
+		return
+	}
+
+	estimate := tokens.MessagesRequest(req.model, count)
+	router.logger.Debug("estimated message tokens", "provider", req.providerID, "model", req.model, "tokens", estimate.Tokens, "method", estimate.Method)
+	c.JSON(http.StatusOK, types.MessagesCountTokensResponse{InputTokens: estimate.Tokens})
+}
+
+// messagesRequest is a Messages API request resolved to its provider
+type messagesRequest struct {
+	body          []byte
+	providerID    types.Provider
+	model         string
+	originalModel string
+	stream        bool
+}
+
+// resolveMessagesRequest reads a Messages API request, resolves its provider
+// and applies the model allow-list. It writes the error response and returns
+// false when the request cannot be served.
+func (router *RouterImpl) resolveMessagesRequest(c *gin.Context) (*messagesRequest, bool) {
 	maxBodySize := router.cfg.Server.ResolveMaxRequestBodySize()
 	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)))
 	if err != nil {
 		router.logger.Error("failed to read request body", err)
 		messagesError(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request")
-		return
+		return nil, false
 	}
 	if len(body) >= maxBodySize {
 		messagesError(c, http.StatusRequestEntityTooLarge, "invalid_request_error", "Request body too large")
-		return
+		return nil, false
 	}
 
 	var req struct {
@@ -859,7 +928,7 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 	if err := json.Unmarshal(body, &req); err != nil {
 		router.logger.Error("failed to decode request", err)
 		messagesError(c, http.StatusBadRequest, "invalid_request_error", "Failed to decode request")
-		return
+		return nil, false
 	}
 
 	originalModel := req.Model
@@ -871,7 +940,7 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 		if providerPtr == nil {
 			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
 			messagesError(c, http.StatusBadRequest, "invalid_request_error", "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., anthropic/claude-sonnet-4-5).")
-			return
+			return nil, false
 		}
 		providerID = *providerPtr
 	}
@@ -884,15 +953,22 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 
 	if reason := router.modelDenied(originalModel); reason != "" {
 		messagesError(c, http.StatusForbidden, "invalid_request_error", reason)
-		return
+		return nil, false
 	}
 
-	if providerID != constants.AnthropicID {
-		router.logger.Error("messages api not supported by provider", nil, "provider", providerID)
-		messagesError(c, http.StatusBadRequest, "not_supported_error", "The Messages API is not supported by this provider yet.")
-		return
-	}
+	return &messagesRequest{
+		body:          body,
+		providerID:    providerID,
+		model:         model,
+		originalModel: originalModel,
+		stream:        req.Stream != nil && *req.Stream,
+	}, true
+}
 
+// relayMessages forwards a Messages API request to path on its provider and
+// relays the response verbatim
+func (router *RouterImpl) relayMessages(c *gin.Context, req *messagesRequest, path string) {
+	providerID := req.providerID
 	provider, err := router.registry.BuildProvider(providerID, router.client)
 	if err != nil {
 		if strings.Contains(err.Error(), "token not configured") {
@@ -905,7 +981,8 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 		return
 	}
 
-	if model != originalModel {
+	body := req.body
+	if req.model != req.originalModel {
 		dec := json.NewDecoder(bytes.NewReader(body))
 		dec.UseNumber()
 		var payload map[string]any
@@ -914,7 +991,7 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 			messagesError(c, http.StatusBadRequest, "invalid_request_error", "Failed to decode request")
 			return
 		}
-		payload["model"] = model
+		payload["model"] = req.model
 		if body, err = json.Marshal(payload); err != nil {
 			router.logger.Error("failed to encode request", err)
 			messagesError(c, http.StatusInternalServerError, "api_error", "Failed to encode request")
@@ -922,7 +999,7 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 		}
 	}
 
-	isStreaming := req.Stream != nil && *req.Stream
+	isStreaming := req.stream
 
 	ctx := c.Request.Context()
 	if !isStreaming {
@@ -931,7 +1008,7 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 		defer cancel()
 	}
 
-	upstreamURL := strings.TrimSuffix(provider.GetURL(), "/") + "/messages"
+	upstreamURL := strings.TrimSuffix(provider.GetURL(), "/") + path
 	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
 	if err != nil {
 		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
@@ -945,7 +1022,7 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 		upstreamReq.Header.Set("Accept", "application/json")
 	}
 
-	if err := applyProviderAuth(upstreamReq, provider); err != nil {
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
 		router.logger.Error("unsupported auth type", err, "provider", providerID)
 		messagesError(c, http.StatusUnprocessableEntity, "api_error", "Unsupported auth type")
 		return
@@ -957,51 +1034,28 @@ func (router *RouterImpl) MessagesHandler(c *gin.Context) {
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			router.logger.Error("request timed out", err, "provider", providerID)
-			messagesError(c, http.StatusGatewayTimeout, "api_error", "Request timed out")
-			return
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
 		}
-		router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
-		messagesError(c, http.StatusBadGateway, "api_error", "Failed to reach upstream server")
+		messagesUpstreamError(c, transportError(ctx, err))
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
+		span := trace.SpanFromContext(c.Request.Context())
 		span.SetStatus(codes.Error, resp.Status)
 		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
 	}
 
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "text/event-stream") {
-		c.DataFromReader(resp.StatusCode, resp.ContentLength, contentType, resp.Body, nil)
+		c.DataFromReader(resp.StatusCode, resp.ContentLength, contentType, resp.Body, relayHeaders(resp))
 		return
 	}
 
 	middlewares.SetSSEHeaders(c)
-	reader := bufio.NewReaderSize(resp.Body, 4096)
-	c.Stream(func(w io.Writer) bool {
-		middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
-
-		// The upstream request carries the client's context, so cancellation
-		// surfaces here as a read error - no separate ctx.Done() check needed.
-		line, err := reader.ReadBytes('\n')
-		if len(line) > 0 {
-			if _, werr := w.Write(line); werr != nil {
-				router.logger.Error("failed to write chunk", werr)
-				return false
-			}
-			if flusher, ok := w.(http.Flusher); ok {
-				flusher.Flush()
-			}
-		}
-		if err != nil {
-			if err != io.EOF {
-				router.logger.Error("failed to read stream", err, "url", upstreamURL)
-			}
-			return false
-		}
-		return true
-	})
+	router.relayStream(c, resp.Body, upstreamURL, messagesIdleEvent, nil)
 }
 
 // ResponsesHandler implements an OpenAI-compatible POST /v1/responses
@@ -1020,11 +1074,11 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)))
 	if err != nil {
 		router.logger.Error("failed to read request body", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read request"})
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to read request"))
 		return
 	}
 	if len(body) >= maxBodySize {
-		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body too large"})
+		upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
 		return
 	}
 
@@ -1034,7 +1088,7 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 	}
 	if err := json.Unmarshal(body, &req); err != nil {
 		router.logger.Error("failed to decode request", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to decode request"})
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
 		return
 	}
 
@@ -1046,7 +1100,7 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 		providerPtr, model = routing.DetermineProviderAndModelName(model)
 		if providerPtr == nil {
 			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-4o)."})
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-4o)."))
 			return
 		}
 		providerID = *providerPtr
@@ -1059,13 +1113,13 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 	)
 
 	if reason := router.modelDenied(originalModel); reason != "" {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: reason})
+		upstreamError(c, deniedError(reason))
 		return
 	}
 
 	if providerID != constants.OpenaiID {
 		router.logger.Error("responses api not supported by provider", nil, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "The Responses API is not supported by this provider yet. Use /v1/chat/completions instead."})
+		upstreamError(c, requestError(http.StatusBadRequest, "The Responses API is not supported by this provider yet. Use /v1/chat/completions instead."))
 		return
 	}
 
@@ -1073,11 +1127,11 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 	if err != nil {
 		if strings.Contains(err.Error(), "token not configured") {
 			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider requires an API key. Please configure the provider's API key."})
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
 			return
 		}
 		router.logger.Error("provider not found or not supported", err, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider not found. Please check the list of supported providers."})
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
 		return
 	}
 
@@ -1087,13 +1141,13 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 		var payload map[string]any
 		if err := dec.Decode(&payload); err != nil {
 			router.logger.Error("failed to decode request", err)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to decode request"})
+			upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
 			return
 		}
 		payload["model"] = model
 		if body, err = json.Marshal(payload); err != nil {
 			router.logger.Error("failed to encode request", err)
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to encode request"})
+			upstreamError(c, serverError(http.StatusInternalServerError, "Failed to encode request"))
 			return
 		}
 	}
@@ -1111,7 +1165,7 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
 	if err != nil {
 		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create upstream request"})
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
 		return
 	}
 	upstreamReq.Header.Set("Content-Type", "application/json")
@@ -1121,9 +1175,9 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 		upstreamReq.Header.Set("Accept", "application/json")
 	}
 
-	if err := applyProviderAuth(upstreamReq, provider); err != nil {
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
 		router.logger.Error("unsupported auth type", err, "provider", providerID)
-		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: "Unsupported auth type"})
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
 		return
 	}
 
@@ -1133,11 +1187,10 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			router.logger.Error("request timed out", err, "provider", providerID)
-			c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "Request timed out"})
-			return
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
 		}
-		router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
-		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "Failed to reach upstream server"})
+		upstreamError(c, transportError(ctx, err))
 		return
 	}
 	defer resp.Body.Close()
@@ -1149,33 +1202,12 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "text/event-stream") {
-		c.DataFromReader(resp.StatusCode, resp.ContentLength, contentType, resp.Body, nil)
+		c.DataFromReader(resp.StatusCode, resp.ContentLength, contentType, resp.Body, relayHeaders(resp))
 		return
 	}
 
 	middlewares.SetSSEHeaders(c)
-	reader := bufio.NewReaderSize(resp.Body, 4096)
-	c.Stream(func(w io.Writer) bool {
-		middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
-
-		line, err := reader.ReadBytes('\n')
-		if len(line) > 0 {
-			if _, werr := w.Write(line); werr != nil {
-				router.logger.Error("failed to write chunk", werr)
-				return false
-			}
-			if flusher, ok := w.(http.Flusher); ok {
-				flusher.Flush()
-			}
-		}
-		if err != nil {
-			if err != io.EOF {
-				router.logger.Error("failed to read stream", err, "url", upstreamURL)
-			}
-			return false
-		}
-		return true
-	})
+	router.relayStream(c, resp.Body, upstreamURL, responsesIdleEvent, nil)
 }
 
 // ImagesHandler implements an OpenAI-compatible POST /v1/images/generations
@@ -1194,7 +1226,7 @@ func (router *RouterImpl) ResponsesHandler(c *gin.Context) {
 func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 	if !router.cfg.EnableImages {
 		router.logger.Error("images api not enabled", nil)
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "The Images API is not enabled. Set ENABLE_IMAGES=true to enable it."})
+		upstreamError(c, requestError(http.StatusNotFound, "The Images API is not enabled. Set ENABLE_IMAGES=true to enable it."))
 		return
 	}
 
@@ -1202,11 +1234,11 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)))
 	if err != nil {
 		router.logger.Error("failed to read request body", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read request"})
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to read request"))
 		return
 	}
 	if len(body) >= maxBodySize {
-		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body too large"})
+		upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
 		return
 	}
 
@@ -1215,7 +1247,7 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 	}
 	if err := json.Unmarshal(body, &req); err != nil {
 		router.logger.Error("failed to decode request", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to decode request"})
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
 		return
 	}
 
@@ -1231,7 +1263,7 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 		providerPtr, model = routing.DetermineProviderAndModelName(model)
 		if providerPtr == nil {
 			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-image-2)."})
+			upstreamError(c, requestError(http.StatusBadRequest, "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-image-2)."))
 			return
 		}
 		providerID = *providerPtr
@@ -1239,12 +1271,12 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 
 	if providerID == "" {
 		router.logger.Error("no provider specified for images request", nil)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "No provider specified. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-image-2)."})
+		upstreamError(c, requestError(http.StatusBadRequest, "No provider specified. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-image-2)."))
 		return
 	}
 
 	if reason := router.modelDenied(originalModel); reason != "" {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: reason})
+		upstreamError(c, deniedError(reason))
 		return
 	}
 
@@ -1252,17 +1284,17 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 	if err != nil {
 		if strings.Contains(err.Error(), "token not configured") {
 			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider requires an API key. Please configure the provider's API key."})
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
 			return
 		}
 		router.logger.Error("provider not found or not supported", err, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider not found. Please check the list of supported providers."})
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
 		return
 	}
 
 	if provider.GetEndpoints().Images == nil || *provider.GetEndpoints().Images == "" {
 		router.logger.Error("images api not supported by provider", nil, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "The Images API is not supported by this provider yet."})
+		upstreamError(c, requestError(http.StatusBadRequest, "The Images API is not supported by this provider yet."))
 		return
 	}
 
@@ -1272,13 +1304,13 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 		var payload map[string]any
 		if err := dec.Decode(&payload); err != nil {
 			router.logger.Error("failed to decode request", err)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to decode request"})
+			upstreamError(c, requestError(http.StatusBadRequest, "Failed to decode request"))
 			return
 		}
 		payload["model"] = model
 		if body, err = json.Marshal(payload); err != nil {
 			router.logger.Error("failed to encode request", err)
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to encode request"})
+			upstreamError(c, serverError(http.StatusInternalServerError, "Failed to encode request"))
 			return
 		}
 	}
@@ -1290,15 +1322,15 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
 	if err != nil {
 		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create upstream request"})
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
 		return
 	}
 	upstreamReq.Header.Set("Content-Type", "application/json")
 	upstreamReq.Header.Set("Accept", "application/json")
 
-	if err := applyProviderAuth(upstreamReq, provider); err != nil {
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
 		router.logger.Error("unsupported auth type", err, "provider", providerID)
-		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: "Unsupported auth type"})
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
 		return
 	}
 
@@ -1308,11 +1340,10 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			router.logger.Error("request timed out", err, "provider", providerID)
-			c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "Request timed out"})
-			return
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
 		}
-		router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
-		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "Failed to reach upstream server"})
+		upstreamError(c, transportError(ctx, err))
 		return
 	}
 	defer resp.Body.Close()
@@ -1323,7 +1354,7 @@ func (router *RouterImpl) ImagesHandler(c *gin.Context) {
 		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
 	}
 
-	c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, nil)
+	c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, relayHeaders(resp))
 }
 
 // Multipart form field names shared by the /images/edits and
@@ -1334,19 +1365,32 @@ const (
 	// gpt-image-1 edits.
 	imageFormFieldImageArray = "image[]"
 	imageFormFieldPrompt     = "prompt"
-	imageFormFieldModel      = "model"
+	formFieldModel           = "model"
 
-	// imagesMultipartMaxMemory caps how much of a multipart upload is kept in
+	// multipartMaxMemory caps how much of a multipart upload is kept in
 	// memory; parts above it spill to temp files instead.
-	imagesMultipartMaxMemory = 1 << 20
+	multipartMaxMemory = 1 << 20
 )
 
-// imagesMultipartTarget selects which multipart Images endpoint a request is
-// forwarded to and whether a prompt is required (edits require one, variations
-// do not).
-type imagesMultipartTarget struct {
-	endpoint      func(types.Endpoints) *string
+// multipartTarget selects which multipart endpoint a request is forwarded to
+// and what it requires: a prompt for image edits, not for variations.
+type multipartTarget struct {
+	// api names the API in error messages, e.g. "Images"
+	api string
+	// enabled reports whether the API is on, and flag is the setting turning
+	// it on
+	enabled  func(config.Config) bool
+	flag     string
+	endpoint func(types.Endpoints) *string
+	// fileFields are the fields of the upload, one of which is required, and
+	// missingFile the error when none is there
+	fileFields    []string
+	missingFile   string
 	requirePrompt bool
+	// accept is the Accept header of the upstream request, if any
+	accept string
+	// exampleModel illustrates the provider/model format in error messages
+	exampleModel string
 }
 
 // proxyTransport wraps http.DefaultTransport with OpenTelemetry instrumentation
@@ -1354,54 +1398,69 @@ type imagesMultipartTarget struct {
 var proxyTransport = otelhttp.NewTransport(http.DefaultTransport, client.SpanNameFormatter())
 
 var (
-	imagesEditsTarget = imagesMultipartTarget{
+	imagesEditsTarget = multipartTarget{
+		api:           "Images",
+		enabled:       func(cfg config.Config) bool { return cfg.EnableImages },
+		flag:          "ENABLE_IMAGES",
 		endpoint:      func(e types.Endpoints) *string { return e.ImagesEdits },
+		fileFields:    []string{imageFormFieldImage, imageFormFieldImageArray},
+		missingFile:   "The 'image' file is required.",
 		requirePrompt: true,
+		accept:        "application/json",
+		exampleModel:  "openai/gpt-image-2",
 	}
-	imagesVariationsTarget = imagesMultipartTarget{
+	imagesVariationsTarget = multipartTarget{
+		api:           "Images",
+		enabled:       func(cfg config.Config) bool { return cfg.EnableImages },
+		flag:          "ENABLE_IMAGES",
 		endpoint:      func(e types.Endpoints) *string { return e.ImagesVariations },
+		fileFields:    []string{imageFormFieldImage, imageFormFieldImageArray},
+		missingFile:   "The 'image' file is required.",
 		requirePrompt: false,
+		accept:        "application/json",
+		exampleModel:  "openai/gpt-image-2",
 	}
 )
 
 // ImagesEditsHandler implements POST /v1/images/edits (multipart/form-data).
 func (router *RouterImpl) ImagesEditsHandler(c *gin.Context) {
-	router.handleImagesMultipart(c, imagesEditsTarget)
+	router.handleMultipart(c, imagesEditsTarget)
 }
 
 // ImagesVariationsHandler implements POST /v1/images/variations
 // (multipart/form-data).
 func (router *RouterImpl) ImagesVariationsHandler(c *gin.Context) {
-	router.handleImagesMultipart(c, imagesVariationsTarget)
+	router.handleMultipart(c, imagesVariationsTarget)
 }
 
-// handleImagesMultipart proxies a multipart Images upload to the resolved
+// handleMultipart proxies a multipart upload (images, audio) to the resolved
 // provider. It parses the form to validate required fields and resolve the
 // provider (via ?provider= or the model prefix), then re-encodes the parts and
-// streams them to the upstream through an io.Pipe so the binary image/mask
-// files are streamed to the upstream without a second full in-memory copy
+// streams them to the upstream through an io.Pipe so the binary files are
+// streamed to the upstream without a second full in-memory copy
 // (ParseMultipartForm has already spilled anything over 1 MiB to temp files).
 //
-// Behaviour mirrors ImagesHandler: opt-in via ENABLE_IMAGES (404 when off) and
-// only providers that natively implement the endpoint are supported (others
-// receive a 400).
-func (router *RouterImpl) handleImagesMultipart(c *gin.Context, target imagesMultipartTarget) {
-	if !router.cfg.EnableImages {
-		router.logger.Error("images api not enabled", nil)
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "The Images API is not enabled. Set ENABLE_IMAGES=true to enable it."})
+// Behaviour mirrors ImagesHandler: opt-in via the flag of the target (404 when
+// off) and only providers that natively implement the endpoint are supported
+// (others receive a 400). Streamed responses (stream=true transcriptions) are
+// relayed as they arrive.
+func (router *RouterImpl) handleMultipart(c *gin.Context, target multipartTarget) {
+	if !target.enabled(router.cfg) {
+		router.logger.Error("api not enabled", nil, "api", target.api)
+		upstreamError(c, requestError(http.StatusNotFound, fmt.Sprintf("The %s API is not enabled. Set %s=true to enable it.", target.api, target.flag)))
 		return
 	}
 
 	maxBodySize := router.cfg.Server.ResolveMaxRequestBodySize()
 	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(maxBodySize))
-	if err := c.Request.ParseMultipartForm(imagesMultipartMaxMemory); err != nil {
+	if err := c.Request.ParseMultipartForm(multipartMaxMemory); err != nil {
 		var maxErr *http.MaxBytesError
 		if errors.As(err, &maxErr) {
-			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body too large"})
+			upstreamError(c, requestError(http.StatusRequestEntityTooLarge, "Request body too large"))
 			return
 		}
 		router.logger.Error("failed to parse multipart form", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to parse multipart/form-data request"})
+		upstreamError(c, requestError(http.StatusBadRequest, "Failed to parse multipart/form-data request"))
 		return
 	}
 	defer func() {
@@ -1412,39 +1471,43 @@ func (router *RouterImpl) handleImagesMultipart(c *gin.Context, target imagesMul
 
 	form := c.Request.MultipartForm
 
-	if len(form.File[imageFormFieldImage])+len(form.File[imageFormFieldImageArray]) == 0 {
-		router.logger.Error("images request missing image file", nil)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "The 'image' file is required."})
+	files := 0
+	for _, field := range target.fileFields {
+		files += len(form.File[field])
+	}
+	if files == 0 {
+		router.logger.Error("multipart request missing file", nil, "api", target.api, "field", target.fileFields[0])
+		upstreamError(c, requestError(http.StatusBadRequest, target.missingFile))
 		return
 	}
-	if target.requirePrompt && strings.TrimSpace(imagesFormValue(form, imageFormFieldPrompt)) == "" {
+	if target.requirePrompt && strings.TrimSpace(formValue(form, imageFormFieldPrompt)) == "" {
 		router.logger.Error("images edit request missing prompt", nil)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "The 'prompt' field is required."})
+		upstreamError(c, requestError(http.StatusBadRequest, "The 'prompt' field is required."))
 		return
 	}
 
 	providerID := types.Provider(c.Query("provider"))
-	model := imagesFormValue(form, imageFormFieldModel)
+	model := formValue(form, formFieldModel)
 	originalModel := model
 	if providerID == "" && model != "" {
 		var providerPtr *types.Provider
 		providerPtr, model = routing.DetermineProviderAndModelName(model)
 		if providerPtr == nil {
 			router.logger.Error("unable to determine provider for model", nil, "model", originalModel)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-image-2)."})
+			upstreamError(c, requestError(http.StatusBadRequest, fmt.Sprintf("Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., %s).", target.exampleModel)))
 			return
 		}
 		providerID = *providerPtr
 	}
 
 	if providerID == "" {
-		router.logger.Error("no provider specified for images request", nil)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "No provider specified. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-image-2)."})
+		router.logger.Error("no provider specified for multipart request", nil, "api", target.api)
+		upstreamError(c, requestError(http.StatusBadRequest, fmt.Sprintf("No provider specified. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., %s).", target.exampleModel)))
 		return
 	}
 
 	if reason := router.modelDenied(originalModel); reason != "" {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: reason})
+		upstreamError(c, deniedError(reason))
 		return
 	}
 
@@ -1452,33 +1515,38 @@ func (router *RouterImpl) handleImagesMultipart(c *gin.Context, target imagesMul
 	if err != nil {
 		if strings.Contains(err.Error(), "token not configured") {
 			router.logger.Error("provider requires authentication but no api key was configured", err, "provider", providerID)
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider requires an API key. Please configure the provider's API key."})
+			upstreamError(c, providerError(http.StatusBadRequest, "Provider requires an API key. Please configure the provider's API key."))
 			return
 		}
 		router.logger.Error("provider not found or not supported", err, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Provider not found. Please check the list of supported providers."})
+		upstreamError(c, providerError(http.StatusBadRequest, "Provider not found. Please check the list of supported providers."))
 		return
 	}
 
 	endpoint := target.endpoint(provider.GetEndpoints())
 	if endpoint == nil || *endpoint == "" {
-		router.logger.Error("images api not supported by provider", nil, "provider", providerID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "The Images API is not supported by this provider yet."})
+		router.logger.Error("api not supported by provider", nil, "api", target.api, "provider", providerID)
+		upstreamError(c, requestError(http.StatusBadRequest, fmt.Sprintf("The %s API is not supported by this provider yet.", target.api)))
 		return
 	}
 
 	if model != originalModel {
-		form.Value[imageFormFieldModel] = []string{model}
+		form.Value[formFieldModel] = []string{model}
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), router.cfg.Server.ReadTimeout)
-	defer cancel()
+	ctx := c.Request.Context()
+	isStreaming := formValue(form, "stream") == "true"
+	if !isStreaming {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, router.cfg.Server.ReadTimeout)
+		defer cancel()
+	}
 
 	pr, pw := io.Pipe()
 	mw := multipart.NewWriter(pw)
 	contentType := mw.FormDataContentType()
 	go func() {
-		pw.CloseWithError(writeImagesMultipartForm(mw, form))
+		pw.CloseWithError(writeMultipartForm(mw, form))
 	}()
 
 	upstreamURL := strings.TrimSuffix(provider.GetURL(), "/") + *endpoint
@@ -1486,16 +1554,18 @@ func (router *RouterImpl) handleImagesMultipart(c *gin.Context, target imagesMul
 	if err != nil {
 		_ = pr.CloseWithError(err)
 		router.logger.Error("failed to create upstream request", err, "url", upstreamURL)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create upstream request"})
+		upstreamError(c, serverError(http.StatusInternalServerError, "Failed to create upstream request"))
 		return
 	}
 	upstreamReq.Header.Set("Content-Type", contentType)
-	upstreamReq.Header.Set("Accept", "application/json")
+	if target.accept != "" {
+		upstreamReq.Header.Set("Accept", target.accept)
+	}
 
-	if err := applyProviderAuth(upstreamReq, provider); err != nil {
+	if err := core.ApplyAuth(upstreamReq, provider); err != nil {
 		_ = pr.CloseWithError(err)
 		router.logger.Error("unsupported auth type", err, "provider", providerID)
-		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: "Unsupported auth type"})
+		upstreamError(c, providerError(http.StatusUnprocessableEntity, "Unsupported auth type"))
 		return
 	}
 
@@ -1505,11 +1575,10 @@ func (router *RouterImpl) handleImagesMultipart(c *gin.Context, target imagesMul
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			router.logger.Error("request timed out", err, "provider", providerID)
-			c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "Request timed out"})
-			return
+		} else {
+			router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
 		}
-		router.logger.Error("failed to reach upstream server", err, "url", upstreamURL)
-		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "Failed to reach upstream server"})
+		upstreamError(c, transportError(ctx, err))
 		return
 	}
 	defer resp.Body.Close()
@@ -1520,23 +1589,28 @@ func (router *RouterImpl) handleImagesMultipart(c *gin.Context, target imagesMul
 		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
 	}
 
-	c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, nil)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		middlewares.SetSSEHeaders(c)
+		router.relayStream(c, resp.Body, upstreamURL, chatIdleEvent, nil)
+		return
+	}
+	c.DataFromReader(resp.StatusCode, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, relayHeaders(resp))
 }
 
-// imagesFormValue returns the first value for key in a parsed multipart form,
-// or "" when absent.
-func imagesFormValue(form *multipart.Form, key string) string {
+// formValue returns the first value for key in a parsed multipart form, or ""
+// when absent.
+func formValue(form *multipart.Form, key string) string {
 	if vs := form.Value[key]; len(vs) > 0 {
 		return vs[0]
 	}
 	return ""
 }
 
-// writeImagesMultipartForm re-encodes a parsed multipart form onto mw, copying
+// writeMultipartForm re-encodes a parsed multipart form onto mw, copying
 // each uploaded file straight through (preserving its Content-Type) so the
 // payload streams to the upstream without a second full in-memory copy. It
 // runs in its own goroutine writing to the io.Pipe.
-func writeImagesMultipartForm(mw *multipart.Writer, form *multipart.Form) error {
+func writeMultipartForm(mw *multipart.Writer, form *multipart.Form) error {
 	for field, values := range form.Value {
 		for _, v := range values {
 			if err := mw.WriteField(field, v); err != nil {
@@ -1546,7 +1620,7 @@ func writeImagesMultipartForm(mw *multipart.Writer, form *multipart.Form) error
 	}
 	for field, headers := range form.File {
 		for _, fh := range headers {
-			if err := copyImagesFormFile(mw, field, fh); err != nil {
+			if err := copyFormFile(mw, field, fh); err != nil {
 				return err
 			}
 		}
@@ -1554,7 +1628,7 @@ func writeImagesMultipartForm(mw *multipart.Writer, form *multipart.Form) error
 	return mw.Close()
 }
 
-func copyImagesFormFile(mw *multipart.Writer, field string, fh *multipart.FileHeader) error {
+func copyFormFile(mw *multipart.Writer, field string, fh *multipart.FileHeader) error {
 	src, err := fh.Open()
 	if err != nil {
 		return err
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"strings"
+
+	gin "github.com/gin-gonic/gin"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// shape applies the request shaping rules matching the model of a chat
+// completion, as requested or as resolved to provider/model. It returns false
+// after writing an error response when the shaped request cannot be built.
+func (router *RouterImpl) shape(c *gin.Context, provider types.Provider, originalModel string, req *types.CreateChatCompletionRequest) bool {
+	if router.shaper == nil {
+		return true
+	}
+
+	changes, err := router.shaper.Apply(req, originalModel, string(provider)+"/"+req.Model)
+	if err != nil {
+		router.logger.Error("failed to shape request", err, "provider", provider, "model", req.Model)
+		upstreamError(c, shapingError)
+		return false
+	}
+	if len(changes) == 0 {
+		return true
+	}
+
+	applied := make([]string, 0, len(changes))
+	for _, change := range changes {
+		applied = append(applied, change.String())
+	}
+	router.logger.Debug("shaped request parameters",
+		"provider", provider,
+		"model", req.Model,
+		"changes", strings.Join(applied, ", "))
+	return true
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+)
+
+// streamLine is a line read from an upstream stream, with the error ending
+// the stream after it, if any
+type streamLine struct {
+	data []byte
+	err  error
+}
+
+// readLines reads the lines of an upstream stream in the background, so that
+// relays can wait for them along with their watchdog. The channel is closed
+// when the stream ends, fails or ctx is done.
+func readLines(ctx context.Context, r io.Reader) <-chan streamLine {
+	lines := make(chan streamLine)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReaderSize(r, 4096)
+		for {
+			data, err := reader.ReadBytes('\n')
+			if len(data) > 0 || err != nil {
+				select {
+				case lines <- streamLine{data: data, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// newStreamWatchdog starts the watchdog of a streaming response
+func (router *RouterImpl) newStreamWatchdog() *middlewares.StreamWatchdog {
+	return middlewares.NewStreamWatchdog(router.cfg.Server.StreamKeepaliveInterval, router.cfg.Server.StreamIdleTimeout)
+}
+
+// errStreamIdle is the error ending a stream whose upstream went silent
+func errStreamIdle(timeout time.Duration) *core.UpstreamError {
+	return &core.UpstreamError{
+		Code:       core.ErrorCodeTimeout,
+		StatusCode: http.StatusGatewayTimeout,
+		Message:    fmt.Sprintf("upstream stream idle for %s", timeout),
+	}
+}
+
+// chatIdleEvent is the OpenAI error event ending an idle stream
+func chatIdleEvent(timeout time.Duration) []byte {
+	return core.StreamEvent{Err: errStreamIdle(timeout)}.SSE()
+}
+
+// messagesIdleEvent is the Anthropic error event ending an idle stream
+func messagesIdleEvent(timeout time.Duration) []byte {
+	data, _ := json.Marshal(errStreamIdle(timeout).Anthropic())
+	return fmt.Appendf(nil, "event: error\ndata: %s\n\n", data)
+}
+
+// responsesIdleEvent is the Responses API error event ending an idle stream
+func responsesIdleEvent(timeout time.Duration) []byte {
+	data, _ := json.Marshal(errStreamIdle(timeout).ResponsesEvent())
+	return fmt.Appendf(nil, "event: error\ndata: %s\n\n", data)
+}
+
+// relayStream relays an upstream server-sent event stream to the client line
+// by line. Keep-alive comments are written while the upstream is quiet, and
+// the stream ends with idleEvent once the upstream has been silent for the
+// stream idle timeout. onLine, if set, sees every relayed line.
+func (router *RouterImpl) relayStream(c *gin.Context, body io.Reader, upstreamURL string, idleEvent func(time.Duration) []byte, onLine func([]byte)) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	watchdog := router.newStreamWatchdog()
+	defer watchdog.Stop()
+
+	lines := readLines(ctx, body)
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return false
+			}
+			watchdog.Received()
+			if len(line.data) > 0 {
+				if onLine != nil {
+					onLine(line.data)
+				}
+				middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+				if _, err := w.Write(line.data); err != nil {
+					router.logger.Error("failed to write chunk", err)
+					return false
+				}
+				if flusher, ok := w.(http.Flusher); ok {
+					flusher.Flush()
+				}
+				watchdog.Sent()
+			}
+			if line.err != nil {
+				// The upstream request carries the client's context, so
+				// cancellation surfaces here as a read error.
+				if line.err != io.EOF && ctx.Err() == nil {
+					router.logger.Error("failed to read stream", line.err, "url", upstreamURL)
+				}
+				return false
+			}
+			return true
+		case <-watchdog.KeepAlive():
+			middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+			if err := middlewares.WriteKeepAlive(w); err != nil {
+				router.logger.Error("failed to write keep-alive", err)
+				return false
+			}
+			watchdog.Sent()
+			return true
+		case <-watchdog.Idle():
+			router.logger.Warn("aborting idle stream", "url", upstreamURL, "idle_timeout", watchdog.IdleTimeout())
+			middlewares.ResetWriteDeadline(c, router.cfg.Server.WriteTimeout)
+			if _, err := w.Write(idleEvent(watchdog.IdleTimeout())); err != nil {
+				router.logger.Error("failed to write chunk", err)
+			}
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
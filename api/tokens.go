@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	tokens "github.com/inference-gateway/inference-gateway/internal/tokens"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// contextWindow returns the context window of a model: from the model
+// catalog when it is enabled and has listed the model, otherwise from the
+// local runtime serving it (see runtimeContextWindow) or the community table.
+func (router *RouterImpl) contextWindow(ctx context.Context, provider types.Provider, model string) (int, bool) {
+	if router.catalog != nil {
+		if tokens, ok := router.catalog.contextWindow(provider, model); ok {
+			return tokens, true
+		}
+	}
+	if tokens, ok := router.runtimeContextWindow(ctx, provider, model); ok {
+		return tokens, true
+	}
+	return core.CommunityContextWindow(provider, model)
+}
+
+// contextWindowExceeded checks a chat completion against the context window
+// of its model and returns the error message for requests that do not fit,
+// or "" when the request fits or the window is unknown. Requests asking for
+// a completion budget must fit the prompt and the budget.
+func (router *RouterImpl) contextWindowExceeded(ctx context.Context, provider types.Provider, req types.CreateChatCompletionRequest) string {
+	window, ok := router.contextWindow(ctx, provider, req.Model)
+	if !ok {
+		return ""
+	}
+
+	prompt := tokens.ChatCompletion(req.Model, req)
+	budget := tokens.CompletionBudget(req)
+	if prompt.Tokens+budget <= window {
+		return ""
+	}
+
+	router.logger.Debug("request exceeds the context window", "provider", provider, "model", req.Model, "window", window, "prompt_tokens", prompt.Tokens, "completion_tokens", budget, "method", prompt.Method)
+	if budget == 0 {
+		return fmt.Sprintf("This model's maximum context length is %d tokens, however the messages take %d tokens. Please reduce the length of the messages.", window, prompt.Tokens)
+	}
+	return fmt.Sprintf("This model's maximum context length is %d tokens, however you requested %d tokens (%d in the messages, %d in the completion). Please reduce the length of the messages or completion.", window, prompt.Tokens+budget, prompt.Tokens, budget)
+}
+
+// TokenizeHandler counts the prompt tokens of chat messages or plain text for
+// a model without calling the provider.
+//
+// Request format:
+//
+//	{
+//	  "model": "openai/gpt-4o",
+//	  "messages": [{"role": "user", "content": "Hello"}]
+//	}
+//
+// Response format:
+//
+//	{
+//	  "model": "openai/gpt-4o",
+//	  "tokens": 9,
+//	  "method": "tiktoken",
+//	  "encoding": "o200k_base",
+//	  "context_window": 128000
+//	}
+//
+// The count of a messages request includes the chat formatting overhead and
+// is what the context-window check (ENFORCE_CONTEXT_WINDOW) compares against
+// the model's window.
+func (router *RouterImpl) TokenizeHandler(c *gin.Context) {
+	raw, err := middlewares.GetRequestBody(c, router.cfg).Bytes()
+	if err != nil {
+		if errors.Is(err, middlewares.ErrRequestBodyTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body too large"})
+			return
+		}
+		router.logger.Error("failed to read request", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read request"})
+		return
+	}
+	var req types.TokenizeRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		router.logger.Error("failed to decode request", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to decode request"})
+		return
+	}
+	if req.Model == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Model is required"})
+		return
+	}
+	if (req.Messages == nil) == (req.Input == nil) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Exactly one of messages or input is required"})
+		return
+	}
+
+	providerID := types.Provider(c.Query("provider"))
+	model := req.Model
+	if router.selector != nil && providerID == "" {
+		if dep, ok := router.selector.Select(model); ok {
+			providerID, model = types.Provider(dep.Provider), dep.Model
+		}
+	}
+	if providerID == "" {
+		var providerPtr *types.Provider
+		providerPtr, model = routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-4)."})
+			return
+		}
+		providerID = *providerPtr
+	}
+
+	counter := tokens.ForModel(model)
+	response := types.TokenizeResponse{
+		Model:  req.Model,
+		Method: counter.Method(),
+	}
+	if req.Input != nil {
+		response.Tokens = counter.Text(*req.Input)
+	} else {
+		response.Tokens = counter.Messages(*req.Messages, req.Tools)
+	}
+	if encoding := counter.Encoding(); encoding != "" {
+		response.Encoding = &encoding
+	}
+	if window, ok := router.contextWindow(c.Request.Context(), providerID, model); ok {
+		response.ContextWindow = &window
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	gin "github.com/gin-gonic/gin"
+
+	tokens "github.com/inference-gateway/inference-gateway/internal/tokens"
+	truncation "github.com/inference-gateway/inference-gateway/internal/truncation"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+const (
+	// TruncationStrategyHeader picks the truncation strategy of a request
+	// (or none to opt out). On responses it reports the strategy applied.
+	TruncationStrategyHeader = "X-Truncation-Strategy"
+	// TruncatedMessagesHeader lists the indices of the request messages that
+	// were removed, as ranges, e.g. "1-4,7"
+	TruncatedMessagesHeader = "X-Truncated-Messages"
+	// TruncatedTokensHeader carries the number of prompt tokens removed
+	TruncatedTokensHeader = "X-Truncated-Tokens"
+)
+
+// truncate trims the messages of a chat completion that does not fit the
+// context window of its model, when truncation is enabled for the model or
+// requested with the X-Truncation-Strategy header, and reports what was
+// removed in the response headers. It returns false after writing an error
+// response for an invalid header.
+func (router *RouterImpl) truncate(ctx context.Context, c *gin.Context, provider types.Provider, originalModel string, req *types.CreateChatCompletionRequest) bool {
+	result, err := router.truncateMessages(ctx, c.GetHeader(TruncationStrategyHeader), provider, originalModel, req)
+	if err != nil {
+		upstreamError(c, requestError(http.StatusBadRequest, err.Error()))
+		return false
+	}
+	if result != nil {
+		c.Header(TruncationStrategyHeader, result.Strategy)
+		c.Header(TruncatedMessagesHeader, truncation.FormatRemoved(result.Removed))
+		c.Header(TruncatedTokensHeader, strconv.Itoa(result.RemovedTokens))
+	}
+	return true
+}
+
+// truncateMessages trims the messages of req as truncate does, with the
+// strategy name requested for the request (empty for the configured one). It
+// returns the truncation applied, nil when the messages are left as they are,
+// and fails for an invalid strategy.
+//
+// Conversations that cannot be made to fit are sent as they are, so the
+// provider (or ENFORCE_CONTEXT_WINDOW) reports the overflow.
+func (router *RouterImpl) truncateMessages(ctx context.Context, name string, provider types.Provider, originalModel string, req *types.CreateChatCompletionRequest) (*truncation.Result, error) {
+	settings := router.cfg.Truncation
+	if settings == nil || !settings.Enabled {
+		return nil, nil
+	}
+
+	if name == "" {
+		if !routing.ModelMatches(routing.ParseModelSet(settings.Models), originalModel) {
+			return nil, nil
+		}
+		name = settings.Strategy
+	}
+	strategy, err := truncation.ParseStrategy(name)
+	if err != nil {
+		return nil, errors.New("Invalid " + TruncationStrategyHeader + " header: " + err.Error())
+	}
+	if strategy == truncation.StrategyNone {
+		return nil, nil
+	}
+	if strategy == truncation.StrategySummarizeMiddle && router.summarizer == nil {
+		return nil, errors.New("The summarize_middle truncation strategy requires TRUNCATION_SUMMARY_MODEL to be configured")
+	}
+
+	window, ok := router.contextWindow(ctx, provider, req.Model)
+	if !ok {
+		return nil, nil
+	}
+	budget := window - tokens.CompletionBudget(*req)
+	if budget <= 0 {
+		return nil, nil
+	}
+
+	result, err := truncation.Truncate(ctx, req.Messages, req.Tools, tokens.ForModel(req.Model), budget, truncation.Options{
+		Strategy:         strategy,
+		KeepFirst:        settings.KeepFirst,
+		Summarizer:       router.summarizer,
+		SummaryMaxTokens: settings.SummaryMaxTokens,
+	})
+	if errors.Is(err, truncation.ErrDoesNotFit) {
+		router.logger.Debug("conversation does not fit the context window even when truncated", "provider", provider, "model", req.Model, "window", window, "strategy", strategy)
+		return nil, nil
+	}
+	if err != nil || !result.Truncated() {
+		return nil, nil
+	}
+	if result.SummaryErr != nil {
+		router.logger.Warn("failed to summarize truncated messages, dropping them instead", "provider", provider, "model", req.Model, "error", result.SummaryErr.Error())
+	}
+
+	router.logger.Debug("truncated conversation to fit the context window",
+		"provider", provider,
+		"model", req.Model,
+		"window", window,
+		"strategy", result.Strategy,
+		"removedMessages", len(result.Removed),
+		"removedTokens", result.RemovedTokens)
+
+	req.Messages = result.Messages
+	return &result, nil
+}
@@ -0,0 +1,682 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	websocket "github.com/gorilla/websocket"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// WebSocketProtocol is the subprotocol of /v1/ws connections
+const WebSocketProtocol = "inference-gateway.v1"
+
+// Messages sent by WebSocket clients
+const (
+	// WSSessionCreate opens a session with a chat completion request, which
+	// is generated right away when it has messages
+	WSSessionCreate = "session.create"
+	// WSSessionMessage appends a message (a user turn, or the result of a
+	// client-side tool call) to a session and generates the next turn
+	WSSessionMessage = "session.message"
+	// WSSessionCancel cancels the generation running in a session
+	WSSessionCancel = "session.cancel"
+	// WSSessionClose cancels the generation of a session and closes it
+	WSSessionClose = "session.close"
+	// WSToolApproval answers a tool approval request
+	WSToolApproval = "tool.approval"
+)
+
+// Messages sent by the gateway
+const (
+	WSSessionCreated      = "session.created"
+	WSSessionClosed       = "session.closed"
+	WSChunk               = "chunk"
+	WSToolApprovalRequest = "tool.approval_request"
+	WSResponseDone        = "response.done"
+	WSResponseCancelled   = "response.cancelled"
+	WSError               = "error"
+)
+
+// wsClientMessage is a message sent by a WebSocket client
+type wsClientMessage struct {
+	Type    string `json:"type"`
+	Session string `json:"session"`
+	// Request is the chat completion request of session.create; its model,
+	// tools and parameters apply to every turn of the session
+	Request *types.CreateChatCompletionRequest `json:"request,omitempty"`
+	// ToolApproval makes session.create ask the client before each MCP tool
+	// call of the session
+	ToolApproval bool `json:"tool_approval,omitempty"`
+	// Message is the message of session.message
+	Message *types.Message `json:"message,omitempty"`
+	// ToolCallID and Approved answer a tool approval request
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	Approved   bool   `json:"approved,omitempty"`
+}
+
+// wsServerMessage is a message sent by the gateway to a WebSocket client
+type wsServerMessage struct {
+	Type    string `json:"type"`
+	Session string `json:"session,omitempty"`
+	// Chunk is a chat completion chunk, as streamed by /v1/chat/completions
+	Chunk    json.RawMessage                      `json:"chunk,omitempty"`
+	ToolCall *types.ChatCompletionMessageToolCall `json:"tool_call,omitempty"`
+	Error    *core.OpenAIError                    `json:"error,omitempty"`
+}
+
+// wsConn serializes the writes to a WebSocket connection
+type wsConn struct {
+	conn         *websocket.Conn
+	writeTimeout time.Duration
+	mu           sync.Mutex
+}
+
+func (w *wsConn) send(msg wsServerMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return w.write(websocket.TextMessage, data)
+}
+
+func (w *wsConn) write(messageType int, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.writeTimeout > 0 {
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+	}
+	return w.conn.WriteMessage(messageType, data)
+}
+
+func (w *wsConn) sendError(session string, err error) error {
+	upstreamErr := core.ClassifyError(err).OpenAI().Error
+	return w.send(wsServerMessage{Type: WSError, Session: session, Error: &upstreamErr})
+}
+
+// errWSRequest is an invalid message of a WebSocket client
+func errWSRequest(format string, args ...any) error {
+	return &core.UpstreamError{Code: core.ErrorCodeInvalidRequest, StatusCode: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+// wsSession is a chat completion session multiplexed over a WebSocket
+type wsSession struct {
+	id           string
+	request      types.CreateChatCompletionRequest
+	toolApproval bool
+
+	mu       sync.Mutex
+	messages []types.Message
+	// cancel cancels the running generation; nil when idle
+	cancel    context.CancelFunc
+	approvals map[string]chan bool
+}
+
+// SetAgentFactory sets how WebSocket sessions build their MCP agent. Without
+// one, WebSocket sessions do not run the agent loop.
+func (router *RouterImpl) SetAgentFactory(factory func() mcp.Agent) {
+	router.newAgent = factory
+}
+
+// SetGuardrails sets the guardrails the turns of WebSocket sessions go
+// through; only the upgrade request goes through the middleware chain.
+func (router *RouterImpl) SetGuardrails(checker middlewares.GuardrailsChecker) {
+	router.guardrails = checker
+}
+
+// WebSocketHandler serves GET /v1/ws: chat completion sessions multiplexed
+// over a WebSocket. Clients open sessions with a chat completion request, send
+// new turns, cancel generations and answer tool approval requests; every
+// generation streams the chunks of /v1/chat/completions, running the MCP agent
+// loop when MCP tools are available. The upgrade request goes through the
+// middlewares like any other, authentication included.
+func (router *RouterImpl) WebSocketHandler(c *gin.Context) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{WebSocketProtocol},
+		CheckOrigin:  router.checkWebSocketOrigin,
+	}
+	conn, err := router.upgradeWebSocket(c, upgrader)
+	if err != nil {
+		// The upgrader has answered the request already
+		router.logger.Error("failed to upgrade websocket", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	var generations sync.WaitGroup
+	defer func() {
+		cancel()
+		generations.Wait()
+	}()
+
+	ws := &wsConn{conn: conn, writeTimeout: router.cfg.Server.WriteTimeout}
+	if interval := router.cfg.Server.StreamKeepaliveInterval; interval > 0 {
+		go router.pingWebSocket(ctx, ws, interval)
+	}
+
+	sessions := make(map[string]*wsSession)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				router.logger.Error("failed to read websocket message", err)
+			}
+			return
+		}
+
+		var msg wsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			_ = ws.sendError("", errWSRequest("Failed to decode message"))
+			continue
+		}
+		if err := router.handleWebSocketMessage(ctx, ws, sessions, msg, &generations); err != nil {
+			if sendErr := ws.sendError(msg.Session, err); sendErr != nil {
+				router.logger.Error("failed to write websocket message", sendErr)
+				return
+			}
+		}
+	}
+}
+
+// upgradeWebSocket upgrades the request to a WebSocket, limiting the messages
+// of the client to the maximum request body size
+func (router *RouterImpl) upgradeWebSocket(c *gin.Context, upgrader websocket.Upgrader) (*websocket.Conn, error) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The deadlines of the server outlive the hijacking of the connection
+	_ = conn.NetConn().SetDeadline(time.Time{})
+	if limit := router.cfg.Server.ResolveMaxRequestBodySize(); limit > 0 {
+		conn.SetReadLimit(int64(limit))
+	}
+	return conn, nil
+}
+
+// handleWebSocketMessage handles a client message, returning the error to
+// report to the client
+func (router *RouterImpl) handleWebSocketMessage(ctx context.Context, ws *wsConn, sessions map[string]*wsSession, msg wsClientMessage, generations *sync.WaitGroup) error {
+	if msg.Session == "" {
+		return errWSRequest("session is required")
+	}
+	session, exists := sessions[msg.Session]
+	if !exists && msg.Type != WSSessionCreate {
+		return errWSRequest("Unknown session %q", msg.Session)
+	}
+
+	switch msg.Type {
+	case WSSessionCreate:
+		if exists {
+			return errWSRequest("Session %q already exists", msg.Session)
+		}
+		if msg.Request == nil || msg.Request.Model == "" {
+			return errWSRequest("session.create requires a request with a model")
+		}
+		session = &wsSession{
+			id:           msg.Session,
+			request:      *msg.Request,
+			toolApproval: msg.ToolApproval,
+			messages:     msg.Request.Messages,
+			approvals:    make(map[string]chan bool),
+		}
+		session.request.Messages = nil
+		sessions[msg.Session] = session
+		if err := ws.send(wsServerMessage{Type: WSSessionCreated, Session: msg.Session}); err != nil {
+			return err
+		}
+		if len(session.messages) == 0 {
+			return nil
+		}
+		if err := router.startGeneration(ctx, ws, session, generations); err != nil {
+			// A rejected first turn is not kept in the history either
+			session.mu.Lock()
+			session.messages = nil
+			session.mu.Unlock()
+			return err
+		}
+		return nil
+
+	case WSSessionMessage:
+		if msg.Message == nil {
+			return errWSRequest("session.message requires a message")
+		}
+		session.mu.Lock()
+		busy := session.cancel != nil
+		if !busy {
+			session.messages = append(session.messages, *msg.Message)
+		}
+		session.mu.Unlock()
+		if busy {
+			return errWSRequest("Session %q is generating; cancel it or wait for response.done", msg.Session)
+		}
+		if err := router.startGeneration(ctx, ws, session, generations); err != nil {
+			// A rejected turn is not kept in the history
+			session.mu.Lock()
+			session.messages = session.messages[:len(session.messages)-1]
+			session.mu.Unlock()
+			return err
+		}
+		return nil
+
+	case WSSessionCancel:
+		session.stop()
+		return nil
+
+	case WSSessionClose:
+		session.stop()
+		delete(sessions, msg.Session)
+		return ws.send(wsServerMessage{Type: WSSessionClosed, Session: msg.Session})
+
+	case WSToolApproval:
+		session.mu.Lock()
+		answer, pending := session.approvals[msg.ToolCallID]
+		delete(session.approvals, msg.ToolCallID)
+		session.mu.Unlock()
+		if !pending {
+			return errWSRequest("No tool approval pending for %q", msg.ToolCallID)
+		}
+		answer <- msg.Approved
+		return nil
+	}
+	return errWSRequest("Unsupported message type %q", msg.Type)
+}
+
+// stop cancels the running generation of the session, if any
+func (s *wsSession) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// startGeneration generates the next turn of a session in the background.
+// Every turn goes through the checks of a chat completion: the pre_call and
+// post_call guardrails, the model allow-lists, shaping, truncation and the
+// context window; its token usage is recorded like the telemetry middleware
+// does.
+func (router *RouterImpl) startGeneration(ctx context.Context, ws *wsConn, session *wsSession, generations *sync.WaitGroup) error {
+	req := session.request
+	session.mu.Lock()
+	req.Messages = append([]types.Message(nil), session.messages...)
+	session.mu.Unlock()
+	stream := true
+	req.Stream = &stream
+	originalModel := req.Model
+
+	if err := router.checkSessionRequest(ctx, &req); err != nil {
+		return err
+	}
+	provider, providerID, err := router.resolveSessionProvider(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	genCtx, cancel := context.WithCancel(ctx)
+	session.mu.Lock()
+	session.cancel = cancel
+	session.mu.Unlock()
+
+	generations.Go(func() {
+		defer cancel()
+		usageCtx, usage := core.WithStreamUsage(genCtx)
+		message, err := router.generate(usageCtx, ws, session, provider, req)
+		router.recordSessionUsage(ctx, providerID, originalModel, usage)
+		if err == nil {
+			message, err = router.checkSessionResponse(genCtx, req.Model, message)
+		}
+
+		session.mu.Lock()
+		session.cancel = nil
+		if err == nil {
+			session.messages = append(session.messages, message)
+		}
+		session.mu.Unlock()
+
+		var sendErr error
+		switch {
+		case err == nil:
+			sendErr = ws.send(wsServerMessage{Type: WSResponseDone, Session: session.id})
+		case genCtx.Err() != nil && ctx.Err() == nil:
+			sendErr = ws.send(wsServerMessage{Type: WSResponseCancelled, Session: session.id})
+		case ctx.Err() == nil:
+			router.logger.Error("websocket generation failed", err, "provider", providerID, "session", session.id)
+			sendErr = ws.sendError(session.id, err)
+		}
+		if sendErr != nil {
+			router.logger.Error("failed to write websocket message", sendErr)
+		}
+	})
+	return nil
+}
+
+// resolveSessionProvider resolves the provider and model of a session
+// request like ChatCompletionsHandler does, and shapes, truncates and checks
+// the request against the context window of the model
+func (router *RouterImpl) resolveSessionProvider(ctx context.Context, req *types.CreateChatCompletionRequest) (core.IProvider, types.Provider, error) {
+	originalModel := req.Model
+	model := req.Model
+	var providerID types.Provider
+	if router.selector != nil {
+		if dep, ok := router.selector.Select(model); ok {
+			providerID, model = types.Provider(dep.Provider), dep.Model
+		}
+	}
+	if providerID == "" {
+		providerPtr, providerModel := routing.DetermineProviderAndModelName(model)
+		if providerPtr == nil {
+			return nil, "", errWSRequest("Unable to determine provider for model. Use the provider/model format (e.g., openai/gpt-4).")
+		}
+		providerID, model = *providerPtr, providerModel
+	}
+	req.Model = model
+
+	if reason := router.modelDenied(originalModel); reason != "" {
+		return nil, "", deniedError(reason)
+	}
+
+	provider, err := router.registry.BuildProvider(providerID, router.client)
+	if err != nil {
+		router.logger.Error("provider not found or not supported", err, "provider", providerID)
+		return nil, "", errWSRequest("Provider not found. Please check the list of supported providers.")
+	}
+
+	if router.shaper != nil {
+		if _, err := router.shaper.Apply(req, originalModel, string(providerID)+"/"+req.Model); err != nil {
+			router.logger.Error("failed to shape request", err, "provider", providerID, "model", req.Model)
+			return nil, "", shapingError
+		}
+	}
+
+	if _, err := router.truncateMessages(ctx, "", providerID, originalModel, req); err != nil {
+		return nil, "", errWSRequest("%s", err.Error())
+	}
+	if router.cfg.EnforceContextWindow {
+		if reason := router.contextWindowExceeded(ctx, providerID, *req); reason != "" {
+			return nil, "", contextLengthError(reason)
+		}
+	}
+	return provider, providerID, nil
+}
+
+// checkSessionRequest runs the pre_call guardrails on a session request,
+// replacing it with its redacted version when the guardrails redact it
+func (router *RouterImpl) checkSessionRequest(ctx context.Context, req *types.CreateChatCompletionRequest) error {
+	if router.guardrails == nil {
+		return nil
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	checked, err := router.guardrails.CheckChatCompletion(ctx, guardrails.PhasePreCall, req.Model, body)
+	if err != nil {
+		return guardrailError(err)
+	}
+	if bytes.Equal(checked, body) {
+		return nil
+	}
+	var redacted types.CreateChatCompletionRequest
+	if err := json.Unmarshal(checked, &redacted); err != nil {
+		router.logger.Error("failed to decode redacted session request", err)
+		return errWSRequest("Failed to apply guardrails")
+	}
+	*req = redacted
+	return nil
+}
+
+// checkSessionResponse runs the post_call guardrails on the assistant message
+// of a turn, as the chat completion it would be over HTTP. The chunks are
+// streamed already, so a blocked or redacted message only changes what the
+// session keeps in its history and whether the turn succeeds.
+func (router *RouterImpl) checkSessionResponse(ctx context.Context, model string, message types.Message) (types.Message, error) {
+	if router.guardrails == nil {
+		return message, nil
+	}
+	body, err := json.Marshal(types.CreateChatCompletionResponse{
+		Object:  "chat.completion",
+		Model:   model,
+		Choices: []types.ChatCompletionChoice{{Index: 0, FinishReason: types.Stop, Message: message}},
+	})
+	if err != nil {
+		return types.Message{}, err
+	}
+	checked, err := router.guardrails.CheckChatCompletion(ctx, guardrails.PhasePostCall, model, body)
+	if err != nil {
+		return types.Message{}, guardrailError(err)
+	}
+	if bytes.Equal(checked, body) {
+		return message, nil
+	}
+	var redacted types.CreateChatCompletionResponse
+	if err := json.Unmarshal(checked, &redacted); err != nil || len(redacted.Choices) == 0 {
+		router.logger.Error("failed to decode redacted session response", err)
+		return types.Message{}, errWSRequest("Failed to apply guardrails")
+	}
+	return redacted.Choices[0].Message, nil
+}
+
+// guardrailError is the error reported to the client for a turn the
+// guardrails blocked
+func guardrailError(err error) error {
+	var blocked *middlewares.GuardrailBlockedError
+	if errors.As(err, &blocked) {
+		return &core.UpstreamError{Code: core.ErrorCodeContentFilter, StatusCode: http.StatusForbidden, Message: blocked.Error()}
+	}
+	return err
+}
+
+// recordSessionUsage records the token usage of a turn, when the provider
+// reported it
+func (router *RouterImpl) recordSessionUsage(ctx context.Context, providerID types.Provider, model string, usage *core.StreamUsage) {
+	total, ok := usage.Usage()
+	if !ok || router.telemetry == nil {
+		return
+	}
+	router.telemetry.RecordTokenUsage(context.WithoutCancel(ctx), otel.SourceGateway, otel.TeamUnknown, string(providerID), model, total.PromptTokens, total.CompletionTokens)
+}
+
+// generate streams a turn of a session to the client, returning the
+// assistant message to add to the session history
+func (router *RouterImpl) generate(ctx context.Context, ws *wsConn, session *wsSession, provider core.IProvider, req types.CreateChatCompletionRequest) (types.Message, error) {
+	if tools := router.mcpTools(); len(tools) > 0 && router.newAgent != nil {
+		req.Tools = &tools
+		return router.generateWithAgent(ctx, ws, session, provider, req)
+	}
+
+	streamCh, err := provider.StreamChatCompletions(ctx, req)
+	if err != nil {
+		return types.Message{}, err
+	}
+
+	watchdog := middlewares.NewStreamWatchdog(0, router.cfg.Server.StreamIdleTimeout)
+	defer watchdog.Stop()
+
+	acc := core.NewStreamAccumulator()
+	for {
+		select {
+		case event, ok := <-streamCh:
+			if !ok {
+				return accumulatedMessage(acc, true)
+			}
+			watchdog.Received()
+			if event.Err != nil {
+				return types.Message{}, event.Err
+			}
+			core.StreamUsageFrom(ctx).Observe(event)
+			acc.Add(*event.Chunk)
+			if err := ws.send(wsServerMessage{Type: WSChunk, Session: session.id, Chunk: event.Data}); err != nil {
+				return types.Message{}, err
+			}
+		case <-watchdog.Idle():
+			return types.Message{}, errStreamIdle(watchdog.IdleTimeout())
+		case <-ctx.Done():
+			return types.Message{}, ctx.Err()
+		}
+	}
+}
+
+// generateWithAgent streams a turn of a session through the MCP agent loop.
+// The tool calls and results of the loop stay internal to the agent, so only
+// the text of the turn is added to the history.
+func (router *RouterImpl) generateWithAgent(ctx context.Context, ws *wsConn, session *wsSession, provider core.IProvider, req types.CreateChatCompletionRequest) (types.Message, error) {
+	agent := router.newAgent()
+	agent.SetProvider(provider)
+	agent.SetModel(&req.Model)
+	if session.toolApproval {
+		agent.SetToolApprover(func(ctx context.Context, toolCall types.ChatCompletionMessageToolCall) (bool, error) {
+			return session.approve(ctx, ws, toolCall)
+		})
+	}
+
+	lines := make(chan []byte, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		errCh <- agent.RunWithStream(ctx, lines, &req)
+	}()
+
+	acc := core.NewStreamAccumulator()
+	var agentErr error
+	for line := range lines {
+		data := bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:")))
+		if len(data) == 0 || bytes.Equal(line, core.StreamDone) {
+			continue
+		}
+		var chunk types.CreateChatCompletionStreamResponse
+		if err := json.Unmarshal(data, &chunk); err != nil || bytes.HasPrefix(data, []byte(`{"error"`)) {
+			// The agent reports its failures in-band; the error it returns
+			// is reported instead
+			continue
+		}
+		acc.Add(chunk)
+		if err := ws.send(wsServerMessage{Type: WSChunk, Session: session.id, Chunk: data}); err != nil && agentErr == nil {
+			agentErr = err
+		}
+	}
+	if err := <-errCh; err != nil {
+		return types.Message{}, err
+	}
+	if agentErr != nil {
+		return types.Message{}, agentErr
+	}
+	return accumulatedMessage(acc, false)
+}
+
+// approve asks the client whether the agent may execute a tool call
+func (s *wsSession) approve(ctx context.Context, ws *wsConn, toolCall types.ChatCompletionMessageToolCall) (bool, error) {
+	answer := make(chan bool, 1)
+	s.mu.Lock()
+	s.approvals[toolCall.ID] = answer
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.approvals, toolCall.ID)
+		s.mu.Unlock()
+	}()
+
+	if err := ws.send(wsServerMessage{Type: WSToolApprovalRequest, Session: s.id, ToolCall: &toolCall}); err != nil {
+		return false, err
+	}
+	select {
+	case approved := <-answer:
+		return approved, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// accumulatedMessage is the assistant message of an accumulated stream, with
+// its tool calls when they are left to the client
+func accumulatedMessage(acc *core.StreamAccumulator, withToolCalls bool) (types.Message, error) {
+	resp, err := acc.Response()
+	if err != nil {
+		return types.Message{}, err
+	}
+	message := resp.Choices[0].Message
+	if !withToolCalls {
+		message.ToolCalls = nil
+	}
+	message.Reasoning, message.ReasoningContent = nil, nil
+	return message, nil
+}
+
+// mcpTools returns the MCP tools to offer to the model, as the MCP middleware
+// injects them into chat completions; none when no MCP server is available
+func (router *RouterImpl) mcpTools() []types.ChatCompletionTool {
+	if router.mcpClient == nil || router.cfg.MCP == nil || !router.cfg.MCP.Enabled || !router.mcpClient.IsInitialized() {
+		return nil
+	}
+	available := false
+	for _, status := range router.mcpClient.GetAllServerStatuses() {
+		if status == mcp.ServerStatusAvailable {
+			available = true
+			break
+		}
+	}
+	if !available {
+		return nil
+	}
+	if router.cfg.MCP.ToolMode == mcp.ToolModeDirect {
+		return router.mcpClient.GetAllChatCompletionTools()
+	}
+	return router.mcpClient.GetSelectorTools()
+}
+
+// pingWebSocket pings the client every interval, keeping proxies from
+// dropping quiet connections
+func (router *RouterImpl) pingWebSocket(ctx context.Context, ws *wsConn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := ws.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval)); err != nil {
+				if !errors.Is(err, websocket.ErrCloseSent) {
+					router.logger.Debug("failed to ping websocket", "error", err.Error())
+				}
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkWebSocketOrigin accepts upgrade requests without an Origin header
+// (non-browser clients), from the origin of the gateway, or from one of
+// SERVER_WEBSOCKET_ALLOWED_ORIGINS
+func (router *RouterImpl) checkWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for allowed := range strings.SplitSeq(router.cfg.Server.WebsocketAllowedOrigins, ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	router.logger.Warn("rejected websocket origin", "origin", origin)
+	return false
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// runChat implements the chat subcommand: an interactive REPL that streams
+// completions from a running gateway. It returns the process exit code.
+func runChat(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("chat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	model := fs.String("model", "", "Model to chat with, as provider/model (required)")
+	system := fs.String("system", "", "System prompt sent at the start of the conversation")
+	gatewayURL := fs.String("url", gatewayURLDefault(), "Gateway base URL (env "+gatewayURLEnv+")")
+	apiKey := fs.String("api-key", os.Getenv(gatewayAPIKeyEnv), "Bearer token for gateways with authentication enabled (env "+gatewayAPIKeyEnv+")")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage:")
+		fmt.Fprintln(stderr, "  inference-gateway chat --model provider/model [--system PROMPT] [--url URL]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Starts an interactive chat against a running gateway. Type /reset to clear the")
+		fmt.Fprintln(stderr, "conversation and /exit (or Ctrl-D) to quit.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *model == "" {
+		fmt.Fprintln(stderr, "--model is required")
+		fs.Usage()
+		return 2
+	}
+
+	s := &chatSession{
+		client: newGatewayClient(*gatewayURL, *apiKey),
+		model:  *model,
+		system: *system,
+		out:    stdout,
+	}
+	s.reset()
+
+	fmt.Fprintf(stdout, "chatting with %s via %s (/reset to clear, /exit to quit)\n", *model, *gatewayURL)
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stdout, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stdout)
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			continue
+		case "/exit", "/quit":
+			return 0
+		case "/reset":
+			s.reset()
+			fmt.Fprintln(stdout, "conversation cleared")
+			continue
+		}
+
+		// Ctrl-C interrupts the current response rather than the REPL
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := s.send(ctx, line)
+		stop()
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "failed to read input: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// chatSession holds the conversation history of a chat REPL
+type chatSession struct {
+	client   *gatewayClient
+	model    string
+	system   string
+	out      io.Writer
+	messages []types.Message
+}
+
+func (s *chatSession) reset() {
+	s.messages = nil
+	if s.system != "" {
+		s.messages = append(s.messages, textMessage(types.System, s.system))
+	}
+}
+
+// send appends the user input to the conversation, streams the reply and
+// prints tool calls and a usage summary once the stream ends.
+func (s *chatSession) send(ctx context.Context, input string) error {
+	messages := append(s.messages, textMessage(types.User, input))
+
+	var (
+		reply     strings.Builder
+		reasoning bool
+		usage     *types.CompletionUsage
+		toolCalls = make(map[int]*types.ChatCompletionMessageToolCall)
+		order     []int
+	)
+	start := time.Now()
+	err := s.client.StreamChatCompletion(ctx, types.CreateChatCompletionRequest{
+		Model:    s.model,
+		Messages: messages,
+	}, func(chunk types.CreateChatCompletionStreamResponse) {
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			return
+		}
+		delta := chunk.Choices[0].Delta
+
+		thinking := delta.ReasoningContent
+		if thinking == nil {
+			thinking = delta.Reasoning
+		}
+		if thinking != nil && *thinking != "" {
+			if !reasoning {
+				fmt.Fprint(s.out, "[thinking] ")
+				reasoning = true
+			}
+			fmt.Fprint(s.out, *thinking)
+		}
+		if delta.Content != "" {
+			if reasoning {
+				fmt.Fprint(s.out, "\n")
+				reasoning = false
+			}
+			fmt.Fprint(s.out, delta.Content)
+			reply.WriteString(delta.Content)
+		}
+
+		if delta.ToolCalls == nil {
+			return
+		}
+		for _, tc := range *delta.ToolCalls {
+			call, ok := toolCalls[tc.Index]
+			if !ok {
+				call = &types.ChatCompletionMessageToolCall{Type: types.Function}
+				toolCalls[tc.Index] = call
+				order = append(order, tc.Index)
+			}
+			if tc.ID != nil {
+				call.ID = *tc.ID
+			}
+			if tc.Function != nil {
+				if tc.Function.Name != "" {
+					call.Function.Name = tc.Function.Name
+				}
+				call.Function.Arguments += tc.Function.Arguments
+			}
+		}
+	})
+	fmt.Fprintln(s.out)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range order {
+		call := toolCalls[i]
+		fmt.Fprintf(s.out, "[tool call] %s(%s)\n", call.Function.Name, call.Function.Arguments)
+	}
+
+	elapsed := time.Since(start).Round(10 * time.Millisecond)
+	if usage != nil {
+		fmt.Fprintf(s.out, "[usage] prompt=%d completion=%d total=%d tokens in %s\n",
+			usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, elapsed)
+	} else {
+		fmt.Fprintf(s.out, "[usage] not reported, took %s\n", elapsed)
+	}
+
+	// Tool calls are not executed client-side, so only the text of the reply
+	// is kept in the history.
+	s.messages = append(messages, textMessage(types.Assistant, reply.String()))
+	return nil
+}
+
+func textMessage(role types.MessageRole, text string) types.Message {
+	msg := types.Message{Role: role}
+	_ = msg.Content.FromMessageContent0(text)
+	return msg
+}
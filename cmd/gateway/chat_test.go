@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func TestRunChat(t *testing.T) {
+	var requests []types.CreateChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer secret")
+		}
+		var req types.CreateChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []string{
+			`{"id":"1","object":"chat.completion.chunk","created":0,"model":"m","choices":[{"index":0,"finish_reason":"","delta":{"role":"assistant","content":"Hel"}}]}`,
+			`{"id":"1","object":"chat.completion.chunk","created":0,"model":"m","choices":[{"index":0,"finish_reason":"","delta":{"role":"assistant","content":"lo","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_time","arguments":"{\"tz\":"}}]}}]}`,
+			`{"id":"1","object":"chat.completion.chunk","created":0,"model":"m","choices":[{"index":0,"finish_reason":"tool_calls","delta":{"role":"assistant","content":"","tool_calls":[{"index":0,"function":{"name":"","arguments":"\"UTC\"}"}}]}}]}`,
+			`{"id":"1","object":"chat.completion.chunk","created":0,"model":"m","choices":[],"usage":{"prompt_tokens":7,"completion_tokens":2,"total_tokens":9}}`,
+			`[DONE]`,
+		}
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+	}))
+	defer srv.Close()
+
+	stdin := strings.NewReader("hi\n/reset\nagain\n/exit\n")
+	var stdout, stderr bytes.Buffer
+	code := runChat([]string{"--model", "openai/gpt-test", "--system", "be brief", "--url", srv.URL, "--api-key", "secret"}, stdin, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("runChat() = %d, stderr: %s", code, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"Hello\n",
+		`[tool call] get_time({"tz":"UTC"})`,
+		"[usage] prompt=7 completion=2 total=9 tokens",
+		"conversation cleared",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(requests))
+	}
+	if requests[0].Stream == nil || !*requests[0].Stream {
+		t.Error("expected a streaming request")
+	}
+	// After /reset only the system prompt and the new message are sent
+	if got := len(requests[1].Messages); got != 2 {
+		t.Errorf("messages after reset = %d, want 2", got)
+	}
+	if requests[1].Messages[0].Role != types.System {
+		t.Errorf("first message role = %q, want %q", requests[1].Messages[0].Role, types.System)
+	}
+}
+
+func TestRunChatRequiresModel(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runChat(nil, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("runChat() = %d, want 2", code)
+	}
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	envconfig "github.com/sethvargo/go-envconfig"
+	yaml "gopkg.in/yaml.v3"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+	cache "github.com/inference-gateway/inference-gateway/internal/cache"
+	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
+	shaping "github.com/inference-gateway/inference-gateway/internal/shaping"
+	truncation "github.com/inference-gateway/inference-gateway/internal/truncation"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Severity of a configuration issue reported by validateConfig
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// configIssue is a single problem found while validating the configuration
+type configIssue struct {
+	Severity string
+	Setting  string
+	Message  string
+}
+
+func configUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  inference-gateway config validate [--config FILE]")
+	fmt.Fprintln(w, "  inference-gateway config print [--config FILE] [--format yaml|toml] [--show-secrets]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  validate    Load and validate the configuration, exiting non-zero on errors")
+	fmt.Fprintln(w, "  print       Print the effective configuration with secrets masked")
+}
+
+// runConfig implements the config subcommand and returns the process exit code
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		configUsage(stderr)
+		return 2
+	}
+
+	command := args[0]
+	fs := flag.NewFlagSet("config "+command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "Path to a YAML or TOML config file")
+
+	switch command {
+	case "validate":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		cfg, err := loadConfigQuietly(*configPath)
+		if err != nil {
+			fmt.Fprintf(stdout, "%-7s  %s\n", "ERROR", err)
+			fmt.Fprintln(stdout, "configuration is invalid")
+			return 1
+		}
+		configured := configuredProviders(cfg)
+		slices.Sort(configured)
+		fmt.Fprintf(stdout, "configured providers: %s\n", strings.Join(configured, ", "))
+		return printIssues(stdout, validateConfig(context.Background(), cfg))
+	case "print":
+		format := fs.String("format", "yaml", "Output format: yaml or toml")
+		showSecrets := fs.Bool("show-secrets", false, "Print secrets instead of masking them")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		cfg, err := loadConfigQuietly(*configPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to load configuration: %v\n", err)
+			return 1
+		}
+		if err := printConfig(stdout, cfg, *format, !*showSecrets); err != nil {
+			fmt.Fprintf(stderr, "failed to print configuration: %v\n", err)
+			return 1
+		}
+		return 0
+	case "-h", "--help", "help":
+		configUsage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown config command %q\n\n", command)
+		configUsage(stderr)
+		return 2
+	}
+}
+
+// loadConfigQuietly loads the configuration exactly as the server does, but
+// without the per-provider notices Load writes to the standard logger; the
+// validate command reports unconfigured providers itself.
+func loadConfigQuietly(path string) (config.Config, error) {
+	output := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(output)
+
+	var cfg config.Config
+	return cfg.LoadWithFile(path, envconfig.OsLookuper())
+}
+
+func printConfig(w io.Writer, cfg config.Config, format string, maskSecrets bool) error {
+	values := cfg.FileValues(maskSecrets)
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(values); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "toml":
+		return toml.NewEncoder(w).Encode(values)
+	default:
+		return fmt.Errorf("unsupported format %q: expected yaml or toml", format)
+	}
+}
+
+func printIssues(w io.Writer, issues []configIssue) int {
+	errCount := 0
+	for _, issue := range issues {
+		if issue.Severity == severityError {
+			errCount++
+		}
+		fmt.Fprintf(w, "%-7s  %s: %s\n", strings.ToUpper(issue.Severity), issue.Setting, issue.Message)
+	}
+
+	if errCount > 0 {
+		fmt.Fprintf(w, "configuration is invalid: %d error(s)\n", errCount)
+		return 1
+	}
+	fmt.Fprintln(w, "configuration is valid")
+	return 0
+}
+
+// validateConfig performs the checks the server would otherwise only surface
+// at startup or on the first request.
+func validateConfig(ctx context.Context, cfg config.Config) []configIssue {
+	var issues []configIssue
+	add := func(severity, setting, format string, args ...any) {
+		issues = append(issues, configIssue{Severity: severity, Setting: setting, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Providers
+	for id, p := range cfg.Providers {
+		prefix := strings.ToUpper(string(id))
+		if p.AuthType != constants.AuthTypeNone && p.Token == "" {
+			continue
+		}
+		if err := validateURL(p.URL); err != nil {
+			add(severityError, prefix+"_API_URL", "%v", err)
+		} else if strings.ContainsAny(p.URL, "{}") {
+			add(severityError, prefix+"_API_URL", "url %q contains an unresolved placeholder", p.URL)
+		}
+	}
+	if len(configuredProviders(cfg)) == 0 {
+		add(severityWarning, "providers", "no provider has an API key configured")
+	}
+
+	// Telemetry
+	if cfg.Telemetry.Enabled {
+		if err := validatePort(cfg.Telemetry.MetricsPort); err != nil {
+			add(severityError, "TELEMETRY_METRICS_PORT", "%v", err)
+		}
+		if cfg.Telemetry.TracingEnabled {
+			if err := validateURL(cfg.Telemetry.TracingOtlpEndpoint); err != nil {
+				add(severityError, "TELEMETRY_TRACING_OTLP_ENDPOINT", "%v", err)
+			}
+		}
+	} else if cfg.Telemetry.TracingEnabled || cfg.Telemetry.MetricsPushEnabled {
+		add(severityWarning, "TELEMETRY_ENABLED", "tracing and metrics push have no effect while telemetry is disabled")
+	}
+
+	// MCP
+	if cfg.MCP.Enabled {
+		if cfg.MCP.Servers == "" {
+			add(severityWarning, "MCP_SERVERS", "mcp is enabled but no servers are configured")
+		}
+		for _, server := range splitServers(cfg.MCP.Servers) {
+			if err := validateURL(server); err != nil {
+				add(severityError, "MCP_SERVERS", "%v", err)
+			}
+		}
+		if cfg.MCP.ToolMode != mcp.ToolModeSelector && cfg.MCP.ToolMode != mcp.ToolModeDirect {
+			add(severityError, "MCP_TOOL_MODE", "unsupported tool mode %q: expected %s or %s", cfg.MCP.ToolMode, mcp.ToolModeSelector, mcp.ToolModeDirect)
+		}
+	}
+
+	// Authentication
+	if cfg.Auth.Enabled {
+		if err := validateURL(cfg.Auth.OidcIssuer); err != nil {
+			add(severityError, "AUTH_OIDC_ISSUER", "%v", err)
+		}
+		if cfg.Auth.OidcClientId == "" {
+			add(severityError, "AUTH_OIDC_CLIENT_ID", "client id is required when authentication is enabled")
+		}
+	} else if !isLoopbackHost(cfg.Server.Host) {
+		add(severityWarning, "AUTH_ENABLED", "authentication is disabled while the server binds to non-loopback host %q", cfg.Server.Host)
+	}
+
+	// Guardrails
+	if cfg.Guardrails.Enabled {
+		if cfg.Guardrails.FailMode != guardrails.FailModeOpen && cfg.Guardrails.FailMode != guardrails.FailModeClosed {
+			add(severityError, "GUARDRAILS_FAIL_MODE", "unsupported fail mode %q: expected %s or %s", cfg.Guardrails.FailMode, guardrails.FailModeOpen, guardrails.FailModeClosed)
+		}
+		if _, err := guardrails.NewEvaluator(ctx, cfg.Guardrails.PolicyDir); err != nil {
+			add(severityError, "GUARDRAILS_POLICY_DIR", "%v", err)
+		}
+		if cfg.Guardrails.ExternalUrl != "" {
+			if err := validateURL(cfg.Guardrails.ExternalUrl); err != nil {
+				add(severityError, "GUARDRAILS_EXTERNAL_URL", "%v", err)
+			}
+		}
+		if cfg.Guardrails.ModerationEnabled {
+			if p, ok := cfg.Providers[types.Provider(cfg.Guardrails.ModerationProvider)]; !ok {
+				add(severityError, "GUARDRAILS_MODERATION_PROVIDER", "unknown provider %q", cfg.Guardrails.ModerationProvider)
+			} else if p.Endpoints.Moderations == nil {
+				add(severityError, "GUARDRAILS_MODERATION_PROVIDER", "provider %q does not support moderations", cfg.Guardrails.ModerationProvider)
+			}
+			if cfg.Guardrails.ModerationModel == "" {
+				add(severityError, "GUARDRAILS_MODERATION_MODEL", "required when GUARDRAILS_MODERATION_ENABLED is true")
+			}
+			if _, err := guardrails.NewModeration(nil, cfg.Guardrails.ModerationCategories, cfg.Guardrails.ModerationPhases); err != nil {
+				add(severityError, "GUARDRAILS_MODERATION_PHASES", "%v", err)
+			}
+		}
+	}
+
+	// Server
+	if err := validatePort(cfg.Server.Port); err != nil {
+		add(severityError, "SERVER_PORT", "%v", err)
+	}
+	certPath, keyPath := cfg.Server.TlsCertPath, cfg.Server.TlsKeyPath
+	switch {
+	case certPath != "" && keyPath == "":
+		add(severityError, "SERVER_TLS_KEY_PATH", "required when SERVER_TLS_CERT_PATH is set")
+	case certPath == "" && keyPath != "":
+		add(severityError, "SERVER_TLS_CERT_PATH", "required when SERVER_TLS_KEY_PATH is set")
+	}
+	for setting, path := range map[string]string{"SERVER_TLS_CERT_PATH": certPath, "SERVER_TLS_KEY_PATH": keyPath} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			add(severityError, setting, "%v", err)
+		}
+	}
+	if cfg.Server.StreamKeepaliveInterval < 0 {
+		add(severityError, "SERVER_STREAM_KEEPALIVE_INTERVAL", "must not be negative")
+	}
+	if cfg.Server.StreamIdleTimeout < 0 {
+		add(severityError, "SERVER_STREAM_IDLE_TIMEOUT", "must not be negative")
+	}
+
+	// Client
+	if v := cfg.Client.ClientTlsMinVersion; v != "TLS12" && v != "TLS13" {
+		add(severityError, "CLIENT_TLS_MIN_VERSION", "unsupported tls version %q: expected TLS12 or TLS13", v)
+	}
+
+	// Routing
+	if cfg.Routing.Enabled {
+		if cfg.Routing.ConfigPath == "" {
+			add(severityError, "ROUTING_CONFIG_PATH", "required when ROUTING_ENABLED is true")
+		} else if poolsCfg, err := routing.LoadPoolsConfig(cfg.Routing.ConfigPath); err != nil {
+			add(severityError, "ROUTING_CONFIG_PATH", "%v", err)
+		} else if _, err := routing.NewSelector(poolsCfg); err != nil {
+			add(severityError, "ROUTING_CONFIG_PATH", "%v", err)
+		}
+	}
+
+	// Response cache
+	if cfg.Cache.Enabled {
+		switch cfg.Cache.Backend {
+		case cache.BackendMemory:
+		case cache.BackendDisk:
+			if cfg.Cache.Dir == "" {
+				add(severityError, "CACHE_DIR", "required when CACHE_BACKEND is %s", cache.BackendDisk)
+			}
+		default:
+			add(severityError, "CACHE_BACKEND", "unsupported backend %q: expected %s or %s", cfg.Cache.Backend, cache.BackendMemory, cache.BackendDisk)
+		}
+		if cfg.Cache.Ttl <= 0 {
+			add(severityError, "CACHE_TTL", "must be greater than zero")
+		}
+		if cfg.Cache.SemanticEnabled {
+			if _, ok := cfg.Providers[types.Provider(cfg.Cache.SemanticProvider)]; !ok {
+				add(severityError, "CACHE_SEMANTIC_PROVIDER", "unknown provider %q", cfg.Cache.SemanticProvider)
+			}
+			if cfg.Cache.SemanticModel == "" {
+				add(severityError, "CACHE_SEMANTIC_MODEL", "required when CACHE_SEMANTIC_ENABLED is true")
+			}
+			if t := cfg.Cache.SemanticThreshold; t <= 0 || t > 1 {
+				add(severityError, "CACHE_SEMANTIC_THRESHOLD", "must be greater than 0 and at most 1, got %g", t)
+			}
+		}
+	}
+
+	if cfg.Cache.ModelsEnabled && cfg.Cache.ModelsTtl <= 0 {
+		add(severityError, "CACHE_MODELS_TTL", "must be greater than zero")
+	}
+
+	// Conversation truncation
+	if cfg.Truncation.Enabled {
+		strategy, err := truncation.ParseStrategy(cfg.Truncation.Strategy)
+		if err != nil {
+			add(severityError, "TRUNCATION_STRATEGY", "%v", err)
+		}
+		if cfg.Truncation.KeepFirst < 0 {
+			add(severityError, "TRUNCATION_KEEP_FIRST", "must not be negative")
+		}
+		if cfg.Truncation.SummaryModel != "" {
+			if provider, _ := routing.DetermineProviderAndModelName(cfg.Truncation.SummaryModel); provider == nil {
+				add(severityError, "TRUNCATION_SUMMARY_MODEL", "%q is not in provider/model format", cfg.Truncation.SummaryModel)
+			} else if _, ok := cfg.Providers[*provider]; !ok {
+				add(severityError, "TRUNCATION_SUMMARY_MODEL", "unknown provider %q", *provider)
+			}
+			if cfg.Truncation.SummaryMaxTokens <= 0 {
+				add(severityError, "TRUNCATION_SUMMARY_MAX_TOKENS", "must be greater than zero")
+			}
+		} else if strategy == truncation.StrategySummarizeMiddle {
+			add(severityError, "TRUNCATION_SUMMARY_MODEL", "required when TRUNCATION_STRATEGY is %s", truncation.StrategySummarizeMiddle)
+		}
+	}
+
+	// Request shaping
+	if cfg.Shaping.Enabled {
+		if cfg.Shaping.ConfigPath == "" {
+			add(severityError, "SHAPING_CONFIG_PATH", "required when SHAPING_ENABLED is true")
+		} else if shapingCfg, err := shaping.LoadConfig(cfg.Shaping.ConfigPath); err != nil {
+			add(severityError, "SHAPING_CONFIG_PATH", "%v", err)
+		} else if _, err := shaping.NewShaper(shapingCfg); err != nil {
+			add(severityError, "SHAPING_CONFIG_PATH", "%v", err)
+		}
+	}
+
+	sortIssues(issues)
+	return issues
+}
+
+// configuredProviders returns the IDs of providers that can serve requests
+func configuredProviders(cfg config.Config) []string {
+	configured := make([]string, 0)
+	for id, p := range cfg.Providers {
+		if p.AuthType == constants.AuthTypeNone || p.Token != "" {
+			configured = append(configured, string(id))
+		}
+	}
+	return configured
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid url %q: missing host", raw)
+	}
+	return nil
+}
+
+func validatePort(raw string) error {
+	port, err := strconv.Atoi(raw)
+	if err != nil || port < 1 || port > 65535 {
+		return errors.New("port must be a number between 1 and 65535")
+	}
+	return nil
+}
+
+// sortIssues orders errors before warnings and then by setting name so the
+// output is stable across runs.
+func sortIssues(issues []configIssue) {
+	rank := map[string]int{severityError: 0, severityWarning: 1}
+	slices.SortStableFunc(issues, func(a, b configIssue) int {
+		return cmp.Or(cmp.Compare(rank[a.Severity], rank[b.Severity]), cmp.Compare(a.Setting, b.Setting))
+	})
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+)
+
+func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	if err := os.WriteFile(certPath, []byte("cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	routingPath := filepath.Join(dir, "routing.yaml")
+	if err := os.WriteFile(routingPath, []byte("models:\n  fast:\n    deployments:\n      - provider: groq\n        model: llama\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	shapingPath := filepath.Join(dir, "shaping.yaml")
+	if err := os.WriteFile(shapingPath, []byte("rules:\n  - model: openai/*\n    clamp:\n      messages:\n        max: 10\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policyDir := filepath.Join(dir, "policies")
+	if err := os.Mkdir(policyDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(policyDir, "broken.rego"), []byte("package guardrails\nmain := {"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		settings []string
+	}{
+		{
+			name:     "defaults are valid",
+			env:      map[string]string{"OPENAI_API_KEY": "sk-test"},
+			settings: nil,
+		},
+		{
+			name: "invalid provider url",
+			env: map[string]string{
+				"OPENAI_API_KEY": "sk-test",
+				"OPENAI_API_URL": "api.openai.com/v1",
+			},
+			settings: []string{"OPENAI_API_URL"},
+		},
+		{
+			name:     "unresolved provider url placeholder",
+			env:      map[string]string{"CLOUDFLARE_API_KEY": "cf-test"},
+			settings: []string{"CLOUDFLARE_API_URL"},
+		},
+		{
+			name: "tls key missing and cert present",
+			env: map[string]string{
+				"OPENAI_API_KEY":       "sk-test",
+				"SERVER_TLS_CERT_PATH": certPath,
+			},
+			settings: []string{"SERVER_TLS_KEY_PATH"},
+		},
+		{
+			name: "tls files missing",
+			env: map[string]string{
+				"OPENAI_API_KEY":       "sk-test",
+				"SERVER_TLS_CERT_PATH": filepath.Join(dir, "missing.crt"),
+				"SERVER_TLS_KEY_PATH":  filepath.Join(dir, "missing.key"),
+			},
+			settings: []string{"SERVER_TLS_CERT_PATH", "SERVER_TLS_KEY_PATH"},
+		},
+		{
+			name: "negative stream timings",
+			env: map[string]string{
+				"OPENAI_API_KEY":                   "sk-test",
+				"SERVER_STREAM_KEEPALIVE_INTERVAL": "-1s",
+				"SERVER_STREAM_IDLE_TIMEOUT":       "-5m",
+			},
+			settings: []string{"SERVER_STREAM_IDLE_TIMEOUT", "SERVER_STREAM_KEEPALIVE_INTERVAL"},
+		},
+		{
+			name: "routing pool rejected by selector",
+			env: map[string]string{
+				"OPENAI_API_KEY":      "sk-test",
+				"ROUTING_ENABLED":     "true",
+				"ROUTING_CONFIG_PATH": routingPath,
+			},
+			settings: []string{"ROUTING_CONFIG_PATH"},
+		},
+		{
+			name: "guardrail policy fails to compile",
+			env: map[string]string{
+				"OPENAI_API_KEY":        "sk-test",
+				"GUARDRAILS_ENABLED":    "true",
+				"GUARDRAILS_POLICY_DIR": policyDir,
+				"GUARDRAILS_FAIL_MODE":  "sometimes",
+			},
+			settings: []string{"GUARDRAILS_FAIL_MODE", "GUARDRAILS_POLICY_DIR"},
+		},
+		{
+			name: "guardrail moderation provider and phases",
+			env: map[string]string{
+				"OPENAI_API_KEY":                 "sk-test",
+				"GUARDRAILS_ENABLED":             "true",
+				"GUARDRAILS_MODERATION_ENABLED":  "true",
+				"GUARDRAILS_MODERATION_PROVIDER": "anthropic",
+				"GUARDRAILS_MODERATION_PHASES":   "pre_call,tool_args",
+			},
+			settings: []string{"GUARDRAILS_MODERATION_PHASES", "GUARDRAILS_MODERATION_PROVIDER"},
+		},
+		{
+			name: "mcp servers and tool mode",
+			env: map[string]string{
+				"OPENAI_API_KEY": "sk-test",
+				"MCP_ENABLED":    "true",
+				"MCP_SERVERS":    "http://mcp:8081/mcp,mcp:8082",
+				"MCP_TOOL_MODE":  "all",
+			},
+			settings: []string{"MCP_SERVERS", "MCP_TOOL_MODE"},
+		},
+		{
+			name: "semantic cache provider and threshold",
+			env: map[string]string{
+				"OPENAI_API_KEY":           "sk-test",
+				"CACHE_ENABLED":            "true",
+				"CACHE_SEMANTIC_ENABLED":   "true",
+				"CACHE_SEMANTIC_PROVIDER":  "vectors",
+				"CACHE_SEMANTIC_THRESHOLD": "1.5",
+			},
+			settings: []string{"CACHE_SEMANTIC_PROVIDER", "CACHE_SEMANTIC_THRESHOLD"},
+		},
+		{
+			name: "truncation strategy and summary model",
+			env: map[string]string{
+				"OPENAI_API_KEY":        "sk-test",
+				"TRUNCATION_ENABLED":    "true",
+				"TRUNCATION_STRATEGY":   "drop_newest",
+				"TRUNCATION_KEEP_FIRST": "-1",
+			},
+			settings: []string{"TRUNCATION_KEEP_FIRST", "TRUNCATION_STRATEGY"},
+		},
+		{
+			name: "summarize_middle without a summary model",
+			env: map[string]string{
+				"OPENAI_API_KEY":      "sk-test",
+				"TRUNCATION_ENABLED":  "true",
+				"TRUNCATION_STRATEGY": "summarize_middle",
+			},
+			settings: []string{"TRUNCATION_SUMMARY_MODEL"},
+		},
+		{
+			name: "shaping rule rejected by shaper",
+			env: map[string]string{
+				"OPENAI_API_KEY":      "sk-test",
+				"SHAPING_ENABLED":     "true",
+				"SHAPING_CONFIG_PATH": shapingPath,
+			},
+			settings: []string{"SHAPING_CONFIG_PATH"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := validateConfig(context.Background(), loadTestConfig(t, tt.env))
+
+			var got []string
+			for _, issue := range issues {
+				if issue.Severity == severityError {
+					got = append(got, issue.Setting)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.settings, ",") {
+				t.Errorf("error settings = %v, want %v (issues: %+v)", got, tt.settings, issues)
+			}
+		})
+	}
+}
+
+func TestPrintIssuesExitCode(t *testing.T) {
+	var out bytes.Buffer
+	if code := printIssues(&out, []configIssue{{Severity: severityWarning, Setting: "MCP_SERVERS", Message: "empty"}}); code != 0 {
+		t.Errorf("exit code with warnings only = %d, want 0", code)
+	}
+	if code := printIssues(&out, []configIssue{{Severity: severityError, Setting: "SERVER_PORT", Message: "bad"}}); code != 1 {
+		t.Errorf("exit code with errors = %d, want 1", code)
+	}
+}
+
+func TestPrintConfigMasksSecrets(t *testing.T) {
+	cfg := loadTestConfig(t, map[string]string{
+		"OPENAI_API_KEY":          "sk-secret",
+		"AUTH_OIDC_CLIENT_SECRET": "oidc-secret",
+	})
+
+	var out bytes.Buffer
+	if err := printConfig(&out, cfg, "yaml", true); err != nil {
+		t.Fatalf("printConfig() error = %v", err)
+	}
+
+	printed := out.String()
+	for _, secret := range []string{"sk-secret", "oidc-secret"} {
+		if strings.Contains(printed, secret) {
+			t.Errorf("printed config leaks secret %q", secret)
+		}
+	}
+	if !strings.Contains(printed, config.MaskedSecret) {
+		t.Errorf("printed config does not contain the masked secret placeholder")
+	}
+
+	path := filepath.Join(t.TempDir(), "printed.yaml")
+	if err := os.WriteFile(path, out.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.ReadFile(path); err != nil {
+		t.Errorf("printed config cannot be read back: %v", err)
+	}
+}
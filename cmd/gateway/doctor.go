@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	oidcV3 "github.com/coreos/go-oidc/v3/oidc"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
+	l "github.com/inference-gateway/inference-gateway/logger"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Status of a single doctor check
+const (
+	checkPass = "pass"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// doctorCheckTimeout bounds every individual doctor check
+const doctorCheckTimeout = 10 * time.Second
+
+// checkResult is a single row of the doctor report
+type checkResult struct {
+	Check     string `json:"check"`
+	Target    string `json:"target"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// runDoctor implements the doctor subcommand and returns the process exit code
+func runDoctor(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "Path to a YAML or TOML config file")
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	chatProbe := fs.Bool("chat", true, "Send a one-token chat completion to every reachable provider")
+	chatModels := fs.String("chat-models", "", "Comma-separated provider/model list to probe instead of each provider's first listed chat model")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage:")
+		fmt.Fprintln(stderr, "  inference-gateway doctor [--config FILE] [--json] [--chat=false] [--chat-models provider/model,...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Checks provider credentials and connectivity, MCP servers, OIDC discovery and")
+		fmt.Fprintln(stderr, "the OTLP endpoint, exiting non-zero when any check fails.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := loadConfigQuietly(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	d := &doctor{
+		cfg:        cfg,
+		logger:     l.NewNoopLogger(),
+		chatProbe:  *chatProbe,
+		chatModels: parseChatModels(*chatModels),
+	}
+	results := d.run(context.Background())
+
+	if *jsonOutput {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintf(stderr, "failed to encode report: %v\n", err)
+			return 1
+		}
+	} else {
+		printDoctorTable(stdout, results)
+	}
+
+	for _, r := range results {
+		if r.Status == checkFail {
+			return 1
+		}
+	}
+	return 0
+}
+
+// doctor runs the same connectivity checks the server performs in the
+// background at startup, synchronously and with a report of every result.
+type doctor struct {
+	cfg        config.Config
+	logger     l.Logger
+	chatProbe  bool
+	chatModels map[types.Provider]string
+}
+
+func (d *doctor) run(ctx context.Context) []checkResult {
+	providerRegistry := registry.NewProviderRegistry(d.cfg.Providers, d.logger)
+	httpClient := client.NewHTTPClient(d.cfg.Client)
+
+	results := d.checkProviders(ctx, providerRegistry, httpClient)
+	results = append(results, d.checkMCP(ctx)...)
+	results = append(results, d.checkOIDC(ctx))
+	results = append(results, d.checkOTLP(ctx))
+	return results
+}
+
+// checkProviders lists the models of every configured provider and, when
+// enabled, sends a one-token chat completion. Providers are checked
+// concurrently but reported in a stable order.
+func (d *doctor) checkProviders(ctx context.Context, providerRegistry registry.ProviderRegistry, httpClient client.Client) []checkResult {
+	ids := make([]types.Provider, 0, len(d.cfg.Providers))
+	for id := range d.cfg.Providers {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	rows := make([][]checkResult, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Go(func() {
+			rows[i] = d.checkProvider(ctx, providerRegistry, httpClient, id)
+		})
+	}
+	wg.Wait()
+
+	results := make([]checkResult, 0, len(ids)*2)
+	for _, row := range rows {
+		results = append(results, row...)
+	}
+	return results
+}
+
+func (d *doctor) checkProvider(ctx context.Context, providerRegistry registry.ProviderRegistry, httpClient client.Client, id types.Provider) []checkResult {
+	target := string(id)
+	if !providerConfigured(id, d.cfg.Providers[id]) {
+		return []checkResult{{Check: "provider", Target: target, Status: checkSkip, Detail: "not configured"}}
+	}
+
+	provider, err := providerRegistry.BuildProvider(id, httpClient)
+	if err != nil {
+		return []checkResult{{Check: "provider", Target: target, Status: checkFail, Detail: err.Error()}}
+	}
+
+	var models types.ListModelsResponse
+	listResult := timed("provider", target, func() (string, error) {
+		checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		defer cancel()
+		models, err = provider.ListModels(checkCtx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d models", len(models.Data)), nil
+	})
+	results := []checkResult{listResult}
+
+	if !d.chatProbe || listResult.Status != checkPass {
+		return results
+	}
+
+	model, ok := d.chatModels[id]
+	if !ok {
+		listed, found := chatModel(models.Data)
+		if !found {
+			return append(results, checkResult{Check: "chat", Target: target, Status: checkSkip, Detail: "no chat model listed, set --chat-models"})
+		}
+		model = strings.TrimPrefix(listed, target+"/")
+	}
+
+	return append(results, timed("chat", target+"/"+model, func() (string, error) {
+		checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		defer cancel()
+		msg := types.Message{Role: types.User}
+		if err := msg.Content.FromMessageContent0("ping"); err != nil {
+			return "", err
+		}
+		maxTokens := 1
+		resp, err := provider.ChatCompletions(checkCtx, types.CreateChatCompletionRequest{
+			Model:     model,
+			MaxTokens: &maxTokens,
+			Messages:  []types.Message{msg},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Choices) == 0 {
+			return "", errors.New("response contained no choices")
+		}
+		return "completion received", nil
+	}))
+}
+
+// nonChatModelMarkers are substrings of the ids of models listed next to chat
+// models that cannot answer a chat completion
+var nonChatModelMarkers = []string{"embed", "whisper", "tts", "dall-e", "transcribe", "moderation", "rerank", "speech", "audio", "image", "realtime"}
+
+// chatModel picks the model probed with a chat completion: the first listed
+// model known to take and produce text, else the first whose id does not mark
+// it as an embedding, audio, image or moderation model
+func chatModel(models []types.Model) (string, bool) {
+	for _, m := range models {
+		if m.Modalities != nil && slices.Contains(m.Modalities.Input, types.ModalityText) && slices.Contains(m.Modalities.Output, types.ModalityText) && !nonChatModel(m.ID) {
+			return m.ID, true
+		}
+	}
+	for _, m := range models {
+		if m.Modalities == nil && !nonChatModel(m.ID) {
+			return m.ID, true
+		}
+	}
+	return "", false
+}
+
+func nonChatModel(id string) bool {
+	id = strings.ToLower(id)
+	return slices.ContainsFunc(nonChatModelMarkers, func(marker string) bool {
+		return strings.Contains(id, marker)
+	})
+}
+
+// checkMCP initializes every configured MCP server and lists its tools
+func (d *doctor) checkMCP(ctx context.Context) []checkResult {
+	if !d.cfg.MCP.Enabled {
+		return []checkResult{{Check: "mcp", Target: "-", Status: checkSkip, Detail: "mcp disabled"}}
+	}
+	servers := splitServers(d.cfg.MCP.Servers)
+	if len(servers) == 0 {
+		return []checkResult{{Check: "mcp", Target: "-", Status: checkSkip, Detail: "no servers configured"}}
+	}
+
+	// A single attempt without background reconnection: doctor reports the
+	// state right now rather than waiting for a server to come back.
+	mcpCfg := d.cfg
+	mcpSettings := *d.cfg.MCP
+	mcpSettings.MaxRetries = 0
+	mcpSettings.EnableReconnect = false
+	mcpCfg.MCP = &mcpSettings
+
+	results := make([]checkResult, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Go(func() {
+			results[i] = timed("mcp", server, func() (string, error) {
+				checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+				defer cancel()
+				mcpClient := mcp.NewMCPClient([]string{server}, d.logger, mcpCfg)
+				if err := mcpClient.InitializeAll(checkCtx); err != nil {
+					return "", err
+				}
+				tools, err := mcpClient.GetServerTools(server)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d tools", len(tools)), nil
+			})
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// checkOIDC fetches the issuer's discovery document
+func (d *doctor) checkOIDC(ctx context.Context) checkResult {
+	if !d.cfg.Auth.Enabled {
+		return checkResult{Check: "oidc", Target: "-", Status: checkSkip, Detail: "authentication disabled"}
+	}
+	return timed("oidc", d.cfg.Auth.OidcIssuer, func() (string, error) {
+		checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		defer cancel()
+		if _, err := oidcV3.NewProvider(checkCtx, d.cfg.Auth.OidcIssuer); err != nil {
+			return "", err
+		}
+		return "discovery document loaded", nil
+	})
+}
+
+// checkOTLP verifies that the OTLP traces endpoint accepts connections. Any
+// HTTP response counts as reachable; only transport errors fail the check.
+func (d *doctor) checkOTLP(ctx context.Context) checkResult {
+	if !d.cfg.Telemetry.Enabled || !d.cfg.Telemetry.TracingEnabled {
+		return checkResult{Check: "otlp", Target: "-", Status: checkSkip, Detail: "tracing disabled"}
+	}
+	endpoint := otel.TracesEndpointURL(d.cfg.Telemetry.TracingOtlpEndpoint)
+	return timed("otlp", endpoint, func() (string, error) {
+		checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(checkCtx, http.MethodPost, endpoint, strings.NewReader("{}"))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		return fmt.Sprintf("reachable (HTTP %d)", resp.StatusCode), nil
+	})
+}
+
+// timed runs fn and converts its outcome into a check result
+func timed(check, target string, fn func() (string, error)) checkResult {
+	start := time.Now()
+	detail, err := fn()
+	result := checkResult{
+		Check:     check,
+		Target:    target,
+		Status:    checkPass,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Detail:    detail,
+	}
+	if err != nil {
+		result.Status = checkFail
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// providerConfigured reports whether a provider should be checked: it has an
+// API key, or it needs none and its URL was changed from the default.
+func providerConfigured(id types.Provider, p *registry.ProviderConfig) bool {
+	if p.AuthType != constants.AuthTypeNone {
+		return p.Token != ""
+	}
+	defaults, ok := registry.Registry[id]
+	return !ok || p.URL != defaults.URL
+}
+
+func parseChatModels(raw string) map[types.Provider]string {
+	models := make(map[types.Provider]string)
+	for _, entry := range strings.Split(raw, ",") {
+		provider, model, ok := strings.Cut(strings.TrimSpace(entry), "/")
+		if ok && provider != "" && model != "" {
+			models[types.Provider(provider)] = model
+		}
+	}
+	return models
+}
+
+func printDoctorTable(w io.Writer, results []checkResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tTARGET\tSTATUS\tLATENCY\tDETAIL")
+	for _, r := range results {
+		latency := "-"
+		if r.Status != checkSkip {
+			latency = fmt.Sprintf("%dms", r.LatencyMs)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Check, r.Target, strings.ToUpper(r.Status), latency, r.Detail)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	l "github.com/inference-gateway/inference-gateway/logger"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func newFakeOpenAI(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"gpt-test","object":"model","created":0,"owned_by":"openai"}]}`))
+	})
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":0,"model":"gpt-test","choices":[{"index":0,"finish_reason":"length","message":{"role":"assistant","content":"p"}}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func findResult(results []checkResult, check, target string) (checkResult, bool) {
+	for _, r := range results {
+		if r.Check == check && r.Target == target {
+			return r, true
+		}
+	}
+	return checkResult{}, false
+}
+
+func TestDoctorChecksProviders(t *testing.T) {
+	upstream := newFakeOpenAI(t, "valid-key")
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedList   string
+		expectedChat   string
+		expectChatSkip bool
+	}{
+		{
+			name:         "ValidCredentials",
+			token:        "valid-key",
+			expectedList: checkPass,
+			expectedChat: checkPass,
+		},
+		{
+			name:         "InvalidCredentials",
+			token:        "wrong-key",
+			expectedList: checkFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadTestConfig(t, map[string]string{
+				"OPENAI_API_URL": upstream.URL + "/v1",
+				"OPENAI_API_KEY": tt.token,
+			})
+			d := &doctor{cfg: cfg, logger: l.NewNoopLogger(), chatProbe: true, chatModels: map[types.Provider]string{}}
+
+			results := d.run(context.Background())
+
+			list, ok := findResult(results, "provider", "openai")
+			if !ok {
+				t.Fatal("missing provider check for openai")
+			}
+			if list.Status != tt.expectedList {
+				t.Errorf("provider status = %q, want %q (detail: %s)", list.Status, tt.expectedList, list.Detail)
+			}
+
+			chat, ok := findResult(results, "chat", "openai/gpt-test")
+			if tt.expectedChat == "" {
+				if ok {
+					t.Errorf("unexpected chat check after failed listing: %+v", chat)
+				}
+				return
+			}
+			if !ok || chat.Status != tt.expectedChat {
+				t.Errorf("chat result = %+v, want status %q", chat, tt.expectedChat)
+			}
+
+			if groq, _ := findResult(results, "provider", "groq"); groq.Status != checkSkip {
+				t.Errorf("unconfigured provider status = %q, want %q", groq.Status, checkSkip)
+			}
+		})
+	}
+}
+
+func TestDoctorChatProbePicksChatModel(t *testing.T) {
+	tests := []struct {
+		name         string
+		models       string
+		expectedChat string
+		status       string
+	}{
+		{
+			name:         "SkipsNonChatModels",
+			models:       `[{"id":"whisper-1"},{"id":"dall-e-3"},{"id":"text-embedding-3-small"},{"id":"tts-1"},{"id":"gpt-4o-mini"}]`,
+			expectedChat: "openai/gpt-4o-mini",
+			status:       checkPass,
+		},
+		{
+			name:         "NoChatModelListed",
+			models:       `[{"id":"whisper-1"},{"id":"tts-1-hd"},{"id":"gpt-image-1"}]`,
+			expectedChat: "openai",
+			status:       checkSkip,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var probed string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"object":"list","data":` + tt.models + `}`))
+			})
+			mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+				var req types.CreateChatCompletionRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				probed = req.Model
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":0,"model":"gpt-4o-mini","choices":[{"index":0,"finish_reason":"length","message":{"role":"assistant","content":"p"}}]}`))
+			})
+			upstream := httptest.NewServer(mux)
+			defer upstream.Close()
+
+			cfg := loadTestConfig(t, map[string]string{
+				"OPENAI_API_URL": upstream.URL + "/v1",
+				"OPENAI_API_KEY": "valid-key",
+			})
+			d := &doctor{cfg: cfg, logger: l.NewNoopLogger(), chatProbe: true, chatModels: map[types.Provider]string{}}
+
+			results := d.run(context.Background())
+
+			chat, ok := findResult(results, "chat", tt.expectedChat)
+			if !ok || chat.Status != tt.status {
+				t.Fatalf("chat result = %+v (found %v), want status %q", chat, ok, tt.status)
+			}
+			if tt.status == checkPass && probed != "gpt-4o-mini" {
+				t.Errorf("probed model = %q, want gpt-4o-mini", probed)
+			}
+		})
+	}
+}
+
+func TestParseChatModels(t *testing.T) {
+	got := parseChatModels("openai/gpt-4o, groq/llama-3.3-70b-versatile,invalid,")
+	want := map[types.Provider]string{
+		"openai": "gpt-4o",
+		"groq":   "llama-3.3-70b-versatile",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseChatModels() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("parseChatModels()[%s] = %q, want %q", k, got[k], v)
+		}
+	}
+}
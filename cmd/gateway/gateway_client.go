@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Environment variables the client subcommands read their defaults from
+const (
+	gatewayURLEnv    = "INFERENCE_GATEWAY_URL"
+	gatewayAPIKeyEnv = "INFERENCE_GATEWAY_API_KEY"
+)
+
+const defaultGatewayURL = "http://localhost:8080"
+
+// gatewayClient talks to a running gateway over its public HTTP API
+type gatewayClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func newGatewayClient(baseURL, apiKey string) *gatewayClient {
+	return &gatewayClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{},
+	}
+}
+
+// gatewayURLDefault returns the gateway URL from the environment, falling back
+// to the default listen address.
+func gatewayURLDefault() string {
+	if v := os.Getenv(gatewayURLEnv); v != "" {
+		return v
+	}
+	return defaultGatewayURL
+}
+
+// ListModels calls GET /v1/models, optionally scoped to a provider and with
+// the given include keys.
+func (c *gatewayClient) ListModels(ctx context.Context, provider string, include []string) (types.ListModelsResponse, error) {
+	query := url.Values{}
+	if provider != "" {
+		query.Set("provider", provider)
+	}
+	if len(include) > 0 {
+		query.Set("include", strings.Join(include, ","))
+	}
+	endpoint := c.baseURL + "/v1/models"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return types.ListModelsResponse{}, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return types.ListModelsResponse{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return types.ListModelsResponse{}, responseError(resp)
+	}
+
+	var models types.ListModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return types.ListModelsResponse{}, fmt.Errorf("failed to decode models response: %w", err)
+	}
+	return models, nil
+}
+
+// StreamChatCompletion calls POST /v1/chat/completions with streaming enabled
+// and invokes onChunk for every chunk until the stream ends.
+func (c *gatewayClient) StreamChatCompletion(ctx context.Context, request types.CreateChatCompletionRequest, onChunk func(types.CreateChatCompletionStreamResponse)) error {
+	stream := true
+	request.Stream = &stream
+	request.StreamOptions = &types.ChatCompletionStreamOptions{IncludeUsage: true}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			return nil
+		}
+
+		var chunk types.CreateChatCompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			var streamErr types.Error
+			if json.Unmarshal([]byte(data), &streamErr) == nil && streamErr.Error != nil {
+				return fmt.Errorf("stream error: %s", *streamErr.Error)
+			}
+			return fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		onChunk(chunk)
+	}
+	return scanner.Err()
+}
+
+func (c *gatewayClient) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return req, nil
+}
+
+// responseError turns a non-2xx gateway response into an error, preferring
+// the message from the gateway's JSON error body.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var errResp types.Error
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+		return fmt.Errorf("gateway returned %s: %s", resp.Status, *errResp.Error)
+	}
+	return fmt.Errorf("gateway returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
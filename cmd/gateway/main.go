@@ -24,11 +24,14 @@ import (
 	config "github.com/inference-gateway/inference-gateway/config"
 	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
 	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
+	proxy "github.com/inference-gateway/inference-gateway/internal/proxy"
+	shaping "github.com/inference-gateway/inference-gateway/internal/shaping"
 	l "github.com/inference-gateway/inference-gateway/logger"
 	otel "github.com/inference-gateway/inference-gateway/otel"
 	client "github.com/inference-gateway/inference-gateway/providers/client"
 	registry "github.com/inference-gateway/inference-gateway/providers/registry"
 	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
 var (
@@ -44,8 +47,22 @@ func isLoopbackHost(host string) bool {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "config":
+			os.Exit(runConfig(os.Args[2:], os.Stdout, os.Stderr))
+		case "doctor":
+			os.Exit(runDoctor(os.Args[2:], os.Stdout, os.Stderr))
+		case "chat":
+			os.Exit(runChat(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		case "models":
+			os.Exit(runModels(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	versionFlag := flag.Bool("version", false, "Print version information")
 	helpFlag := flag.Bool("help", false, "Print help information")
+	configFlag := flag.String("config", "", "Path to a YAML or TOML config file")
 	flag.Parse()
 
 	if *versionFlag {
@@ -58,13 +75,24 @@ func main() {
 		fmt.Println()
 		fmt.Println("Usage:")
 		fmt.Println("  inference-gateway [flags]")
+		fmt.Println("  inference-gateway <command> [flags]")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  config validate    Validate the configuration and exit non-zero on errors")
+		fmt.Println("  config print       Print the effective configuration with secrets masked")
+		fmt.Println("  doctor             Check provider credentials, MCP servers, OIDC and OTLP connectivity")
+		fmt.Println("  chat               Chat with a model through a running gateway")
+		fmt.Println("  models             List the models of a running gateway")
 		fmt.Println()
 		fmt.Println("Flags:")
+		fmt.Println("  --config     Path to a YAML or TOML config file")
 		fmt.Println("  --version    Print version information")
 		fmt.Println("  --help       Print help information")
 		fmt.Println()
 		fmt.Println("Configuration:")
-		fmt.Println("  The gateway is configured via environment variables.")
+		fmt.Println("  The gateway is configured via environment variables and an optional")
+		fmt.Println("  config file. Environment variables take precedence over the file.")
+		fmt.Println("  Send SIGHUP to reload providers, model allow-lists and MCP servers.")
 		fmt.Println("  See https://github.com/inference-gateway/inference-gateway/blob/main/Configurations.md")
 		fmt.Println()
 		fmt.Println("Examples:")
@@ -74,10 +102,13 @@ func main() {
 		fmt.Println("  # Start with specific provider configured")
 		fmt.Println("  export OPENAI_API_KEY=your-key")
 		fmt.Println("  inference-gateway")
+		fmt.Println()
+		fmt.Println("  # Start with a config file")
+		fmt.Println("  inference-gateway --config gateway.yaml")
 		os.Exit(0)
 	}
 	var config config.Config
-	cfg, err := config.Load(envconfig.OsLookuper())
+	cfg, err := config.LoadWithFile(*configFlag, envconfig.OsLookuper())
 	if err != nil {
 		log.Printf("{\"error\": \"config load error: %v\"}", err)
 		return
@@ -176,12 +207,10 @@ func main() {
 		return
 	}
 
-	scheme := "http"
-	if cfg.Server.TlsCertPath != "" && cfg.Server.TlsKeyPath != "" {
-		scheme = "https"
+	httpClient := client.NewHTTPClient(cfg.Client)
+	if cfg.Environment == "development" {
+		httpClient = proxy.NewDevClient(httpClient, logger, &cfg)
 	}
-
-	httpClient := client.NewHTTPClient(cfg.Client, scheme, cfg.Server.Host, cfg.Server.Port)
 	providerRegistry := registry.NewProviderRegistry(cfg.Providers, logger)
 
 	// Log registered providers
@@ -222,6 +251,7 @@ func main() {
 			logger.Info("mcp is enabled but no servers configured, using no-op middleware")
 			mcpAgent = mcp.NewAgent(logger, mcpClient)
 		}
+		mcpAgent.SetStreamIdleTimeout(cfg.Server.StreamIdleTimeout)
 		mcpMiddleware, err = middlewares.NewMCPMiddleware(providerRegistry, httpClient, mcpClient, mcpAgent, logger, cfg)
 		if err != nil {
 			logger.Error("failed to initialize mcp middleware", err)
@@ -231,6 +261,7 @@ func main() {
 
 	// Initialize guardrails middleware if enabled
 	var guardrailsMiddleware middlewares.GuardrailsMiddleware
+	var guardrailsEvaluator *guardrails.Evaluator
 	if cfg.Guardrails != nil && cfg.Guardrails.Enabled {
 		ctx := context.Background()
 		evaluator, err := guardrails.NewEvaluator(ctx, cfg.Guardrails.PolicyDir)
@@ -244,22 +275,45 @@ func main() {
 			externalClient = guardrails.NewExternalClient(cfg.Guardrails.ExternalUrl, cfg.Guardrails.ExternalTimeout)
 		}
 
+		var moderation *guardrails.Moderation
+		if cfg.Guardrails.ModerationEnabled {
+			classifier := guardrails.NewModerationClassifier(providerRegistry, httpClient, types.Provider(cfg.Guardrails.ModerationProvider), cfg.Guardrails.ModerationModel)
+			moderation, err = guardrails.NewModeration(classifier, cfg.Guardrails.ModerationCategories, cfg.Guardrails.ModerationPhases)
+			if err != nil {
+				logger.Error("failed to initialize guardrails moderation", err)
+				return
+			}
+			logger.Info("guardrails moderation enabled", "provider", cfg.Guardrails.ModerationProvider, "model", cfg.Guardrails.ModerationModel)
+		}
+
 		detectors := guardrails.DefaultDetectors()
 		guardrailsMiddleware = middlewares.NewGuardrailsMiddleware(
 			evaluator,
 			externalClient,
 			detectors,
+			moderation,
 			logger,
 			telemetryImpl,
 			cfg,
 		)
 		logger.Info("guardrails middleware initialized", "policy_dir", cfg.Guardrails.PolicyDir)
+		guardrailsEvaluator = evaluator
 
 		if mcpAgent != nil {
 			mcpAgent.SetGuardrails(evaluator, telemetryImpl, cfg.Guardrails.FailMode)
 		}
 	} else {
-		guardrailsMiddleware = middlewares.NewGuardrailsMiddleware(nil, nil, nil, logger, telemetryImpl, cfg)
+		guardrailsMiddleware = middlewares.NewGuardrailsMiddleware(nil, nil, nil, nil, logger, telemetryImpl, cfg)
+	}
+
+	// Initialize the response cache middleware (no-op unless CACHE_ENABLED)
+	responseCache, err := middlewares.NewResponseCacheMiddleware(cfg, providerRegistry, httpClient, telemetryImpl, logger)
+	if err != nil {
+		logger.Error("failed to initialize response cache", err, "backend", cfg.Cache.Backend)
+		return
+	}
+	if cfg.Cache.Enabled {
+		logger.Info("response cache enabled", "backend", cfg.Cache.Backend, "ttl", cfg.Cache.Ttl.String(), "semantic", cfg.Cache.SemanticEnabled)
 	}
 
 	// Build the model routing selector if enabled (opt-in, default off).
@@ -278,12 +332,46 @@ func main() {
 		logger.Info("model routing enabled", "aliases", selector.Aliases())
 	}
 
+	// Build the request shaper if enabled (opt-in, default off).
+	var shaper *shaping.Shaper
+	if cfg.Shaping != nil && cfg.Shaping.Enabled {
+		shapingCfg, err := shaping.LoadConfig(cfg.Shaping.ConfigPath)
+		if err != nil {
+			logger.Error("failed to load shaping config", err, "path", cfg.Shaping.ConfigPath)
+			return
+		}
+		shaper, err = shaping.NewShaper(shapingCfg)
+		if err != nil {
+			logger.Error("invalid shaping config", err, "path", cfg.Shaping.ConfigPath)
+			return
+		}
+		logger.Info("request shaping enabled", "rules", len(shapingCfg.Rules))
+	}
+
 	// Set GIN mode based on environment
 	if cfg.Environment != "development" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	api := api.NewRouter(cfg, logger, providerRegistry, httpClient, mcpClient, telemetryImpl, selector)
+	api := api.NewRouter(cfg, logger, providerRegistry, httpClient, mcpClient, telemetryImpl, selector, shaper)
+	modelRefreshCtx, stopModelRefresh := context.WithCancel(context.Background())
+	defer stopModelRefresh()
+	api.StartModelRefresh(modelRefreshCtx)
+	api.SetGuardrails(guardrailsMiddleware)
+	if mcpAgent != nil {
+		// WebSocket sessions run concurrently, so each gets an agent of its own
+		api.SetAgentFactory(func() mcp.Agent {
+			agent := mcp.NewAgent(logger, mcpClient)
+			agent.SetStreamIdleTimeout(cfg.Server.StreamIdleTimeout)
+			if guardrailsEvaluator != nil {
+				agent.SetGuardrails(guardrailsEvaluator, telemetryImpl, cfg.Guardrails.FailMode)
+			}
+			return agent
+		})
+	}
+	if cfg.Cache.ModelsEnabled {
+		logger.Info("model catalog cache enabled", "ttl", cfg.Cache.ModelsTtl.String())
+	}
 	r := gin.New()
 	if cfg.Telemetry.Enabled && cfg.Telemetry.TracingEnabled {
 		r.Use(otelgin.Middleware("inference-gateway", otelgin.WithFilter(func(req *http.Request) bool {
@@ -292,6 +380,8 @@ func main() {
 		logger.Info("tracing middleware added to request pipeline")
 	}
 	r.Use(loggerMiddleware.Middleware())
+	// Read and decode the request body once for every middleware below
+	r.Use(middlewares.NewRequestBodyMiddleware(cfg).Middleware())
 	if cfg.Telemetry.Enabled {
 		r.Use(telemetry.Middleware())
 	}
@@ -301,12 +391,20 @@ func main() {
 	r.Use(guardrailsMiddleware.Middleware())
 	logger.Info("guardrails middleware added to request pipeline")
 
+	// Admit chat completions (allow-lists, shaping, truncation, context
+	// window) before MCP or the cache can answer them
+	r.Use(api.ChatCompletionsAdmission())
+
 	// Add MCP middleware if enabled
 	if cfg.MCP.Enabled {
 		r.Use(mcpMiddleware.Middleware())
 		logger.Info("mcp middleware added to request pipeline")
 	}
 
+	// Add the response cache last so cached completions pass through the
+	// guardrail and MCP handling like upstream ones
+	r.Use(responseCache.Middleware())
+
 	r.GET("/health", api.HealthcheckHandler)
 	r.Any("/proxy/:provider/*path", api.ProxyHandler)
 	v1 := r.Group("/v1")
@@ -314,12 +412,22 @@ func main() {
 		v1.GET("/models", api.ListModelsHandler)
 		v1.GET("/mcp/tools", api.ListToolsHandler)
 		v1.POST("/chat/completions", api.ChatCompletionsHandler)
+		v1.POST("/completions", api.CompletionsHandler)
+		v1.POST("/tokenize", api.TokenizeHandler)
 		v1.POST("/messages", api.MessagesHandler)
+		v1.POST("/messages/count_tokens", api.MessagesCountTokensHandler)
 		v1.POST("/responses", api.ResponsesHandler)
 		v1.POST("/images/generations", api.ImagesHandler)
 		v1.POST("/images/edits", api.ImagesEditsHandler)
 		v1.POST("/images/variations", api.ImagesVariationsHandler)
+		v1.POST("/audio/transcriptions", api.AudioTranscriptionsHandler)
+		v1.POST("/audio/translations", api.AudioTranslationsHandler)
+		v1.POST("/audio/speech", api.AudioSpeechHandler)
+		v1.POST("/rerank", api.RerankHandler)
+		v1.POST("/moderations", api.ModerationsHandler)
 		v1.POST("/metrics", api.MetricsIngestionHandler)
+		v1.GET("/ws", api.WebSocketHandler)
+		v1.GET("/realtime", api.RealtimeHandler)
 	}
 	r.NoRoute(api.NotFoundHandler)
 
@@ -381,11 +489,37 @@ func main() {
 		logger.Info("provider validation complete", "total_providers", len(cfg.Providers), "available_providers", availableProviders, "total_models", totalModels)
 	}()
 
+	// Reload providers, model allow-lists and MCP servers on SIGHUP
+	reload := &reloader{
+		configPath: *configFlag,
+		lookuper:   envconfig.OsLookuper(),
+		logger:     logger,
+		registry:   providerRegistry,
+		router:     api,
+		mcpClient:  mcpClient,
+		current:    cfg,
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			logger.Info("received sighup, reloading configuration")
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.MCP.RequestTimeout)
+			if err := reload.Reload(ctx); err != nil {
+				logger.Error("configuration reload failed", err)
+			}
+			cancel()
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
+	signal.Stop(hup)
 	logger.Info("shutting down server...")
 
+	stopModelRefresh()
+
 	if cfg.MCP.Enabled && mcpClient != nil {
 		mcpClient.StopStatusPolling()
 		mcpClient.StopBackgroundReconnection()
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// runModels implements the models subcommand, listing the models of a running
+// gateway. It returns the process exit code.
+func runModels(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("models", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	provider := fs.String("provider", "", "Only list the models of this provider")
+	include := fs.String("include", "", "Comma-separated metadata to include: pricing, context_window, modalities")
+	jsonOutput := fs.Bool("json", false, "Print the gateway response as JSON")
+	gatewayURL := fs.String("url", gatewayURLDefault(), "Gateway base URL (env "+gatewayURLEnv+")")
+	apiKey := fs.String("api-key", os.Getenv(gatewayAPIKeyEnv), "Bearer token for gateways with authentication enabled (env "+gatewayAPIKeyEnv+")")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage:")
+		fmt.Fprintln(stderr, "  inference-gateway models [--provider ID] [--include pricing,context_window,modalities] [--json] [--url URL]")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	includeKeys, err := parseIncludeKeys(*include)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	models, err := newGatewayClient(*gatewayURL, *apiKey).ListModels(context.Background(), *provider, includeKeys)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to list models: %v\n", err)
+		return 1
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(models); err != nil {
+			fmt.Fprintf(stderr, "failed to encode models: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	printModelsTable(stdout, models.Data, includeKeys)
+	return 0
+}
+
+func parseIncludeKeys(raw string) ([]string, error) {
+	var keys []string
+	for key := range strings.SplitSeq(raw, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if !types.ListModelsParamsInclude(key).Valid() {
+			return nil, fmt.Errorf("unknown include value %q: expected pricing, context_window or modalities", key)
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func printModelsTable(w io.Writer, models []types.Model, includeKeys []string) {
+	withContext := slices.Contains(includeKeys, string(types.ListModelsParamsIncludeContextWindow))
+	withPricing := slices.Contains(includeKeys, string(types.ListModelsParamsIncludePricing))
+	withModalities := slices.Contains(includeKeys, string(types.ListModelsParamsIncludeModalities))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"ID", "SERVED BY"}
+	if withContext {
+		header = append(header, "CONTEXT WINDOW")
+	}
+	if withPricing {
+		header = append(header, "INPUT / 1M", "OUTPUT / 1M")
+	}
+	if withModalities {
+		header = append(header, "INPUT MODALITIES", "OUTPUT MODALITIES")
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, m := range models {
+		row := []string{m.ID, string(m.ServedBy)}
+		if withContext {
+			row = append(row, formatContextWindow(m.ContextWindow))
+		}
+		if withPricing {
+			row = append(row, formatPrice(m.Pricing, func(p *types.Pricing) string { return p.InputPerToken }),
+				formatPrice(m.Pricing, func(p *types.Pricing) string { return p.OutputPerToken }))
+		}
+		if withModalities {
+			input, output := "-", "-"
+			if m.Modalities != nil {
+				input, output = joinModalities(m.Modalities.Input), joinModalities(m.Modalities.Output)
+			}
+			row = append(row, input, output)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+func formatContextWindow(cw *types.ContextWindow) string {
+	if cw == nil || cw.Tokens == 0 {
+		return "-"
+	}
+	return strconv.Itoa(cw.Tokens)
+}
+
+// formatPrice renders a per-token price as a price per million tokens, which
+// is how providers usually publish them.
+func formatPrice(p *types.Pricing, field func(*types.Pricing) string) string {
+	if p == nil {
+		return "-"
+	}
+	if p.Subscription != nil && *p.Subscription {
+		return "subscription"
+	}
+	raw := field(p)
+	perToken, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw
+	}
+	return strings.TrimSpace(strconv.FormatFloat(perToken*1e6, 'f', -1, 64) + " " + p.Currency)
+}
+
+func joinModalities(modalities []types.Modality) string {
+	if len(modalities) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(modalities))
+	for i, m := range modalities {
+		parts[i] = string(m)
+	}
+	return strings.Join(parts, ",")
+}
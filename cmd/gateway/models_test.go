@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("include"); got != "pricing,context_window" {
+			t.Errorf("include = %q, want %q", got, "pricing,context_window")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"openai/gpt-test","object":"model","created":0,"owned_by":"openai","served_by":"openai","context_window":{"tokens":128000,"source":"provider"},"pricing":{"input_per_token":"0.0000025","output_per_token":"0.00001","currency":"USD","source":"provider"}}]}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		expectedOut  []string
+	}{
+		{
+			name:         "Table",
+			args:         []string{"--url", srv.URL, "--include", "pricing,context_window"},
+			expectedOut:  []string{"CONTEXT WINDOW", "INPUT / 1M", "openai/gpt-test", "128000", "2.5 USD", "10 USD"},
+			expectedCode: 0,
+		},
+		{
+			name:         "JSON",
+			args:         []string{"--url", srv.URL, "--include", "pricing, context_window", "--json"},
+			expectedOut:  []string{`"id": "openai/gpt-test"`, `"tokens": 128000`},
+			expectedCode: 0,
+		},
+		{
+			name:         "UnknownInclude",
+			args:         []string{"--url", srv.URL, "--include", "latency"},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runModels(tt.args, &stdout, &stderr); code != tt.expectedCode {
+				t.Fatalf("runModels() = %d, want %d (stderr: %s)", code, tt.expectedCode, stderr.String())
+			}
+			for _, want := range tt.expectedOut {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	envconfig "github.com/sethvargo/go-envconfig"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
+	l "github.com/inference-gateway/inference-gateway/logger"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+)
+
+// reloader re-reads the configuration and swaps the reloadable parts of it
+// (provider configs, model allow-lists and MCP servers) into the running
+// gateway. New requests observe the new state while in-flight requests keep
+// the providers they were already built with.
+type reloader struct {
+	configPath string
+	lookuper   envconfig.Lookuper
+	logger     l.Logger
+	registry   registry.ProviderRegistry
+	router     api.Router
+	mcpClient  mcp.MCPClientInterface
+
+	mu      sync.Mutex
+	current config.Config
+}
+
+// Reload loads the configuration again and applies it. A configuration that
+// fails to load leaves the running state untouched.
+func (r *reloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next config.Config
+	cfg, err := next.LoadWithFile(r.configPath, r.lookuper)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	r.registry.Reload(cfg.Providers)
+	r.router.Reload(cfg)
+
+	if cfg.MCP.Servers != r.current.MCP.Servers {
+		switch {
+		case !r.current.MCP.Enabled || r.mcpClient == nil:
+			r.logger.Warn("mcp servers changed but mcp was not started with servers configured; restart the gateway to apply",
+				"servers", cfg.MCP.Servers)
+		default:
+			if err := r.mcpClient.UpdateServers(ctx, splitServers(cfg.MCP.Servers)); err != nil {
+				r.logger.Error("failed to update mcp servers", err, "servers", cfg.MCP.Servers)
+			}
+		}
+	}
+
+	if sections := restartRequired(r.current, cfg); len(sections) > 0 {
+		r.logger.Warn("configuration changes require a restart to take effect", "sections", strings.Join(sections, ", "))
+	}
+
+	r.current = cfg
+	r.logger.Info("configuration reloaded", "providers", len(cfg.Providers))
+	return nil
+}
+
+// restartRequired returns the config sections that changed between old and
+// next in ways that Reload cannot apply to a running gateway.
+func restartRequired(old, next config.Config) []string {
+	general := func(cfg config.Config) config.Config {
+		return config.Config{
+			Environment:               cfg.Environment,
+			EnableVision:              cfg.EnableVision,
+			EnableImages:              cfg.EnableImages,
+			EnableAudio:               cfg.EnableAudio,
+			EnforceContextWindow:      cfg.EnforceContextWindow,
+			DebugContentTruncateWords: cfg.DebugContentTruncateWords,
+			DebugMaxMessages:          cfg.DebugMaxMessages,
+		}
+	}
+	mcpSettings := func(cfg config.Config) config.MCPConfig {
+		m := *cfg.MCP
+		m.Servers = ""
+		return m
+	}
+
+	sections := []struct {
+		name      string
+		old, next any
+	}{
+		{"general", general(old), general(next)},
+		{"telemetry", old.Telemetry, next.Telemetry},
+		{"mcp", mcpSettings(old), mcpSettings(next)},
+		{"auth", old.Auth, next.Auth},
+		{"guardrails", old.Guardrails, next.Guardrails},
+		{"server", old.Server, next.Server},
+		{"client", old.Client, next.Client},
+		{"routing", old.Routing, next.Routing},
+		{"cache", old.Cache, next.Cache},
+		{"truncation", old.Truncation, next.Truncation},
+		{"shaping", old.Shaping, next.Shaping},
+	}
+
+	changed := make([]string, 0)
+	for _, section := range sections {
+		if !reflect.DeepEqual(section.old, section.next) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
+
+func splitServers(servers string) []string {
+	if servers == "" {
+		return nil
+	}
+	return strings.Split(servers, ",")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	envconfig "github.com/sethvargo/go-envconfig"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	l "github.com/inference-gateway/inference-gateway/logger"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+)
+
+func loadTestConfig(t *testing.T, env map[string]string) config.Config {
+	t.Helper()
+	var cfg config.Config
+	loaded, err := cfg.Load(envconfig.MapLookuper(env))
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	return loaded
+}
+
+func TestReloaderSwapsProviders(t *testing.T) {
+	logger := l.NewNoopLogger()
+	cfg := loadTestConfig(t, map[string]string{})
+	providerRegistry := registry.NewProviderRegistry(cfg.Providers, logger)
+
+	env := map[string]string{"GROQ_API_KEY": "groq-reloaded"}
+	r := &reloader{
+		lookuper: envconfig.MapLookuper(env),
+		logger:   logger,
+		registry: providerRegistry,
+		router:   api.NewRouter(cfg, logger, providerRegistry, nil, nil, nil, nil, nil),
+		current:  cfg,
+	}
+
+	if _, err := providerRegistry.BuildProvider(constants.GroqID, nil); err == nil {
+		t.Fatal("expected groq to be unconfigured before reload")
+	}
+
+	if err := r.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	provider, err := providerRegistry.BuildProvider(constants.GroqID, nil)
+	if err != nil {
+		t.Fatalf("BuildProvider() after reload error = %v", err)
+	}
+	if got := provider.GetToken(); got != "groq-reloaded" {
+		t.Errorf("token after reload = %q, want %q", got, "groq-reloaded")
+	}
+}
+
+func TestReloaderKeepsStateOnInvalidConfig(t *testing.T) {
+	logger := l.NewNoopLogger()
+	cfg := loadTestConfig(t, map[string]string{"GROQ_API_KEY": "groq-original"})
+	providerRegistry := registry.NewProviderRegistry(cfg.Providers, logger)
+
+	r := &reloader{
+		lookuper: envconfig.MapLookuper(map[string]string{"SERVER_READ_TIMEOUT": "invalid"}),
+		logger:   logger,
+		registry: providerRegistry,
+		router:   api.NewRouter(cfg, logger, providerRegistry, nil, nil, nil, nil, nil),
+		current:  cfg,
+	}
+
+	if err := r.Reload(context.Background()); err == nil {
+		t.Fatal("expected Reload() to fail on invalid config")
+	}
+	if got := providerRegistry.GetProviders()[constants.GroqID].Token; got != "groq-original" {
+		t.Errorf("token after failed reload = %q, want %q", got, "groq-original")
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	base := map[string]string{}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{name: "no changes", env: base, want: []string{}},
+		{
+			name: "reloadable settings only",
+			env: map[string]string{
+				"ALLOWED_MODELS": "openai/gpt-4o",
+				"MCP_SERVERS":    "http://mcp:8081/mcp",
+				"OPENAI_API_KEY": "sk-test",
+			},
+			want: []string{},
+		},
+		{
+			name: "restart required",
+			env: map[string]string{
+				"ENABLE_VISION":  "true",
+				"SERVER_PORT":    "9090",
+				"MCP_TOOL_MODE":  "direct",
+				"CLIENT_TIMEOUT": "5s",
+			},
+			want: []string{"general", "mcp", "server", "client"},
+		},
+	}
+
+	old := loadTestConfig(t, base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := restartRequired(old, loadTestConfig(t, tt.env))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("restartRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
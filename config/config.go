@@ -17,6 +17,8 @@ type Config struct {
 	DisallowedModels          string `env:"DISALLOWED_MODELS" description:"Comma-separated list of models to disallow. If empty, no models will be blocked. Takes lower precedence than ALLOWED_MODELS"`
 	EnableVision              bool   `env:"ENABLE_VISION, default=false" description:"Enable vision/multimodal support for all providers. When disabled, image inputs will be rejected even if the provider and model support vision"`
 	EnableImages              bool   `env:"ENABLE_IMAGES, default=false" description:"Enable the Images API (POST /v1/images/generations, /v1/images/edits, /v1/images/variations). When disabled, the endpoints return a 404. Only providers with images support (currently openai) can serve these endpoints"`
+	EnableAudio               bool   `env:"ENABLE_AUDIO, default=false" description:"Enable the Audio API (POST /v1/audio/transcriptions, /v1/audio/translations, /v1/audio/speech). When disabled, the endpoints return a 404. Only providers with audio support (currently openai, groq and mistral) can serve these endpoints"`
+	EnforceContextWindow      bool   `env:"ENFORCE_CONTEXT_WINDOW, default=false" description:"Count the prompt tokens of chat completions before sending them upstream and reject requests that exceed the model context window with a 400. OpenAI models are counted exactly, other models are estimated from the text length"`
 	DebugContentTruncateWords int    `env:"DEBUG_CONTENT_TRUNCATE_WORDS, default=10" description:"Number of words to truncate per content section in debug logs (development mode only)"`
 	DebugMaxMessages          int    `env:"DEBUG_MAX_MESSAGES, default=100" description:"Maximum number of messages to show in debug logs (development mode only)"`
 	// Telemetry settings
@@ -33,6 +35,12 @@ type Config struct {
 	Client *client.ClientConfig `description:"Client configuration"`
 	// Routing settings
 	Routing *RoutingConfig `env:", prefix=ROUTING_" description:"Routing configuration"`
+	// Cache settings
+	Cache *CacheConfig `env:", prefix=CACHE_" description:"Cache configuration"`
+	// Truncation settings
+	Truncation *TruncationConfig `env:", prefix=TRUNCATION_" description:"Truncation configuration"`
+	// Shaping settings
+	Shaping *ShapingConfig `env:", prefix=SHAPING_" description:"Shaping configuration"`
 
 	// Providers map
 	Providers map[types.Provider]*registry.ProviderConfig
@@ -82,23 +90,31 @@ type AuthConfig struct {
 
 // Guardrails configuration
 type GuardrailsConfig struct {
-	Enabled         bool          `env:"ENABLED, default=false" description:"Enable gateway guardrails (OPA/Rego policy enforcement)"`
-	PolicyDir       string        `env:"POLICY_DIR" description:"Directory of .rego files compiled at startup"`
-	FailMode        string        `env:"FAIL_MODE, default=closed" description:"closed or open: behavior on policy/external error or timeout"`
-	ExternalUrl     string        `env:"EXTERNAL_URL" description:"Optional external HTTP guardrail service"`
-	ExternalTimeout time.Duration `env:"EXTERNAL_TIMEOUT, default=5s" description:"Timeout for the external guardrail service"`
+	Enabled              bool          `env:"ENABLED, default=false" description:"Enable gateway guardrails (OPA/Rego policy enforcement)"`
+	PolicyDir            string        `env:"POLICY_DIR" description:"Directory of .rego files compiled at startup"`
+	FailMode             string        `env:"FAIL_MODE, default=closed" description:"closed or open: behavior on policy/external error or timeout"`
+	ExternalUrl          string        `env:"EXTERNAL_URL" description:"Optional external HTTP guardrail service"`
+	ExternalTimeout      time.Duration `env:"EXTERNAL_TIMEOUT, default=5s" description:"Timeout for the external guardrail service"`
+	ModerationEnabled    bool          `env:"MODERATION_ENABLED, default=false" description:"Also classify requests and responses with a moderation model, blocking content flagged in a blocked category"`
+	ModerationProvider   string        `env:"MODERATION_PROVIDER, default=openai" description:"Provider serving the moderation model. It must support the Moderations API (currently openai and mistral)"`
+	ModerationModel      string        `env:"MODERATION_MODEL, default=omni-moderation-latest" description:"Moderation model classifying the content"`
+	ModerationCategories string        `env:"MODERATION_CATEGORIES" description:"Comma-separated moderation categories to block (e.g. hate,violence,self-harm/intent). When empty, anything the model flags is blocked"`
+	ModerationPhases     string        `env:"MODERATION_PHASES, default=pre_call,post_call" description:"Comma-separated phases the moderation model runs in: pre_call classifies requests, post_call non-streaming responses"`
 }
 
 // Server configuration
 type ServerConfig struct {
-	Host               string        `env:"HOST, default=127.0.0.1" description:"Server host"`
-	Port               string        `env:"PORT, default=8080" description:"Server port"`
-	ReadTimeout        time.Duration `env:"READ_TIMEOUT, default=30s" description:"Read timeout"`
-	WriteTimeout       time.Duration `env:"WRITE_TIMEOUT, default=30s" description:"Write timeout"`
-	IdleTimeout        time.Duration `env:"IDLE_TIMEOUT, default=120s" description:"Idle timeout"`
-	MaxRequestBodySize int           `env:"MAX_REQUEST_BODY_SIZE, default=10485760" description:"Maximum request body size in bytes (10 MiB)"`
-	TlsCertPath        string        `env:"TLS_CERT_PATH" description:"TLS certificate path"`
-	TlsKeyPath         string        `env:"TLS_KEY_PATH" description:"TLS key path"`
+	Host                    string        `env:"HOST, default=127.0.0.1" description:"Server host"`
+	Port                    string        `env:"PORT, default=8080" description:"Server port"`
+	ReadTimeout             time.Duration `env:"READ_TIMEOUT, default=30s" description:"Read timeout"`
+	WriteTimeout            time.Duration `env:"WRITE_TIMEOUT, default=30s" description:"Write timeout"`
+	IdleTimeout             time.Duration `env:"IDLE_TIMEOUT, default=120s" description:"Idle timeout"`
+	MaxRequestBodySize      int           `env:"MAX_REQUEST_BODY_SIZE, default=10485760" description:"Maximum request body size in bytes (10 MiB)"`
+	StreamKeepaliveInterval time.Duration `env:"STREAM_KEEPALIVE_INTERVAL, default=15s" description:"Interval of the keep-alive comments sent on quiet streaming responses (0 disables)"`
+	StreamIdleTimeout       time.Duration `env:"STREAM_IDLE_TIMEOUT, default=300s" description:"Abort a streaming response when the upstream sends nothing for this long (0 disables)"`
+	WebsocketAllowedOrigins string        `env:"WEBSOCKET_ALLOWED_ORIGINS" description:"Comma-separated list of browser origins allowed to open /v1/ws connections (* allows any). When empty, only same-origin browsers and non-browser clients can connect"`
+	TlsCertPath             string        `env:"TLS_CERT_PATH" description:"TLS certificate path"`
+	TlsKeyPath              string        `env:"TLS_KEY_PATH" description:"TLS key path"`
 }
 
 // Routing configuration
@@ -106,3 +122,36 @@ type RoutingConfig struct {
 	Enabled    bool   `env:"ENABLED, default=false" description:"Enable gateway-native model routing: logical model aliases backed by a pool of upstream provider deployments, selected round-robin per replica. Opt-in; when disabled, direct provider/model routing is unchanged"`
 	ConfigPath string `env:"CONFIG_PATH" description:"Path to a YAML file mapping logical model aliases to their upstream deployment pools. Required when ROUTING_ENABLED is true"`
 }
+
+// Cache configuration
+type CacheConfig struct {
+	Enabled                   bool          `env:"ENABLED, default=false" description:"Enable the exact-match response cache for chat completions. Clients can skip the lookup with Cache-Control: no-cache and skip storing with Cache-Control: no-store"`
+	Backend                   string        `env:"BACKEND, default=memory" description:"Cache backend: memory (in-process LRU) or disk"`
+	Ttl                       time.Duration `env:"TTL, default=1h" description:"How long a cached response is served before the request goes upstream again"`
+	MaxEntries                int           `env:"MAX_ENTRIES, default=1000" description:"Maximum number of responses kept by the memory backend; the least recently used entry is evicted first"`
+	Dir                       string        `env:"DIR" description:"Directory holding cached responses for the disk backend. Required when CACHE_BACKEND is disk"`
+	DeterministicOnly         bool          `env:"DETERMINISTIC_ONLY, default=true" description:"Only serve exact cache matches for requests sent with temperature 0. Disable to cache every chat completion. The semantic cache has its own switch, CACHE_SEMANTIC_DETERMINISTIC_ONLY"`
+	SemanticEnabled           bool          `env:"SEMANTIC_ENABLED, default=false" description:"Also serve near-duplicate requests: the last user message is embedded and matched against earlier requests with the same model, caller and conversation context"`
+	SemanticProvider          string        `env:"SEMANTIC_PROVIDER, default=ollama" description:"Provider computing the embeddings for the semantic cache. It must expose an OpenAI-compatible /embeddings endpoint"`
+	SemanticModel             string        `env:"SEMANTIC_MODEL, default=nomic-embed-text" description:"Embedding model used by the semantic cache"`
+	SemanticThreshold         float64       `env:"SEMANTIC_THRESHOLD, default=0.95" description:"Minimum cosine similarity between two user messages for a semantic cache hit"`
+	SemanticDeterministicOnly bool          `env:"SEMANTIC_DETERMINISTIC_ONLY, default=false" description:"Only serve and store semantic cache entries for requests sent with temperature 0. Independent of CACHE_DETERMINISTIC_ONLY, which only applies to exact matches"`
+	ModelsEnabled             bool          `env:"MODELS_ENABLED, default=false" description:"Cache the model list of each provider for GET /v1/models and refresh it in the background. Stale lists are served while they are refreshed, so a slow provider does not delay the listing"`
+	ModelsTtl                 time.Duration `env:"MODELS_TTL, default=5m" description:"How often the model catalog is refreshed; lists older than this are marked stale"`
+}
+
+// Truncation configuration
+type TruncationConfig struct {
+	Enabled          bool   `env:"ENABLED, default=false" description:"Trim the messages of chat completions that exceed the model context window instead of sending them upstream as they are. Applies to the models in TRUNCATION_MODELS and to requests sending the X-Truncation-Strategy header"`
+	Models           string `env:"MODELS" description:"Comma-separated list of models truncated with TRUNCATION_STRATEGY. If empty, only requests sending the X-Truncation-Strategy header are truncated"`
+	Strategy         string `env:"STRATEGY, default=drop_oldest" description:"Truncation strategy: drop_oldest (drop the oldest messages, keeping system messages), keep_first_last (also keep the first TRUNCATION_KEEP_FIRST messages) or summarize_middle (replace the dropped messages with a summary written by TRUNCATION_SUMMARY_MODEL)"`
+	KeepFirst        int    `env:"KEEP_FIRST, default=1" description:"Number of leading non-system messages kept by the keep_first_last and summarize_middle strategies"`
+	SummaryModel     string `env:"SUMMARY_MODEL" description:"Model in provider/model format writing the summaries of the summarize_middle strategy; a small, fast model is recommended. Required for summarize_middle"`
+	SummaryMaxTokens int    `env:"SUMMARY_MAX_TOKENS, default=512" description:"Maximum length of a summary in tokens; the budget is reserved in the context window before messages are dropped"`
+}
+
+// Shaping configuration
+type ShapingConfig struct {
+	Enabled    bool   `env:"ENABLED, default=false" description:"Apply the per-model parameter rules of SHAPING_CONFIG_PATH to chat completions before they are sent upstream: defaults, forced values, clamped ranges and stripped fields"`
+	ConfigPath string `env:"CONFIG_PATH" description:"Path to a YAML file listing the request shaping rules, each matching models by pattern. Required when SHAPING_ENABLED is true"`
+}
@@ -58,11 +58,14 @@ func defaultConfig(mutate func(*config.Config)) config.Config {
 			DisableHealthcheckLogs: true,
 		},
 		Guardrails: &config.GuardrailsConfig{
-			Enabled:         false,
-			PolicyDir:       "",
-			FailMode:        "closed",
-			ExternalUrl:     "",
-			ExternalTimeout: 5 * time.Second,
+			Enabled:            false,
+			PolicyDir:          "",
+			FailMode:           "closed",
+			ExternalUrl:        "",
+			ExternalTimeout:    5 * time.Second,
+			ModerationProvider: "openai",
+			ModerationModel:    "omni-moderation-latest",
+			ModerationPhases:   "pre_call,post_call",
 		},
 		Auth: &config.AuthConfig{
 			Enabled:          false,
@@ -71,17 +74,45 @@ func defaultConfig(mutate func(*config.Config)) config.Config {
 			OidcClientSecret: "",
 		},
 		Server: &config.ServerConfig{
-			Host:               "127.0.0.1",
-			Port:               "8080",
-			ReadTimeout:        30 * time.Second,
-			WriteTimeout:       30 * time.Second,
-			IdleTimeout:        120 * time.Second,
-			MaxRequestBodySize: 10485760,
+			Host:                    "127.0.0.1",
+			Port:                    "8080",
+			ReadTimeout:             30 * time.Second,
+			WriteTimeout:            30 * time.Second,
+			IdleTimeout:             120 * time.Second,
+			MaxRequestBodySize:      10485760,
+			StreamKeepaliveInterval: 15 * time.Second,
+			StreamIdleTimeout:       300 * time.Second,
 		},
 		Routing: &config.RoutingConfig{
 			Enabled:    false,
 			ConfigPath: "",
 		},
+		Cache: &config.CacheConfig{
+			Enabled:           false,
+			Backend:           "memory",
+			Ttl:               1 * time.Hour,
+			MaxEntries:        1000,
+			Dir:               "",
+			DeterministicOnly: true,
+			SemanticEnabled:   false,
+			SemanticProvider:  "ollama",
+			SemanticModel:     "nomic-embed-text",
+			SemanticThreshold: 0.95,
+			ModelsEnabled:     false,
+			ModelsTtl:         5 * time.Minute,
+		},
+		Truncation: &config.TruncationConfig{
+			Enabled:          false,
+			Models:           "",
+			Strategy:         "drop_oldest",
+			KeepFirst:        1,
+			SummaryModel:     "",
+			SummaryMaxTokens: 512,
+		},
+		Shaping: &config.ShapingConfig{
+			Enabled:    false,
+			ConfigPath: "",
+		},
 		Client: &client.ClientConfig{
 			ClientTimeout:               30 * time.Second,
 			ClientMaxIdleConns:          20,
@@ -0,0 +1,375 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml/v2"
+	envconfig "github.com/sethvargo/go-envconfig"
+	yaml "gopkg.in/yaml.v3"
+
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// providersSection is the config file section holding per-provider settings.
+// Unlike the other sections its keys are provider IDs rather than settings,
+// e.g. providers.openai.api_key resolves to OPENAI_API_KEY.
+const providersSection = "providers"
+
+// FileLookuper resolves configuration values from a YAML or TOML config file.
+//
+// File keys mirror the environment variables documented in Configurations.md:
+// a top-level key maps to a general setting (allowed_models -> ALLOWED_MODELS)
+// and a nested key maps to its section prefix (mcp.servers -> MCP_SERVERS).
+// Lists are joined with commas, so they can be written as native arrays.
+type FileLookuper struct {
+	values       map[string]string
+	extraHeaders map[types.Provider]map[string][]string
+}
+
+var _ envconfig.Lookuper = (*FileLookuper)(nil)
+
+// Lookup implements envconfig.Lookuper
+func (f *FileLookuper) Lookup(key string) (string, bool) {
+	v, ok := f.values[key]
+	return v, ok
+}
+
+// ReadFile parses a YAML (.yaml, .yml) or TOML (.toml) config file
+func ReadFile(path string) (*FileLookuper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	doc := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q: expected .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return parseFile(doc)
+}
+
+// LoadWithFile loads configuration from the config file at path, with values
+// resolved by lookuper taking precedence over the file. An empty path is
+// equivalent to Load.
+func (cfg *Config) LoadWithFile(path string, lookuper envconfig.Lookuper) (Config, error) {
+	if path == "" {
+		return cfg.Load(lookuper)
+	}
+
+	file, err := ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	loaded, err := cfg.Load(envconfig.MultiLookuper(lookuper, file))
+	if err != nil {
+		return Config{}, err
+	}
+
+	for id, headers := range file.extraHeaders {
+		providerCfg, ok := loaded.Providers[id]
+		if !ok {
+			continue
+		}
+		// Like every other file value, the file headers sit under the
+		// headers the provider already sends
+		merged := maps.Clone(headers)
+		maps.Copy(merged, providerCfg.ExtraHeaders)
+		providerCfg.ExtraHeaders = merged
+	}
+
+	return loaded, nil
+}
+
+func parseFile(doc map[string]any) (*FileLookuper, error) {
+	known := knownEnvKeys()
+	f := &FileLookuper{
+		values:       make(map[string]string),
+		extraHeaders: make(map[types.Provider]map[string][]string),
+	}
+
+	for _, key := range sortedKeys(doc) {
+		value := doc[key]
+		if key == providersSection {
+			if err := f.parseProviders(value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		section, ok := value.(map[string]any)
+		if !ok {
+			if err := f.set(known, key, strings.ToUpper(key), value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, name := range sortedKeys(section) {
+			env := strings.ToUpper(key + "_" + name)
+			if err := f.set(known, key+"."+name, env, section[name]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return f, nil
+}
+
+func (f *FileLookuper) parseProviders(value any) error {
+	providers, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("config file key %q must be a table of provider IDs", providersSection)
+	}
+
+	for _, id := range sortedKeys(providers) {
+		provider := types.Provider(id)
+		if _, ok := registry.Registry[provider]; !ok {
+			return fmt.Errorf("config file key %q: unknown provider %q", providersSection+"."+id, id)
+		}
+
+		settings, ok := providers[id].(map[string]any)
+		if !ok {
+			return fmt.Errorf("config file key %q must be a table", providersSection+"."+id)
+		}
+
+		for _, name := range sortedKeys(settings) {
+			key := providersSection + "." + id + "." + name
+			switch name {
+			case "api_url", "api_key":
+				s, err := stringify(key, settings[name])
+				if err != nil {
+					return err
+				}
+				f.values[strings.ToUpper(id+"_"+name)] = s
+			case "extra_headers":
+				headers, err := parseHeaders(key, settings[name])
+				if err != nil {
+					return err
+				}
+				f.extraHeaders[provider] = headers
+			default:
+				return fmt.Errorf("unknown config file key %q", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *FileLookuper) set(known map[string]struct{}, key, env string, value any) error {
+	if _, ok := known[env]; !ok {
+		return fmt.Errorf("unknown config file key %q", key)
+	}
+	s, err := stringify(key, value)
+	if err != nil {
+		return err
+	}
+	f.values[env] = s
+	return nil
+}
+
+func parseHeaders(key string, value any) (map[string][]string, error) {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("config file key %q must be a table of header names", key)
+	}
+
+	headers := make(map[string][]string, len(raw))
+	for name, v := range raw {
+		switch hv := v.(type) {
+		case []any:
+			for _, item := range hv {
+				s, err := stringify(key+"."+name, item)
+				if err != nil {
+					return nil, err
+				}
+				headers[name] = append(headers[name], s)
+			}
+		default:
+			s, err := stringify(key+"."+name, hv)
+			if err != nil {
+				return nil, err
+			}
+			headers[name] = []string{s}
+		}
+	}
+	return headers, nil
+}
+
+// stringify renders a decoded file value in the same textual form that the
+// equivalent environment variable would take.
+func stringify(key string, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := stringify(key, item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("config file key %q has unsupported value type %T", key, value)
+	}
+}
+
+// MaskedSecret replaces secret values in the output of FileValues
+const MaskedSecret = "********"
+
+// FileValues renders cfg using the config file schema accepted by ReadFile, so
+// the result can be written back out as a config file. Secrets (settings
+// marked as secret and provider API keys) are replaced with MaskedSecret when
+// maskSecrets is set.
+func (cfg *Config) FileValues(maskSecrets bool) map[string]any {
+	mask := func(v string) string {
+		if maskSecrets && v != "" {
+			return MaskedSecret
+		}
+		return v
+	}
+
+	doc := make(map[string]any)
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("env"), ",")
+		fv := v.Field(i)
+
+		nested := field.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if nested.Kind() != reflect.Struct {
+			if name != "" {
+				doc[strings.ToLower(name)] = fileValue(fv, field, mask)
+			}
+			continue
+		}
+
+		section := strings.ToLower(field.Name)
+		_, prefix, _ := strings.Cut(opts, "prefix=")
+		prefix = strings.TrimSpace(prefix)
+		values := make(map[string]any)
+		for j := range nested.NumField() {
+			sub := nested.Field(j)
+			env, _, _ := strings.Cut(sub.Tag.Get("env"), ",")
+			if env == "" {
+				continue
+			}
+			key := strings.TrimPrefix(strings.ToLower(prefix+env), section+"_")
+			values[key] = fileValue(fv.Field(j), sub, mask)
+		}
+		doc[section] = values
+	}
+
+	providers := make(map[string]any, len(cfg.Providers))
+	for id, p := range cfg.Providers {
+		values := map[string]any{
+			"api_url": p.URL,
+			"api_key": mask(p.Token),
+		}
+		if len(p.ExtraHeaders) > 0 {
+			headers := make(map[string]any, len(p.ExtraHeaders))
+			for name, hv := range p.ExtraHeaders {
+				headers[name] = hv
+			}
+			values["extra_headers"] = headers
+		}
+		providers[string(id)] = values
+	}
+	doc[providersSection] = providers
+
+	return doc
+}
+
+func fileValue(v reflect.Value, field reflect.StructField, mask func(string) string) any {
+	switch val := v.Interface().(type) {
+	case time.Duration:
+		return val.String()
+	case string:
+		if field.Tag.Get("type") == "secret" {
+			return mask(val)
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// knownEnvKeys collects every environment variable name declared on Config
+func knownEnvKeys() map[string]struct{} {
+	keys := make(map[string]struct{})
+	collectEnvKeys(reflect.TypeFor[Config](), "", keys)
+	return keys
+}
+
+func collectEnvKeys(t reflect.Type, prefix string, keys map[string]struct{}) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("env"), ",")
+		nested := field.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct {
+			_, p, _ := strings.Cut(opts, "prefix=")
+			collectEnvKeys(nested, prefix+strings.TrimSpace(p), keys)
+			continue
+		}
+		if name != "" {
+			keys[prefix+name] = struct{}{}
+		}
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,244 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/inference-gateway/config"
+	"github.com/inference-gateway/inference-gateway/internal/openapi"
+	"github.com/inference-gateway/inference-gateway/providers/constants"
+	"github.com/inference-gateway/inference-gateway/providers/registry"
+	"github.com/inference-gateway/inference-gateway/providers/types"
+	"github.com/sethvargo/go-envconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadWithFile(t *testing.T) {
+	const yamlFile = `
+environment: development
+allowed_models:
+  - openai/gpt-4o
+  - groq/llama-3.3-70b-versatile
+mcp:
+  enabled: true
+  servers:
+    - http://mcp-a:8081/mcp
+    - http://mcp-b:8082/mcp
+  client_timeout: 10s
+server:
+  port: 9090
+client:
+  max_idle_conns: 50
+providers:
+  ollama:
+    api_url: http://localhost:11434/v1
+    extra_headers:
+      X-Tenant: team-a
+      X-Tags:
+        - one
+        - two
+  groq:
+    api_key: groq-from-file
+`
+
+	const tomlFile = `
+environment = "development"
+allowed_models = ["openai/gpt-4o", "groq/llama-3.3-70b-versatile"]
+
+[mcp]
+enabled = true
+servers = ["http://mcp-a:8081/mcp", "http://mcp-b:8082/mcp"]
+client_timeout = "10s"
+
+[server]
+port = 9090
+
+[client]
+max_idle_conns = 50
+
+[providers.ollama]
+api_url = "http://localhost:11434/v1"
+
+[providers.ollama.extra_headers]
+X-Tenant = "team-a"
+X-Tags = ["one", "two"]
+
+[providers.groq]
+api_key = "groq-from-file"
+`
+
+	expected := func(mutate func(*config.Config)) config.Config {
+		return defaultConfig(func(cfg *config.Config) {
+			cfg.Environment = "development"
+			cfg.AllowedModels = "openai/gpt-4o,groq/llama-3.3-70b-versatile"
+			cfg.MCP.Enabled = true
+			cfg.MCP.Servers = "http://mcp-a:8081/mcp,http://mcp-b:8082/mcp"
+			cfg.MCP.ClientTimeout = 10 * time.Second
+			cfg.Server.Port = "9090"
+			cfg.Client.ClientMaxIdleConns = 50
+			cfg.Providers = defaultProviders(map[types.Provider]func(*registry.ProviderConfig){
+				constants.OllamaID: func(p *registry.ProviderConfig) {
+					p.URL = "http://localhost:11434/v1"
+					p.ExtraHeaders = map[string][]string{
+						"X-Tenant": {"team-a"},
+						"X-Tags":   {"one", "two"},
+					}
+				},
+				constants.GroqID: func(p *registry.ProviderConfig) { p.Token = "groq-from-file" },
+			})
+			if mutate != nil {
+				mutate(cfg)
+			}
+		})
+	}
+
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		env           map[string]string
+		expectedCfg   config.Config
+		expectedError string
+	}{
+		{
+			name:        "Success_YAML",
+			file:        "gateway.yaml",
+			content:     yamlFile,
+			expectedCfg: expected(nil),
+		},
+		{
+			name:        "Success_TOML",
+			file:        "gateway.toml",
+			content:     tomlFile,
+			expectedCfg: expected(nil),
+		},
+		{
+			name:    "Success_EnvOverridesFile",
+			file:    "gateway.yml",
+			content: yamlFile,
+			env: map[string]string{
+				"ENVIRONMENT":  "production",
+				"SERVER_PORT":  "7070",
+				"GROQ_API_KEY": "groq-from-env",
+			},
+			expectedCfg: expected(func(cfg *config.Config) {
+				cfg.Environment = "production"
+				cfg.Server.Port = "7070"
+				cfg.Providers[constants.GroqID].Token = "groq-from-env"
+			}),
+		},
+		{
+			name:          "Error_UnknownKey",
+			file:          "gateway.yaml",
+			content:       "mcp:\n  server: http://mcp:8081/mcp\n",
+			expectedError: `unknown config file key "mcp.server"`,
+		},
+		{
+			name:          "Error_UnknownProvider",
+			file:          "gateway.yaml",
+			content:       "providers:\n  acme:\n    api_key: secret\n",
+			expectedError: `config file key "providers.acme": unknown provider "acme"`,
+		},
+		{
+			name:          "Error_UnknownProviderSetting",
+			file:          "gateway.yaml",
+			content:       "providers:\n  openai:\n    token: secret\n",
+			expectedError: `unknown config file key "providers.openai.token"`,
+		},
+		{
+			name:          "Error_InvalidValue",
+			file:          "gateway.yaml",
+			content:       "server:\n  read_timeout: invalid\n",
+			expectedError: "Server: ReadTimeout: time: invalid duration \"invalid\"",
+		},
+		{
+			name:          "Error_UnsupportedExtension",
+			file:          "gateway.json",
+			content:       "{}",
+			expectedError: `unsupported config file extension ".json": expected .yaml, .yml or .toml`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+
+			cfg := &config.Config{}
+			result, err := cfg.LoadWithFile(path, envconfig.MapLookuper(tt.env))
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCfg, result)
+		})
+	}
+}
+
+func TestLoadWithFileDoesNotMutateRegistryDefaults(t *testing.T) {
+	original := registry.Registry[constants.AnthropicID].ExtraHeaders
+
+	path := writeConfigFile(t, "gateway.yaml", "providers:\n  anthropic:\n    extra_headers:\n      X-Extra: value\n")
+	cfg := &config.Config{}
+	result, err := cfg.LoadWithFile(path, envconfig.MapLookuper(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"value"}, result.Providers[constants.AnthropicID].ExtraHeaders["X-Extra"])
+	assert.Equal(t, original, registry.Registry[constants.AnthropicID].ExtraHeaders)
+	assert.NotContains(t, registry.Registry[constants.AnthropicID].ExtraHeaders, "X-Extra")
+}
+
+func TestLoadWithFileHeadersSitUnderProviderHeaders(t *testing.T) {
+	path := writeConfigFile(t, "gateway.yaml", "providers:\n  anthropic:\n    extra_headers:\n      anthropic-version: \"2000-01-01\"\n      X-Extra: value\n")
+	cfg := &config.Config{}
+	result, err := cfg.LoadWithFile(path, envconfig.MapLookuper(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"anthropic-version": {"2023-06-01"},
+		"X-Extra":           {"value"},
+	}, result.Providers[constants.AnthropicID].ExtraHeaders)
+}
+
+// TestFileKeysMatchSchema guards the config file keys documented in
+// Configurations.md against drifting from the keys the loader accepts.
+func TestFileKeysMatchSchema(t *testing.T) {
+	schema, err := openapi.Read("../openapi.yaml")
+	require.NoError(t, err)
+
+	for _, sectionMap := range schema.Components.Schemas.Config.XConfig.Sections {
+		for name, section := range sectionMap {
+			for _, setting := range section.Settings {
+				key := setting.FileKey(name)
+				content := key + " = \"\"\n"
+				if table, leaf, ok := cutLast(key, "."); ok {
+					content = "[" + table + "]\n" + leaf + " = \"\"\n"
+				}
+
+				_, err := config.ReadFile(writeConfigFile(t, "gateway.toml", content))
+				assert.NoError(t, err, "setting %s (%s)", setting.Env, key)
+			}
+		}
+	}
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
@@ -54,7 +54,7 @@ func (cfg *Config) Load(lookuper envconfig.Lookuper) (Config, error) {
 func (cfg *Config) String() string {
 	return fmt.Sprintf(
 		"Config{ApplicationName:%s, Version:%s Environment:%s, Telemetry:%+v, "+
-			"MCP:%+v, Auth:%+v, Server:%+v, Routing:%+v, Client:%+v, Providers:%+v}",
+			"MCP:%+v, Auth:%+v, Server:%+v, Routing:%+v, Cache:%+v, Truncation:%+v, Shaping:%+v, Client:%+v, Providers:%+v}",
 		APPLICATION_NAME,
 		VERSION,
 		cfg.Environment,
@@ -63,6 +63,9 @@ func (cfg *Config) String() string {
 		cfg.Auth,
 		cfg.Server,
 		cfg.Routing,
+		cfg.Cache,
+		cfg.Truncation,
+		cfg.Shaping,
 		cfg.Client,
 		cfg.Providers,
 	)
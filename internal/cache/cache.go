@@ -0,0 +1,44 @@
+// Package cache implements the exact-match response cache for chat
+// completions: the storage backends and the key derived from a request.
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// BackendMemory keeps responses in an in-process LRU
+	BackendMemory = "memory"
+	// BackendDisk keeps responses as files in a directory, surviving restarts
+	BackendDisk = "disk"
+)
+
+// Lookup results reported by the cache metrics
+const (
+	ResultHit         = "hit"
+	ResultSemanticHit = "semantic_hit"
+	ResultMiss        = "miss"
+	ResultBypass      = "bypass"
+)
+
+// Store is a response cache backend. Values are opaque to the store.
+type Store interface {
+	// Get returns the value stored under key, or false when it is missing or
+	// has expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl.
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// New creates the store for the given backend
+func New(backend, dir string, maxEntries int) (Store, error) {
+	switch backend {
+	case BackendMemory:
+		return NewMemoryStore(maxEntries), nil
+	case BackendDisk:
+		return NewDiskStore(dir)
+	default:
+		return nil, fmt.Errorf("unsupported cache backend %q: expected %s or %s", backend, BackendMemory, BackendDisk)
+	}
+}
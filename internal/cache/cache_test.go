@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewMemoryStore(2)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Set("a", []byte("1"), time.Minute))
+	require.NoError(t, s.Set("b", []byte("2"), time.Minute))
+	_, _ = s.Get("a") // b becomes the least recently used entry
+	require.NoError(t, s.Set("c", []byte("3"), time.Minute))
+
+	_, ok := s.Get("b")
+	assert.False(t, ok, "least recently used entry must be evicted")
+	v, ok := s.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(v))
+	assert.Equal(t, 2, s.Len())
+
+	now = now.Add(time.Minute)
+	_, ok = s.Get("c")
+	assert.False(t, ok, "expired entry must not be served")
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestDiskStore(t *testing.T) {
+	now := time.Unix(0, 0)
+	s, err := NewDiskStore(t.TempDir())
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Set("key", []byte(`{"id":"x"}`), time.Minute))
+	v, ok := s.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, `{"id":"x"}`, string(v))
+
+	require.NoError(t, s.Set("key", []byte(`{"id":"y"}`), time.Minute))
+	v, _ = s.Get("key")
+	assert.Equal(t, `{"id":"y"}`, string(v), "set must overwrite")
+
+	now = now.Add(time.Minute)
+	_, ok = s.Get("key")
+	assert.False(t, ok, "expired entry must not be served")
+
+	_, err = NewDiskStore("")
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(BackendMemory, "", 10)
+	assert.NoError(t, err)
+	_, err = New(BackendDisk, t.TempDir(), 0)
+	assert.NoError(t, err)
+	_, err = New("redis", "", 0)
+	assert.Error(t, err)
+}
+
+func TestChatCompletionKey(t *testing.T) {
+	decode := func(body string) types.CreateChatCompletionRequest {
+		var req types.CreateChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(body), &req))
+		return req
+	}
+	key := func(provider types.Provider, body string) string {
+		k, err := ChatCompletionKey(provider, decode(body))
+		require.NoError(t, err)
+		return k
+	}
+
+	base := key("openai", `{"model":"gpt-4o","temperature":0,"messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`)
+
+	assert.Equal(t, base, key("openai", `{ "messages": [ {"content": [{"text": "hi", "type": "text"}], "role": "user"} ], "temperature": 0, "model": "gpt-4o" }`),
+		"formatting and key order must not change the key")
+	assert.Equal(t, base, key("openai", `{"model":"gpt-4o","temperature":0,"stream":true,"stream_options":{"include_usage":true},"user":"ci","messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`),
+		"delivery options must not change the key")
+	assert.NotEqual(t, base, key("groq", `{"model":"gpt-4o","temperature":0,"messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`),
+		"provider is part of the key")
+	assert.NotEqual(t, base, key("openai", `{"model":"gpt-4o","temperature":0,"max_tokens":5,"messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`),
+		"sampling parameters are part of the key")
+	assert.NotEqual(t, base, key("openai", `{"model":"gpt-4o","temperature":0,"tools":[{"type":"function","function":{"name":"f"}}],"messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`),
+		"tools are part of the key")
+}
+
+func TestDeterministic(t *testing.T) {
+	zero, warm := float32(0), float32(0.7)
+	assert.True(t, Deterministic(types.CreateChatCompletionRequest{Temperature: &zero}))
+	assert.False(t, Deterministic(types.CreateChatCompletionRequest{Temperature: &warm}))
+	assert.False(t, Deterministic(types.CreateChatCompletionRequest{}))
+}
+
+func TestAggregateStream(t *testing.T) {
+	stream := strings.Join([]string{
+		`data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+		`data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"lo","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"ci"}}]}}]}`,
+		`data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"ty\":\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}`,
+		`data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+		`data: [DONE]`,
+	}, "\n\n") + "\n\n"
+
+	resp, ok := AggregateStream([]byte(stream))
+	require.True(t, ok)
+	assert.Equal(t, "c1", resp.ID)
+	assert.Equal(t, "chat.completion", resp.Object)
+	require.Len(t, resp.Choices, 1)
+	content, err := resp.Choices[0].Message.Content.AsMessageContent0()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", content)
+	assert.Equal(t, types.ToolCalls, resp.Choices[0].FinishReason)
+	require.NotNil(t, resp.Choices[0].Message.ToolCalls)
+	assert.Equal(t, `{"city":"Paris"}`, (*resp.Choices[0].Message.ToolCalls)[0].Function.Arguments)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, int64(5), resp.Usage.TotalTokens)
+
+	// Replaying and aggregating again must give back the same completion
+	var replayed strings.Builder
+	for _, chunk := range ReplayStream(resp) {
+		data, err := json.Marshal(chunk)
+		require.NoError(t, err)
+		replayed.WriteString("data: " + string(data) + "\n\n")
+	}
+	replayed.WriteString("data: [DONE]\n\n")
+	again, ok := AggregateStream([]byte(replayed.String()))
+	require.True(t, ok)
+	assert.Equal(t, resp, again)
+
+	_, ok = AggregateStream([]byte(strings.TrimSuffix(stream, "data: [DONE]\n\n")))
+	assert.False(t, ok, "truncated streams must not be cached")
+	_, ok = AggregateStream([]byte("data: {\"error\":\"upstream failed\"}\n\ndata: [DONE]\n\n"))
+	assert.False(t, ok, "failed streams must not be cached")
+}
+
+func TestSemanticIndex(t *testing.T) {
+	now := time.Unix(0, 0)
+	x := NewSemanticIndex(2)
+	x.now = func() time.Time { return now }
+
+	_, _, ok := x.Nearest("s", []float32{1, 0})
+	assert.False(t, ok, "empty index")
+
+	x.Add("s", []float32{1, 0}, []byte("east"), time.Minute)
+	x.Add("s", []float32{0, 2}, []byte("north"), 2*time.Minute)
+
+	v, similarity, ok := x.Nearest("s", []float32{0.1, 1})
+	require.True(t, ok)
+	assert.Equal(t, "north", string(v))
+	assert.InDelta(t, 0.995, similarity, 0.001, "similarity must not depend on vector length")
+
+	_, _, ok = x.Nearest("other", []float32{1, 0})
+	assert.False(t, ok, "scopes must not share entries")
+	_, _, ok = x.Nearest("s", []float32{1, 0, 0})
+	assert.False(t, ok, "vectors of another dimension must not match")
+	_, _, ok = x.Nearest("s", []float32{0, 0})
+	assert.False(t, ok, "zero vectors must not match")
+
+	x.Add("other", []float32{1, 0}, []byte("west"), time.Minute)
+	assert.Equal(t, 2, x.Len())
+	v, _, _ = x.Nearest("s", []float32{1, 0})
+	assert.Equal(t, "north", string(v), "the oldest entry must be evicted")
+
+	now = now.Add(time.Minute)
+	_, _, ok = x.Nearest("other", []float32{1, 0})
+	assert.False(t, ok, "expired entry must not be served")
+	assert.Equal(t, 1, x.Len())
+}
+
+func TestSemanticPrompt(t *testing.T) {
+	decode := func(body string) types.CreateChatCompletionRequest {
+		var req types.CreateChatCompletionRequest
+		require.NoError(t, json.Unmarshal([]byte(body), &req))
+		return req
+	}
+	prompt := func(identity, body string) (string, string) {
+		scope, prompt, err := SemanticPrompt("openai", identity, decode(body))
+		require.NoError(t, err)
+		return scope, prompt
+	}
+
+	scope, p := prompt("alice", `{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"What is the capital of France?"}]}`)
+	assert.Equal(t, "What is the capital of France?", p)
+
+	other, p := prompt("alice", `{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":[{"type":"text","text":"capital of"},{"type":"text","text":"France?"}]}]}`)
+	assert.Equal(t, "capital of\nFrance?", p)
+	assert.Equal(t, scope, other, "the last user message must not change the scope")
+
+	other, _ = prompt("bob", `{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"What is the capital of France?"}]}`)
+	assert.NotEqual(t, scope, other, "identity is part of the scope")
+	other, _ = prompt("alice", `{"model":"gpt-4o","messages":[{"role":"system","content":"Be verbose."},{"role":"user","content":"What is the capital of France?"}]}`)
+	assert.NotEqual(t, scope, other, "earlier messages are part of the scope")
+	other, _ = prompt("alice", `{"model":"gpt-4o-mini","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"What is the capital of France?"}]}`)
+	assert.NotEqual(t, scope, other, "the model is part of the scope")
+
+	_, p = prompt("alice", `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"},{"role":"assistant","content":"hello"}]}`)
+	assert.Empty(t, p, "the last message must be a user message")
+	_, p = prompt("alice", `{"model":"gpt-4o","messages":[{"role":"user","content":[{"type":"text","text":"what is this?"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AA=="}}]}]}`)
+	assert.Empty(t, p, "images cannot be matched semantically")
+	_, p = prompt("alice", `{"model":"gpt-4o","messages":[]}`)
+	assert.Empty(t, p)
+}
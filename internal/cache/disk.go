@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// diskHeaderSize is the size of the expiry timestamp (unix nanoseconds,
+// big-endian) written before every value
+const diskHeaderSize = 8
+
+// DiskStore keeps each value in its own file under a directory. Keys must be
+// safe to use as file names, which holds for the hex digests built by
+// ChatCompletionKey.
+type DiskStore struct {
+	dir string
+	now func() time.Time
+}
+
+// NewDiskStore creates a store in dir, creating the directory if needed
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if dir == "" {
+		return nil, errors.New("cache directory is required for the disk backend")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskStore{dir: dir, now: time.Now}, nil
+}
+
+// Get implements Store
+func (s *DiskStore) Get(key string) ([]byte, bool) {
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) < diskHeaderSize {
+		return nil, false
+	}
+	expiresAt := time.Unix(0, int64(binary.BigEndian.Uint64(data[:diskHeaderSize])))
+	if !s.now().Before(expiresAt) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return data[diskHeaderSize:], true
+}
+
+// Set implements Store. The value is written to a temporary file and renamed
+// into place so concurrent readers never see a partial entry.
+func (s *DiskStore) Set(key string, value []byte, ttl time.Duration) error {
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	var header [diskHeaderSize]byte
+	binary.BigEndian.PutUint64(header[:], uint64(s.now().Add(ttl).UnixNano()))
+	_, err = tmp.Write(header[:])
+	if err == nil {
+		_, err = tmp.Write(value)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	return nil
+}
+
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.dir, key)
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Embedder turns text into an embedding vector
+type Embedder interface {
+	Embed(ctx context.Context, input string) ([]float32, error)
+}
+
+// ProviderEmbedder computes embeddings with a configured provider through its
+// OpenAI-compatible /embeddings endpoint. The provider is built from the
+// registry on every call so reloaded credentials take effect.
+type ProviderEmbedder struct {
+	registry registry.ProviderRegistry
+	client   client.Client
+	provider types.Provider
+	model    string
+}
+
+// NewProviderEmbedder creates an embedder using model on provider
+func NewProviderEmbedder(providerRegistry registry.ProviderRegistry, httpClient client.Client, provider types.Provider, model string) *ProviderEmbedder {
+	return &ProviderEmbedder{
+		registry: providerRegistry,
+		client:   httpClient,
+		provider: provider,
+		model:    model,
+	}
+}
+
+type embeddingsRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed implements Embedder
+func (e *ProviderEmbedder) Embed(ctx context.Context, input string) ([]float32, error) {
+	provider, err := e.registry.BuildProvider(e.provider, e.client)
+	if err != nil {
+		return nil, err
+	}
+	u, err := core.BuildURL(provider, "/embeddings", "")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(embeddingsRequest{Model: e.model, Input: input})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if err := core.ApplyAuth(req, provider); err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("embeddings request failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var parsed embeddingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+	if len(parsed.Data) == 0 || len(parsed.Data[0].Embedding) == 0 {
+		return nil, errors.New("embeddings response contains no embedding")
+	}
+	return parsed.Data[0].Embedding, nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// ChatCompletionKey returns the cache key of a chat completion request sent to
+// provider. The key covers everything that shapes the completion (model,
+// messages, tools, sampling parameters) and ignores how it is delivered
+// (stream, stream_options) and who asked (user), so a streamed and a
+// non-streamed request share an entry.
+//
+// The request is canonicalized by re-encoding it through a generic value:
+// object keys are sorted and whitespace is dropped, so clients that serialize
+// the same request differently still hit the same entry.
+func ChatCompletionKey(provider types.Provider, req types.CreateChatCompletionRequest) (string, error) {
+	req.Stream = nil
+	req.StreamOptions = nil
+	req.User = nil
+
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	var generic any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(provider))
+	h.Write([]byte{0})
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Deterministic reports whether req asks for a reproducible completion, i.e.
+// it was sent with temperature 0.
+func Deterministic(req types.CreateChatCompletionRequest) bool {
+	return req.Temperature != nil && *req.Temperature == 0
+}
+
+// SemanticPrompt splits req into the prompt matched by the semantic cache,
+// the text of the last user message, and the scope it is matched in. The
+// scope covers the provider, the caller identity and everything in the
+// request except that message (model, earlier messages, tools, sampling
+// parameters), so a cached answer is only reused for the same model, caller
+// and conversation. The prompt is empty when the request cannot be matched
+// semantically: its last message is not a plain-text user message.
+func SemanticPrompt(provider types.Provider, identity string, req types.CreateChatCompletionRequest) (scope, prompt string, err error) {
+	if len(req.Messages) == 0 {
+		return "", "", nil
+	}
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role != types.User || last.HasImageContent() {
+		return "", "", nil
+	}
+	prompt = last.TextContent()
+	if prompt == "" {
+		return "", "", nil
+	}
+
+	req.Messages = req.Messages[:len(req.Messages)-1]
+	rest, err := ChatCompletionKey(provider, req)
+	if err != nil {
+		return "", "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(identity))
+	h.Write([]byte{0})
+	h.Write([]byte(rest))
+	return hex.EncodeToString(h.Sum(nil)), prompt, nil
+}
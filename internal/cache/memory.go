@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process LRU store bounded by entry count
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front is most recently used
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an LRU store holding at most maxEntries values. A
+// non-positive maxEntries leaves the store unbounded.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.remove(elem)
+		return nil, false
+	}
+	s.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := s.now().Add(ttl)
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Len returns the number of stored entries, including expired ones not yet
+// evicted.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.entries, elem.Value.(*memoryEntry).key)
+}
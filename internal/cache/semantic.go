@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+// SemanticIndex is an in-process nearest-neighbour index of embedded prompts.
+// Entries are partitioned by scope and only compared within their scope; a
+// lookup is a linear scan, which is fast enough for the few thousand entries
+// a gateway replica keeps.
+type SemanticIndex struct {
+	mu         sync.Mutex
+	maxEntries int
+	scopes     map[string][]*vectorEntry
+	order      *list.List // insertion order across scopes, oldest at the back
+	now        func() time.Time
+}
+
+type vectorEntry struct {
+	scope     string
+	vector    []float32 // unit length
+	value     []byte
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+// NewSemanticIndex creates an index holding at most maxEntries vectors across
+// all scopes, evicting the oldest first. A non-positive maxEntries leaves the
+// index unbounded.
+func NewSemanticIndex(maxEntries int) *SemanticIndex {
+	return &SemanticIndex{
+		maxEntries: maxEntries,
+		scopes:     make(map[string][]*vectorEntry),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Nearest returns the value of the entry in scope most similar to vector and
+// its cosine similarity. Expired entries are dropped along the way.
+func (x *SemanticIndex) Nearest(scope string, vector []float32) ([]byte, float64, bool) {
+	query := normalize(vector)
+	if query == nil {
+		return nil, 0, false
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	now := x.now()
+	var best *vectorEntry
+	var expired []*vectorEntry
+	bestSimilarity := math.Inf(-1)
+	for _, entry := range x.scopes[scope] {
+		if !now.Before(entry.expiresAt) {
+			expired = append(expired, entry)
+			continue
+		}
+		if len(entry.vector) != len(query) {
+			// The embedding model changed since the entry was stored
+			continue
+		}
+		if similarity := dot(entry.vector, query); similarity > bestSimilarity {
+			best, bestSimilarity = entry, similarity
+		}
+	}
+	for _, entry := range expired {
+		x.remove(entry)
+	}
+	if best == nil {
+		return nil, 0, false
+	}
+	return best.value, bestSimilarity, true
+}
+
+// Add stores value under vector in scope for ttl
+func (x *SemanticIndex) Add(scope string, vector []float32, value []byte, ttl time.Duration) {
+	normalized := normalize(vector)
+	if normalized == nil {
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	entry := &vectorEntry{scope: scope, vector: normalized, value: value, expiresAt: x.now().Add(ttl)}
+	entry.elem = x.order.PushFront(entry)
+	x.scopes[scope] = append(x.scopes[scope], entry)
+	for x.maxEntries > 0 && x.order.Len() > x.maxEntries {
+		x.remove(x.order.Back().Value.(*vectorEntry))
+	}
+}
+
+// Len returns the number of indexed vectors
+func (x *SemanticIndex) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.order.Len()
+}
+
+func (x *SemanticIndex) remove(entry *vectorEntry) {
+	x.order.Remove(entry.elem)
+	entries := x.scopes[entry.scope]
+	for i, e := range entries {
+		if e == entry {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+	if len(entries) == 0 {
+		delete(x.scopes, entry.scope)
+	} else {
+		x.scopes[entry.scope] = entries
+	}
+}
+
+// normalize returns vector scaled to unit length, or nil for a zero vector
+func normalize(vector []float32) []float32 {
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return nil
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float32, len(vector))
+	for i, v := range vector {
+		out[i] = float32(float64(v) / norm)
+	}
+	return out
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// AggregateStream rebuilds the chat completion carried by an SSE stream body
+// so a streamed response can be cached like a regular one. It returns false
+// when the stream did not finish with [DONE] or contained an error event, as
+// a truncated or failed stream must not be cached.
+func AggregateStream(body []byte) (types.CreateChatCompletionResponse, bool) {
+	acc := core.NewStreamAccumulator()
+	done := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+
+		var chunk types.CreateChatCompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return types.CreateChatCompletionResponse{}, false
+		}
+		if chunk.ID == "" && chunk.Choices == nil && chunk.Usage == nil {
+			// e.g. {"error": ...} events
+			return types.CreateChatCompletionResponse{}, false
+		}
+		acc.Add(chunk)
+	}
+	if !done {
+		return types.CreateChatCompletionResponse{}, false
+	}
+
+	resp, err := acc.Response()
+	if err != nil {
+		return types.CreateChatCompletionResponse{}, false
+	}
+	return resp, true
+}
+
+// ReplayStream converts a cached chat completion into the chunks of an SSE
+// stream, see core.SynthesizeStream
+func ReplayStream(resp types.CreateChatCompletionResponse) []types.CreateChatCompletionStreamResponse {
+	return core.SynthesizeStream(resp)
+}
@@ -73,6 +73,15 @@ type Config struct {
 	{{- else if eq $name "routing" }}
 	// Routing settings
 	Routing *RoutingConfig ` + "`env:\", prefix=ROUTING_\" description:\"Routing configuration\"`" + `
+	{{- else if eq $name "cache" }}
+	// Cache settings
+	Cache *CacheConfig ` + "`env:\", prefix=CACHE_\" description:\"Cache configuration\"`" + `
+	{{- else if eq $name "truncation" }}
+	// Truncation settings
+	Truncation *TruncationConfig ` + "`env:\", prefix=TRUNCATION_\" description:\"Truncation configuration\"`" + `
+	{{- else if eq $name "shaping" }}
+	// Shaping settings
+	Shaping *ShapingConfig ` + "`env:\", prefix=SHAPING_\" description:\"Shaping configuration\"`" + `
 	{{- else if eq $name "client" }}
 	// Client settings
 	Client *client.ClientConfig ` + "`description:\"Client configuration\"`" + `
@@ -134,6 +143,30 @@ type RoutingConfig struct {
 	{{ pascalCase (trimPrefix $field.Env "ROUTING_") }} {{ $field.Type }} ` + "`env:\"{{ trimPrefix $field.Env \"ROUTING_\" }}{{if $field.Default}}, default={{$field.Default}}{{end}}\" description:\"{{$field.Description}}\"`" + `
 	{{- end }}
 }
+{{- else if eq $name "cache" }}
+
+// Cache configuration
+type CacheConfig struct {
+	{{- range $field := $section.Settings }}
+	{{ pascalCase (trimPrefix $field.Env "CACHE_") }} {{ $field.Type }} ` + "`env:\"{{ trimPrefix $field.Env \"CACHE_\" }}{{if $field.Default}}, default={{$field.Default}}{{end}}\" description:\"{{$field.Description}}\"`" + `
+	{{- end }}
+}
+{{- else if eq $name "truncation" }}
+
+// Truncation configuration
+type TruncationConfig struct {
+	{{- range $field := $section.Settings }}
+	{{ pascalCase (trimPrefix $field.Env "TRUNCATION_") }} {{ $field.Type }} ` + "`env:\"{{ trimPrefix $field.Env \"TRUNCATION_\" }}{{if $field.Default}}, default={{$field.Default}}{{end}}\" description:\"{{$field.Description}}\"`" + `
+	{{- end }}
+}
+{{- else if eq $name "shaping" }}
+
+// Shaping configuration
+type ShapingConfig struct {
+	{{- range $field := $section.Settings }}
+	{{ pascalCase (trimPrefix $field.Env "SHAPING_") }} {{ $field.Type }} ` + "`env:\"{{ trimPrefix $field.Env \"SHAPING_\" }}{{if $field.Default}}, default={{$field.Default}}{{end}}\" description:\"{{$field.Description}}\"`" + `
+	{{- end }}
+}
 {{- end }}
 {{- end }}
 {{- end }}
@@ -207,6 +240,9 @@ const (
     {{- range $name, $config := .Providers }}
     {{pascalCase $name}}ModelsEndpoint = "{{(index $config.Endpoints "models").Endpoint}}"
     {{pascalCase $name}}ChatEndpoint   = "{{(index $config.Endpoints "chat").Endpoint}}"
+    {{- with (index $config.Endpoints "completions").Endpoint }}
+    {{pascalCase $name}}CompletionsEndpoint = "{{.}}"
+    {{- end }}
     {{- with (index $config.Endpoints "responses").Endpoint }}
     {{pascalCase $name}}ResponsesEndpoint = "{{.}}"
     {{- end }}
@@ -219,6 +255,21 @@ const (
     {{- with (index $config.Endpoints "images_variations").Endpoint }}
     {{pascalCase $name}}ImagesVariationsEndpoint = "{{.}}"
     {{- end }}
+    {{- with (index $config.Endpoints "audio_transcriptions").Endpoint }}
+    {{pascalCase $name}}AudioTranscriptionsEndpoint = "{{.}}"
+    {{- end }}
+    {{- with (index $config.Endpoints "audio_translations").Endpoint }}
+    {{pascalCase $name}}AudioTranslationsEndpoint = "{{.}}"
+    {{- end }}
+    {{- with (index $config.Endpoints "audio_speech").Endpoint }}
+    {{pascalCase $name}}AudioSpeechEndpoint = "{{.}}"
+    {{- end }}
+    {{- with (index $config.Endpoints "rerank").Endpoint }}
+    {{pascalCase $name}}RerankEndpoint = "{{.}}"
+    {{- end }}
+    {{- with (index $config.Endpoints "moderations").Endpoint }}
+    {{pascalCase $name}}ModerationsEndpoint = "{{.}}"
+    {{- end }}
     {{- end }}
 )
 
@@ -607,6 +658,9 @@ var Registry = map[types.Provider]*ProviderConfig{
 		Endpoints: types.Endpoints{
 			Models: constants.{{pascalCase $name}}ModelsEndpoint,
 			Chat:   constants.{{pascalCase $name}}ChatEndpoint,
+			{{- if (index $config.Endpoints "completions").Endpoint }}
+			Completions: ptr(constants.{{pascalCase $name}}CompletionsEndpoint),
+			{{- end }}
 			{{- if (index $config.Endpoints "responses").Endpoint }}
 			Responses: ptr(constants.{{pascalCase $name}}ResponsesEndpoint),
 			{{- end }}
@@ -619,6 +673,21 @@ var Registry = map[types.Provider]*ProviderConfig{
 			{{- if (index $config.Endpoints "images_variations").Endpoint }}
 			ImagesVariations: ptr(constants.{{pascalCase $name}}ImagesVariationsEndpoint),
 			{{- end }}
+			{{- if (index $config.Endpoints "audio_transcriptions").Endpoint }}
+			AudioTranscriptions: ptr(constants.{{pascalCase $name}}AudioTranscriptionsEndpoint),
+			{{- end }}
+			{{- if (index $config.Endpoints "audio_translations").Endpoint }}
+			AudioTranslations: ptr(constants.{{pascalCase $name}}AudioTranslationsEndpoint),
+			{{- end }}
+			{{- if (index $config.Endpoints "audio_speech").Endpoint }}
+			AudioSpeech: ptr(constants.{{pascalCase $name}}AudioSpeechEndpoint),
+			{{- end }}
+			{{- if (index $config.Endpoints "rerank").Endpoint }}
+			Rerank: ptr(constants.{{pascalCase $name}}RerankEndpoint),
+			{{- end }}
+			{{- if (index $config.Endpoints "moderations").Endpoint }}
+			Moderations: ptr(constants.{{pascalCase $name}}ModerationsEndpoint),
+			{{- end }}
 		},
 	},
 	{{- end }}
@@ -0,0 +1,267 @@
+package guardrails
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// ---------------------------------------------------------------------------
+// Classifiers - model-based detection, e.g. moderation.
+// ---------------------------------------------------------------------------
+
+// Classifier labels text with the categories it is flagged in.
+type Classifier interface {
+	Classify(ctx context.Context, text string) ([]string, error)
+}
+
+// ModerationClassifier classifies text with a provider's Moderations API. The
+// provider is built from the registry on every call so reloaded credentials
+// take effect.
+type ModerationClassifier struct {
+	registry registry.ProviderRegistry
+	client   client.Client
+	provider types.Provider
+	model    string
+}
+
+// NewModerationClassifier creates a classifier using model on provider.
+func NewModerationClassifier(providerRegistry registry.ProviderRegistry, httpClient client.Client, provider types.Provider, model string) *ModerationClassifier {
+	return &ModerationClassifier{
+		registry: providerRegistry,
+		client:   httpClient,
+		provider: provider,
+		model:    model,
+	}
+}
+
+type moderationRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type moderationResponse struct {
+	Results []struct {
+		Categories map[string]bool `json:"categories"`
+	} `json:"results"`
+}
+
+// Classify implements Classifier, returning the flagged categories sorted by
+// name.
+func (m *ModerationClassifier) Classify(ctx context.Context, text string) ([]string, error) {
+	provider, err := m.registry.BuildProvider(m.provider, m.client)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := provider.GetEndpoints().Moderations
+	if endpoint == nil || *endpoint == "" {
+		return nil, fmt.Errorf("guardrails: provider %s does not support moderations", m.provider)
+	}
+	u, err := core.BuildURL(provider, *endpoint, "")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(moderationRequest{Model: m.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("guardrails: moderation marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("guardrails: moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if err := core.ApplyAuth(req, provider); err != nil {
+		return nil, err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("guardrails: moderation call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("guardrails: moderation failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var parsed moderationResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("guardrails: moderation decode: %w", err)
+	}
+
+	var flagged []string
+	for _, result := range parsed.Results {
+		for category, hit := range result.Categories {
+			if hit && !slices.Contains(flagged, category) {
+				flagged = append(flagged, category)
+			}
+		}
+	}
+	slices.Sort(flagged)
+	return flagged, nil
+}
+
+// Moderation blocks content a Classifier flags in a blocked category.
+type Moderation struct {
+	classifier Classifier
+	categories []string
+	phases     []Phase
+}
+
+// NewModeration creates a moderation check blocking the comma-separated
+// categories (any flagged category when empty) in the comma-separated phases,
+// which may be pre_call and post_call.
+func NewModeration(classifier Classifier, categories, phases string) (*Moderation, error) {
+	m := &Moderation{classifier: classifier, categories: splitList(categories)}
+	for _, phase := range splitList(phases) {
+		switch Phase(phase) {
+		case PhasePreCall, PhasePostCall:
+			m.phases = append(m.phases, Phase(phase))
+		default:
+			return nil, fmt.Errorf("guardrails: unsupported moderation phase %q: expected %s or %s", phase, PhasePreCall, PhasePostCall)
+		}
+	}
+	return m, nil
+}
+
+// Check classifies the text of a request or response body of contentType and
+// returns a block decision when it is flagged in a blocked category during
+// phase.
+func (m *Moderation) Check(ctx context.Context, phase Phase, contentType, body string) (Decision, error) {
+	if !slices.Contains(m.phases, phase) {
+		return Decision{Action: ActionAllow}, nil
+	}
+	text := contentText(contentType, body)
+	if text == "" {
+		return Decision{Action: ActionAllow}, nil
+	}
+
+	flagged, err := m.classifier.Classify(ctx, text)
+	if err != nil {
+		return Decision{}, err
+	}
+	var blocked []string
+	for _, category := range flagged {
+		if len(m.categories) == 0 || slices.Contains(m.categories, category) {
+			blocked = append(blocked, category)
+		}
+	}
+	if len(blocked) == 0 {
+		return Decision{Action: ActionAllow}, nil
+	}
+	return Decision{
+		Action:  ActionBlock,
+		Message: "content flagged by moderation: " + strings.Join(blocked, ", "),
+	}, nil
+}
+
+// contentFields are the body fields carrying text for the model or from it,
+// across the chat completions, Responses and Messages APIs.
+var contentFields = []string{"content", "text", "input", "prompt", "instructions", "system"}
+
+// maxFormFieldSize bounds the text read from a multipart form field
+const maxFormFieldSize = 1 << 20
+
+// contentText extracts the text a model reads or writes from a body of
+// contentType: the content fields of a JSON body or of a multipart form,
+// leaving out model names, roles and other metadata, and text bodies, such as
+// plain, srt or vtt transcriptions, as they are. Files and binary media, such
+// as audio, have no text to classify.
+func contentText(contentType, body string) string {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "multipart/form-data":
+		return formContentText(params["boundary"], body)
+	case binaryMediaType(mediaType) || !utf8.ValidString(body):
+		return ""
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return strings.TrimSpace(body)
+	}
+	var parts []string
+	collectContent(doc, false, &parts)
+	return strings.Join(parts, "\n")
+}
+
+// binaryMediaType reports whether bodies of mediaType are binary media
+func binaryMediaType(mediaType string) bool {
+	if mediaType == "application/octet-stream" {
+		return true
+	}
+	kind, _, _ := strings.Cut(mediaType, "/")
+	return kind == "audio" || kind == "image" || kind == "video"
+}
+
+// formContentText extracts the content fields of a multipart form, skipping
+// its files
+func formContentText(boundary, body string) string {
+	if boundary == "" {
+		return ""
+	}
+	reader := multipart.NewReader(strings.NewReader(body), boundary)
+	var parts []string
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		if part.FileName() != "" || !slices.Contains(contentFields, part.FormName()) {
+			continue
+		}
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize))
+		if err != nil {
+			break
+		}
+		if text := string(value); strings.TrimSpace(text) != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// collectContent appends the strings of v held by a content field, directly
+// or in an array.
+func collectContent(v any, field bool, parts *[]string) {
+	switch v := v.(type) {
+	case string:
+		if field && strings.TrimSpace(v) != "" {
+			*parts = append(*parts, v)
+		}
+	case []any:
+		for _, item := range v {
+			collectContent(item, field, parts)
+		}
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			collectContent(v[key], slices.Contains(contentFields, key), parts)
+		}
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
 	logger "github.com/inference-gateway/inference-gateway/logger"
@@ -23,6 +25,11 @@ import (
 // MaxAgentIterations limits the number of agent loop iterations
 const MaxAgentIterations = 10
 
+// ToolApprover decides whether the agent may execute a tool call. Denied
+// calls are answered with an error tool message instead; an error stops the
+// agent.
+type ToolApprover func(ctx context.Context, toolCall types.ChatCompletionMessageToolCall) (bool, error)
+
 // Agent defines the interface for running agent operations
 //
 //go:generate mockgen -source=agent.go -destination=../../tests/mocks/mcp/agent.go -package=mcpmocks -typed
@@ -33,6 +40,8 @@ type Agent interface {
 	SetProvider(provider core.IProvider)
 	SetModel(model *string)
 	SetGuardrails(evaluator *guardrails.Evaluator, telemetry otel.OpenTelemetry, failMode string)
+	SetStreamIdleTimeout(timeout time.Duration)
+	SetToolApprover(approver ToolApprover)
 }
 
 // Ensure agentImpl implements Agent interface at compile time
@@ -47,6 +56,8 @@ type agentImpl struct {
 	guardrailsEvaluator *guardrails.Evaluator
 	guardrailsTelemetry otel.OpenTelemetry
 	guardrailsFailMode  string
+	streamIdleTimeout   time.Duration
+	toolApprover        ToolApprover
 }
 
 // NewAgent creates a new Agent instance
@@ -87,6 +98,19 @@ func (a *agentImpl) SetGuardrails(evaluator *guardrails.Evaluator, telemetry ote
 	}
 }
 
+// SetStreamIdleTimeout sets how long RunWithStream waits for the provider
+// stream before failing; zero waits indefinitely.
+func (a *agentImpl) SetStreamIdleTimeout(timeout time.Duration) {
+	a.streamIdleTimeout = timeout
+}
+
+// SetToolApprover makes the agent ask approver before executing each tool
+// call; mcp_tools_get lookups are answered without asking. nil executes
+// every call.
+func (a *agentImpl) SetToolApprover(approver ToolApprover) {
+	a.toolApprover = approver
+}
+
 func (a *agentImpl) Run(ctx context.Context, request *types.CreateChatCompletionRequest, response *types.CreateChatCompletionResponse) error {
 	if a.provider == nil {
 		return errors.New("provider is not set for agent")
@@ -159,21 +183,31 @@ func (a *agentImpl) RunWithStream(ctx context.Context, middlewareStreamCh chan [
 	currentRequest := *body
 
 	currentRequest.Model = *a.model
+	usage := core.StreamUsageFrom(ctx)
 	a.logger.Debug("starting agent streaming", "model", currentRequest.Model, "max_iterations", MaxAgentIterations)
 
 	defer func() {
 		a.logger.Debug("sending agent completion signal")
-		send(ctx, middlewareStreamCh, []byte("data: [DONE]\n\n"))
+		send(ctx, middlewareStreamCh, core.StreamDone)
 	}()
 
+	// idle fires when the provider stream of the iteration has been silent
+	// for the stream idle timeout; nil never fires
+	var idle <-chan time.Time
+	var idleTimer *time.Timer
+	if a.streamIdleTimeout > 0 {
+		idleTimer = time.NewTimer(a.streamIdleTimeout)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
+
 	for iteration := range MaxAgentIterations {
 		a.logger.Debug("streaming iteration", "iteration", iteration+1, "max_iterations", MaxAgentIterations)
 
 		streamCh, err := a.provider.StreamChatCompletions(ctx, currentRequest)
 		if err != nil {
 			a.logger.Error("failed to start streaming", err, "iteration", iteration+1, "model", *a.model)
-			errorData := []byte(fmt.Sprintf("data: {\"error\": \"Failed to start streaming: %s\"}\n\n", err.Error()))
-			send(ctx, middlewareStreamCh, errorData)
+			send(ctx, middlewareStreamCh, core.StreamEvent{Err: err}.SSE())
 			return err
 		}
 
@@ -189,46 +223,37 @@ func (a *agentImpl) RunWithStream(ctx context.Context, middlewareStreamCh chan [
 
 		streamComplete := false
 		hasToolCalls := false
+		if idleTimer != nil {
+			idleTimer.Reset(a.streamIdleTimeout)
+		}
 
 		for !streamComplete {
 			select {
-			case line, ok := <-streamCh:
+			case event, ok := <-streamCh:
+				if idleTimer != nil {
+					idleTimer.Reset(a.streamIdleTimeout)
+				}
 				if !ok {
 					a.logger.Debug("stream channel closed", "iteration", iteration+1)
 					streamComplete = true
 					break
 				}
 
-				lineStr := string(line)
-				trimmedLine := strings.TrimSpace(lineStr)
-
-				if strings.Contains(trimmedLine, "[DONE]") {
-					responseBodyBuilder.Write(line)
-					continue
-				}
-
-				if !strings.HasPrefix(trimmedLine, "data: ") {
-					continue
-				}
-
-				chunkData := strings.TrimPrefix(trimmedLine, "data: ")
-				if chunkData == "" {
-					continue
+				if event.Err != nil {
+					a.logger.Error("provider stream failed", event.Err, "iteration", iteration+1, "model", *a.model)
+					send(ctx, middlewareStreamCh, event.SSE())
+					return event.Err
 				}
 
-				formattedData := []byte(fmt.Sprintf("data: %s\n\n", chunkData))
+				usage.Observe(event)
+				formattedData := event.SSE()
 				if !send(ctx, middlewareStreamCh, formattedData) {
 					a.logger.Debug("context cancelled while sending stream chunk", "iteration", iteration+1)
 					return ctx.Err()
 				}
 				responseBodyBuilder.Write(formattedData)
 
-				var resp types.CreateChatCompletionStreamResponse
-				if err := json.Unmarshal([]byte(chunkData), &resp); err != nil {
-					a.logger.Debug("failed to unmarshal streaming chunk", err, "chunk_data", chunkData, "iteration", iteration+1)
-					continue
-				}
-
+				resp := event.Chunk
 				if len(resp.Choices) == 0 {
 					continue
 				}
@@ -268,6 +293,16 @@ func (a *agentImpl) RunWithStream(ctx context.Context, middlewareStreamCh chan [
 					streamComplete = true
 				}
 
+			case <-idle:
+				err := &core.UpstreamError{
+					Code:       core.ErrorCodeTimeout,
+					StatusCode: http.StatusGatewayTimeout,
+					Message:    fmt.Sprintf("upstream stream idle for %s", a.streamIdleTimeout),
+				}
+				a.logger.Error("provider stream failed", err, "iteration", iteration+1, "model", *a.model)
+				send(ctx, middlewareStreamCh, core.StreamEvent{Err: err}.SSE())
+				return err
+
 			case <-ctx.Done():
 				a.logger.Debug("context cancelled during streaming", "iteration", iteration+1)
 				return ctx.Err()
@@ -275,6 +310,7 @@ func (a *agentImpl) RunWithStream(ctx context.Context, middlewareStreamCh chan [
 		}
 
 		a.logger.Debug("stream completed for iteration", "iteration", iteration+1, "has_tool_calls", hasToolCalls)
+		usage.EndStream()
 
 		var toolCalls []types.ChatCompletionMessageToolCall
 		if hasToolCalls {
@@ -295,8 +331,7 @@ func (a *agentImpl) RunWithStream(ctx context.Context, middlewareStreamCh chan [
 		toolResults, err := a.ExecuteTools(ctx, toolCalls)
 		if err != nil {
 			a.logger.Error("failed to execute tool calls", err, "iteration", iteration+1, "tool_count", len(toolCalls))
-			errorData := []byte(fmt.Sprintf("data: {\"error\": \"Failed to execute tools: %s\"}\n\n", err.Error()))
-			send(ctx, middlewareStreamCh, errorData)
+			send(ctx, middlewareStreamCh, core.StreamEvent{Err: fmt.Errorf("Failed to execute tools: %w", err)}.SSE())
 			return err
 		}
 
@@ -320,6 +355,18 @@ func (a *agentImpl) ExecuteTools(ctx context.Context, toolCalls []types.ChatComp
 	var results []types.Message
 
 	for _, toolCall := range toolCalls {
+		if a.toolApprover != nil && toolCall.Function.Name != SelectorToolGet {
+			approved, err := a.toolApprover(ctx, toolCall)
+			if err != nil {
+				return nil, err
+			}
+			if !approved {
+				a.logger.Info("tool call denied", "tool_call_id", toolCall.ID, "tool_name", toolCall.Function.Name)
+				results = append(results, a.toolMessage(toolCall.ID, "Error: the tool call was denied by the user"))
+				continue
+			}
+		}
+
 		switch toolCall.Function.Name {
 		case SelectorToolGet:
 			results = append(results, a.handleToolsGet(toolCall))
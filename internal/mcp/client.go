@@ -42,6 +42,10 @@ type MCPClientInterface interface {
 	// InitializeAll establishes connection with MCP servers and performs handshake
 	InitializeAll(ctx context.Context) error
 
+	// UpdateServers reconciles the configured servers with serverURLs,
+	// initializing added servers and dropping removed ones
+	UpdateServers(ctx context.Context, serverURLs []string) error
+
 	// IsInitialized returns whether the client has been successfully initialized
 	IsInitialized() bool
 
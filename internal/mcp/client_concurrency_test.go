@@ -17,6 +17,7 @@ import (
 
 	config "github.com/inference-gateway/inference-gateway/config"
 	logger "github.com/inference-gateway/inference-gateway/logger"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
 )
@@ -165,12 +166,17 @@ func TestRunWithStreamReturnsWhenConsumerAbandons(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	provider := providersmocks.NewMockIProvider(ctrl)
 
-	streamCh := make(chan []byte)
+	streamCh := make(chan core.StreamEvent)
 	done := make(chan struct{})
 	defer close(done)
 
 	go func() {
-		chunk := []byte(`data: {"choices":[{"delta":{"content":"x"}}]}` + "\n")
+		chunk := core.StreamEvent{
+			Chunk: &types.CreateChatCompletionStreamResponse{
+				Choices: []types.ChatCompletionStreamChoice{{Delta: types.ChatCompletionStreamResponseDelta{Content: "x"}}},
+			},
+			Data: []byte(`{"choices":[{"delta":{"content":"x"}}]}`),
+		}
 		for {
 			select {
 			case streamCh <- chunk:
@@ -180,7 +186,7 @@ func TestRunWithStreamReturnsWhenConsumerAbandons(t *testing.T) {
 		}
 	}()
 
-	provider.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).Return((<-chan []byte)(streamCh), nil).AnyTimes()
+	provider.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).Return((<-chan core.StreamEvent)(streamCh), nil).AnyTimes()
 
 	model := "openai/gpt-4o"
 	agent := &agentImpl{
@@ -207,3 +213,52 @@ func TestRunWithStreamReturnsWhenConsumerAbandons(t *testing.T) {
 		t.Fatal("RunWithStream did not return after the consumer stopped draining")
 	}
 }
+
+func TestRunWithStreamFailsIdleProviderStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := providersmocks.NewMockIProvider(ctrl)
+	provider.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).Return(make(<-chan core.StreamEvent), nil)
+
+	model := "openai/gpt-4o"
+	agent := &agentImpl{
+		logger:   logger.NewNoopLogger(),
+		provider: provider,
+		model:    &model,
+	}
+	agent.SetStreamIdleTimeout(50 * time.Millisecond)
+
+	middlewareCh := make(chan []byte, 2)
+	err := agent.RunWithStream(context.Background(), middlewareCh, &types.CreateChatCompletionRequest{})
+	require.EqualError(t, err, "upstream stream idle for 50ms")
+	assert.Equal(t, "data: {\"error\":{\"message\":\"upstream stream idle for 50ms\",\"type\":\"timeout_error\",\"code\":\"timeout\",\"param\":null}}\n\n", string(<-middlewareCh))
+	assert.Equal(t, core.StreamDone, <-middlewareCh)
+}
+
+func TestUpdateServersInitializesOnlyAddedServers(t *testing.T) {
+	var keptInits, addedInits atomic.Int32
+	kept := newMCPStubServer(t, 0, &keptInits)
+	removed := newMCPStubServer(t, 0, nil)
+	added := newMCPStubServer(t, 0, &addedInits)
+
+	mc := NewMCPClient([]string{kept.URL, removed.URL}, logger.NewNoopLogger(), newStubMCPConfig()).(*MCPClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	require.NoError(t, mc.InitializeAll(ctx))
+	require.Equal(t, int32(1), keptInits.Load())
+
+	require.NoError(t, mc.UpdateServers(ctx, []string{kept.URL, added.URL}))
+
+	assert.Equal(t, int32(1), keptInits.Load(), "unchanged server must keep its connection")
+	assert.Equal(t, int32(1), addedInits.Load())
+	assert.ElementsMatch(t, []string{kept.URL, added.URL}, mc.GetServers())
+
+	statuses := mc.GetAllServerStatuses()
+	assert.NotContains(t, statuses, removed.URL)
+	assert.Equal(t, ServerStatusAvailable, statuses[added.URL])
+
+	_, err := mc.GetServerTools(removed.URL)
+	assert.Error(t, err)
+	assert.Len(t, mc.GetAllChatCompletionTools(), 2)
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"maps"
+	"slices"
 	"time"
 )
 
@@ -58,7 +59,11 @@ func (mc *MCPClient) statusPollingLoop(ctx context.Context) {
 
 // pollServerStatuses checks the health status of all servers
 func (mc *MCPClient) pollServerStatuses(ctx context.Context) {
-	for _, serverURL := range mc.ServerURLs {
+	mc.mu.RLock()
+	serverURLs := slices.Clone(mc.ServerURLs)
+	mc.mu.RUnlock()
+
+	for _, serverURL := range serverURLs {
 		go mc.checkServerHealth(ctx, serverURL)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	m "github.com/metoro-io/mcp-golang"
@@ -93,6 +94,85 @@ func (mc *MCPClient) InitializeAll(ctx context.Context) error {
 	return nil
 }
 
+// UpdateServers implements MCPClientInterface. Servers present in both the
+// current and the new list keep their existing connection and tools, so only
+// added servers pay the initialization cost.
+func (mc *MCPClient) UpdateServers(ctx context.Context, serverURLs []string) error {
+	desired := make(map[string]struct{}, len(serverURLs))
+	for _, serverURL := range serverURLs {
+		desired[serverURL] = struct{}{}
+	}
+
+	mc.mu.Lock()
+	current := make(map[string]struct{}, len(mc.ServerURLs))
+	for _, serverURL := range mc.ServerURLs {
+		current[serverURL] = struct{}{}
+	}
+
+	removed := make([]string, 0)
+	for serverURL := range current {
+		if _, ok := desired[serverURL]; ok {
+			continue
+		}
+		delete(mc.clients, serverURL)
+		delete(mc.serverTools, serverURL)
+		delete(mc.serverStatuses, serverURL)
+		removed = append(removed, serverURL)
+	}
+
+	added := make([]string, 0)
+	for _, serverURL := range serverURLs {
+		if _, ok := current[serverURL]; ok {
+			continue
+		}
+		mc.serverStatuses[serverURL] = ServerStatusUnknown
+		added = append(added, serverURL)
+	}
+
+	mc.ServerURLs = slices.Clone(serverURLs)
+	mc.initialized = true
+	mc.rebuildChatCompletionToolsLocked()
+	mc.mu.Unlock()
+
+	mc.Logger.Info("updating mcp servers",
+		"added", added,
+		"removed", removed,
+		"total_servers", len(serverURLs),
+		"component", "mcp_client")
+
+	var errs []error
+	for _, serverURL := range added {
+		if err := mc.initializeServer(ctx, serverURL); err != nil {
+			mc.Logger.Error("failed to initialize mcp server", err, "server", serverURL, "component", "mcp_client")
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 && mc.Config.MCP.EnableReconnect {
+		// The reconnection goroutine only tracks the servers it was started
+		// with, so restart it to cover every server that is still down.
+		mc.StopBackgroundReconnection()
+		mc.scheduleReconnectionIfEnabled(mc.unavailableServers())
+		return nil
+	}
+
+	return errors.Join(errs...)
+}
+
+// unavailableServers returns the configured servers that are currently unavailable
+func (mc *MCPClient) unavailableServers() []string {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	servers := make([]string, 0)
+	for _, serverURL := range mc.ServerURLs {
+		if mc.serverStatuses[serverURL] == ServerStatusUnavailable {
+			servers = append(servers, serverURL)
+		}
+	}
+	return servers
+}
+
 // scheduleReconnectionIfEnabled is the single guard point for kicking off the
 // background reconnection goroutine.
 func (mc *MCPClient) scheduleReconnectionIfEnabled(failedServers []string) bool {
@@ -356,7 +436,13 @@ func (mc *MCPClient) startBackgroundReconnection(ctx context.Context, failedServ
 			mc.mu.RLock()
 			serversToReconnect := make([]string, 0)
 			for serverURL := range reconnectingServers {
-				if status, exists := mc.serverStatuses[serverURL]; exists && status == ServerStatusUnavailable {
+				status, exists := mc.serverStatuses[serverURL]
+				if !exists {
+					// The server was removed by UpdateServers
+					delete(reconnectingServers, serverURL)
+					continue
+				}
+				if status == ServerStatusUnavailable {
 					serversToReconnect = append(serversToReconnect, serverURL)
 				} else if status == ServerStatusAvailable {
 					delete(reconnectingServers, serverURL)
@@ -18,13 +18,20 @@ func GenerateConfigurationsMD(filePath string, oas string) error {
 	}
 
 	const mdTemplate = `## Configurations
+
+Every setting can be provided as an environment variable or in a YAML or TOML
+config file passed with ` + "`--config`" + `. Environment variables take precedence
+over values from the config file. List settings accept either a comma-separated
+string or a native array, and each provider additionally accepts an
+` + "`extra_headers`" + ` table of header names to values; headers the provider
+sends by default take precedence over it.
 {{- range $index, $sectionMap := .Sections }}
 {{ range $name, $section := $sectionMap }}
 ### {{ $section.Title }}
-| Environment Variable | Default Value | Description |
-|---------------------|---------------|-------------|
+| Environment Variable | Config File Key | Default Value | Description |
+|---------------------|-----------------|---------------|-------------|
 {{- range $setting := $section.Settings }}
-| {{ $setting.Env }} | ` + "`{{ if $setting.Default }}{{ $setting.Default }}{{ else }}\"\"{{ end }}`" + ` | {{ $setting.Description }} |
+| {{ $setting.Env }} | ` + "`{{ $setting.FileKey $name }}`" + ` | ` + "`{{ if $setting.Default }}{{ $setting.Default }}{{ else }}\"\"{{ end }}`" + ` | {{ $setting.Description }} |
 {{- end }}
 {{ end }}
 {{- end }}
@@ -3,6 +3,7 @@ package openapi
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -42,6 +43,24 @@ type Setting struct {
 	Secret      bool   `yaml:"secret,omitempty"`
 }
 
+// FileKey returns the dotted config file key for the setting within section.
+// General settings live at the top level, provider settings are keyed by
+// provider ID and every other setting is nested under its section name.
+func (s Setting) FileKey(section string) string {
+	env := strings.ToLower(s.Env)
+	switch section {
+	case "general":
+		return env
+	case "providers":
+		for _, suffix := range []string{"_api_url", "_api_key"} {
+			if id, ok := strings.CutSuffix(env, suffix); ok {
+				return section + "." + id + "." + strings.TrimPrefix(suffix, "_")
+			}
+		}
+	}
+	return section + "." + strings.TrimPrefix(env, section+"_")
+}
+
 // ExtraHeader can be either string or []string
 type ExtraHeader struct {
 	Values []string
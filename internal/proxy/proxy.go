@@ -11,6 +11,7 @@ import (
 
 	config "github.com/inference-gateway/inference-gateway/config"
 	logger "github.com/inference-gateway/inference-gateway/logger"
+	client "github.com/inference-gateway/inference-gateway/providers/client"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
@@ -215,3 +216,53 @@ func (m *DevResponseModifier) Modify(resp *http.Response) error {
 
 	return nil
 }
+
+// DevClient logs the provider requests and responses of a client like the
+// development modifiers of the proxy, for provider calls that do not go
+// through it
+type DevClient struct {
+	client.Client
+	request  RequestModifier
+	response ResponseModifier
+}
+
+// NewDevClient wraps httpClient with development logging
+func NewDevClient(httpClient client.Client, l logger.Logger, cfg *config.Config) client.Client {
+	return &DevClient{
+		Client:   httpClient,
+		request:  NewDevRequestModifier(l, cfg),
+		response: NewDevResponseModifier(l),
+	}
+}
+
+func (c *DevClient) Do(req *http.Request) (*http.Response, error) {
+	if err := c.request.Modify(req); err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.response.Modify(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *DevClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+func (c *DevClient) Post(url string, bodyType string, body string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", bodyType)
+	return c.Do(req)
+}
@@ -0,0 +1,367 @@
+// Package shaping rewrites the parameters of chat completion requests with
+// per-model rules: defaults for unset fields, forced values, clamped numeric
+// ranges and stripped fields.
+package shaping
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Actions reported in a Change, in the order a rule applies them
+const (
+	ActionDefault = "default"
+	ActionForce   = "force"
+	ActionClamp   = "clamp"
+	ActionStrip   = "strip"
+)
+
+// reserved fields select the model, the conversation and the response format;
+// they are not parameters a rule may rewrite
+var reserved = map[string]bool{"model": true, "messages": true, "stream": true}
+
+// Range bounds a numeric field; either end may be left open
+type Range struct {
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+// Rule is the on-disk shape of a single shaping rule. Model is a pattern
+// matched case-insensitively against the requested model and against the
+// resolved provider/model, where * matches any sequence of characters.
+// Fields are named as in the chat completions request body.
+type Rule struct {
+	Model    string           `yaml:"model"`
+	Defaults map[string]any   `yaml:"defaults"`
+	Force    map[string]any   `yaml:"force"`
+	Clamp    map[string]Range `yaml:"clamp"`
+	Strip    []string         `yaml:"strip"`
+}
+
+// Config is the on-disk shaping file: rules applied in order, every matching
+// rule seeing the changes of the ones before it.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Change is a parameter rewritten by a rule. From and To hold the JSON
+// values; From is empty for a field that was unset and To for a stripped one.
+type Change struct {
+	Field  string
+	Action string
+	From   string
+	To     string
+}
+
+// String renders the change for logs, e.g. "max_tokens=8192->4096 (clamp)"
+func (c Change) String() string {
+	from, to := c.From, c.To
+	if from == "" {
+		from = "unset"
+	}
+	if to == "" {
+		to = "unset"
+	}
+	return fmt.Sprintf("%s=%s->%s (%s)", c.Field, from, to, c.Action)
+}
+
+// rule is the runtime form of a Rule with its values encoded and its field
+// names sorted so changes are applied in a stable order
+type rule struct {
+	pattern  *regexp.Regexp
+	defaults map[string]json.RawMessage
+	force    map[string]json.RawMessage
+	clamp    map[string]Range
+	strip    []string
+}
+
+// Shaper applies shaping rules to chat completion requests
+type Shaper struct {
+	rules []rule
+}
+
+// LoadConfig reads and parses the shaping YAML file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read shaping config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse shaping config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// NewShaper builds a Shaper from parsed rules, validating that every rule has
+// a model pattern and only names request parameters, with values of the
+// parameter's type and numeric ranges for numeric parameters.
+func NewShaper(cfg *Config) (*Shaper, error) {
+	if cfg == nil || len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("shaping enabled but no rules configured")
+	}
+	fields := requestFields()
+	rules := make([]rule, 0, len(cfg.Rules))
+	for i, rc := range cfg.Rules {
+		if rc.Model == "" {
+			return nil, fmt.Errorf("rule %d: model is required", i)
+		}
+		if len(rc.Defaults)+len(rc.Force)+len(rc.Clamp)+len(rc.Strip) == 0 {
+			return nil, fmt.Errorf("rule %d (%s): no defaults, force, clamp or strip configured", i, rc.Model)
+		}
+		r := rule{
+			pattern:  compilePattern(rc.Model),
+			defaults: make(map[string]json.RawMessage, len(rc.Defaults)),
+			force:    make(map[string]json.RawMessage, len(rc.Force)),
+			clamp:    rc.Clamp,
+			strip:    rc.Strip,
+		}
+
+		for _, values := range []struct {
+			action string
+			in     map[string]any
+			out    map[string]json.RawMessage
+		}{
+			{ActionDefault, rc.Defaults, r.defaults},
+			{ActionForce, rc.Force, r.force},
+		} {
+			for field, value := range values.in {
+				if err := checkField(fields, field); err != nil {
+					return nil, fmt.Errorf("rule %d (%s) %s: %w", i, rc.Model, values.action, err)
+				}
+				raw, err := json.Marshal(value)
+				if err != nil {
+					return nil, fmt.Errorf("rule %d (%s) %s: field %q: %w", i, rc.Model, values.action, field, err)
+				}
+				if err := checkValue(field, raw); err != nil {
+					return nil, fmt.Errorf("rule %d (%s) %s: %w", i, rc.Model, values.action, err)
+				}
+				values.out[field] = raw
+			}
+		}
+
+		for field, bounds := range rc.Clamp {
+			if err := checkField(fields, field); err != nil {
+				return nil, fmt.Errorf("rule %d (%s) clamp: %w", i, rc.Model, err)
+			}
+			if !isNumeric(fields[field]) {
+				return nil, fmt.Errorf("rule %d (%s) clamp: field %q is not numeric", i, rc.Model, field)
+			}
+			if bounds.Min == nil && bounds.Max == nil {
+				return nil, fmt.Errorf("rule %d (%s) clamp: field %q needs a min or a max", i, rc.Model, field)
+			}
+			if bounds.Min != nil && bounds.Max != nil && *bounds.Min > *bounds.Max {
+				return nil, fmt.Errorf("rule %d (%s) clamp: field %q has min %v above max %v", i, rc.Model, field, *bounds.Min, *bounds.Max)
+			}
+			for _, bound := range []*float64{bounds.Min, bounds.Max} {
+				if bound == nil {
+					continue
+				}
+				if err := checkValue(field, encodeNumber(*bound)); err != nil {
+					return nil, fmt.Errorf("rule %d (%s) clamp: %w", i, rc.Model, err)
+				}
+			}
+		}
+
+		for _, field := range rc.Strip {
+			if err := checkField(fields, field); err != nil {
+				return nil, fmt.Errorf("rule %d (%s) strip: %w", i, rc.Model, err)
+			}
+			_, defaulted := rc.Defaults[field]
+			_, forced := rc.Force[field]
+			if defaulted || forced {
+				return nil, fmt.Errorf("rule %d (%s) strip: field %q is also set by the rule", i, rc.Model, field)
+			}
+		}
+
+		rules = append(rules, r)
+	}
+	return &Shaper{rules: rules}, nil
+}
+
+// Apply shapes req with the rules matching any of models, typically the model
+// as requested (possibly a routing alias) and the resolved provider/model. It
+// returns the changes made, in order; req is left untouched when no rule
+// changes it.
+func (s *Shaper) Apply(req *types.CreateChatCompletionRequest, models ...string) ([]Change, error) {
+	var matched []*rule
+	for i := range s.rules {
+		for _, model := range models {
+			if s.rules[i].pattern.MatchString(model) {
+				matched = append(matched, &s.rules[i])
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	// The messages are not shaped; leave them out of the round trip
+	params := *req
+	params.Messages = nil
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, r := range matched {
+		changes = r.apply(fields, changes)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if raw, err = json.Marshal(fields); err != nil {
+		return nil, err
+	}
+	var shaped types.CreateChatCompletionRequest
+	if err := json.Unmarshal(raw, &shaped); err != nil {
+		return nil, fmt.Errorf("decode shaped request: %w", err)
+	}
+	shaped.Messages = req.Messages
+	*req = shaped
+	return changes, nil
+}
+
+// apply runs the rule on the request fields, appending its changes
+func (r *rule) apply(fields map[string]json.RawMessage, changes []Change) []Change {
+	for _, field := range slices.Sorted(maps.Keys(r.defaults)) {
+		if isSet(fields[field]) {
+			continue
+		}
+		fields[field] = r.defaults[field]
+		changes = append(changes, Change{Field: field, Action: ActionDefault, To: string(r.defaults[field])})
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(r.force)) {
+		from := fields[field]
+		if isSet(from) && string(from) == string(r.force[field]) {
+			continue
+		}
+		fields[field] = r.force[field]
+		changes = append(changes, Change{Field: field, Action: ActionForce, From: value(from), To: string(r.force[field])})
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(r.clamp)) {
+		from := fields[field]
+		if !isSet(from) {
+			continue
+		}
+		n, err := strconv.ParseFloat(string(from), 64)
+		if err != nil {
+			continue
+		}
+		bounds := r.clamp[field]
+		var to json.RawMessage
+		switch {
+		case bounds.Min != nil && n < *bounds.Min:
+			to = encodeNumber(*bounds.Min)
+		case bounds.Max != nil && n > *bounds.Max:
+			to = encodeNumber(*bounds.Max)
+		default:
+			continue
+		}
+		fields[field] = to
+		changes = append(changes, Change{Field: field, Action: ActionClamp, From: string(from), To: string(to)})
+	}
+
+	for _, field := range r.strip {
+		from := fields[field]
+		if !isSet(from) {
+			continue
+		}
+		delete(fields, field)
+		changes = append(changes, Change{Field: field, Action: ActionStrip, From: string(from)})
+	}
+	return changes
+}
+
+// compilePattern turns a model pattern into an anchored, case-insensitive
+// expression in which * matches any sequence of characters, including the
+// slash between provider and model
+func compilePattern(pattern string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSpace(pattern)), `\*`, `.*`)
+	return regexp.MustCompile(`(?i)^` + quoted + `$`)
+}
+
+// requestFields maps the JSON names of the chat completion request fields to
+// their types
+func requestFields() map[string]reflect.Type {
+	t := reflect.TypeFor[types.CreateChatCompletionRequest]()
+	fields := make(map[string]reflect.Type, t.NumField())
+	for f := range t.Fields() {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = f.Type
+		}
+	}
+	return fields
+}
+
+func checkField(fields map[string]reflect.Type, field string) error {
+	if reserved[field] {
+		return fmt.Errorf("field %q cannot be shaped", field)
+	}
+	if _, ok := fields[field]; !ok {
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// checkValue reports whether raw decodes as the value of field
+func checkValue(field string, raw json.RawMessage) error {
+	body, err := json.Marshal(map[string]json.RawMessage{field: raw})
+	if err != nil {
+		return err
+	}
+	var req types.CreateChatCompletionRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return fmt.Errorf("field %q: invalid value %s", field, raw)
+	}
+	return nil
+}
+
+func isNumeric(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isSet(raw json.RawMessage) bool {
+	return len(raw) > 0 && string(raw) != "null"
+}
+
+func value(raw json.RawMessage) string {
+	if !isSet(raw) {
+		return ""
+	}
+	return string(raw)
+}
+
+func encodeNumber(n float64) json.RawMessage {
+	return json.RawMessage(strconv.FormatFloat(n, 'f', -1, 64))
+}
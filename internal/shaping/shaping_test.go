@@ -0,0 +1,131 @@
+package shaping
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func shaperFor(t *testing.T, rules string) *Shaper {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shaping.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(rules), 0o600))
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	shaper, err := NewShaper(cfg)
+	require.NoError(t, err)
+	return shaper
+}
+
+func chatRequest(t *testing.T, body string) types.CreateChatCompletionRequest {
+	t.Helper()
+	var req types.CreateChatCompletionRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &req))
+	return req
+}
+
+func TestApply(t *testing.T) {
+	shaper := shaperFor(t, `
+rules:
+  - model: openai/gpt-4o*
+    defaults:
+      max_tokens: 4096
+    clamp:
+      max_tokens: {max: 4096}
+      temperature: {min: 0, max: 1}
+  - model: code
+    defaults:
+      temperature: 0.2
+    force:
+      parallel_tool_calls: false
+  - model: groq/*
+    strip: [logit_bias]
+`)
+
+	t.Run("DefaultsUnsetFields", func(t *testing.T) {
+		req := chatRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`)
+		changes, err := shaper.Apply(&req, "openai/gpt-4o", "openai/gpt-4o")
+		require.NoError(t, err)
+		assert.Equal(t, []Change{{Field: "max_tokens", Action: ActionDefault, To: "4096"}}, changes)
+		require.NotNil(t, req.MaxTokens)
+		assert.Equal(t, 4096, *req.MaxTokens)
+		assert.Equal(t, "gpt-4o", req.Model)
+		assert.Equal(t, "Hi", req.Messages[0].TextContent())
+	})
+
+	t.Run("ClampsRanges", func(t *testing.T) {
+		req := chatRequest(t, `{"model":"gpt-4o-mini","messages":[],"max_tokens":8192,"temperature":1.5}`)
+		changes, err := shaper.Apply(&req, "openai/gpt-4o-mini")
+		require.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Field: "max_tokens", Action: ActionClamp, From: "8192", To: "4096"},
+			{Field: "temperature", Action: ActionClamp, From: "1.5", To: "1"},
+		}, changes)
+		assert.Equal(t, 4096, *req.MaxTokens)
+		assert.InDelta(t, 1, *req.Temperature, 0.0001)
+	})
+
+	t.Run("MatchesAlias", func(t *testing.T) {
+		req := chatRequest(t, `{"model":"llama-3.3-70b","messages":[],"temperature":0.7,"parallel_tool_calls":true,"logit_bias":{"50256":-100}}`)
+		changes, err := shaper.Apply(&req, "code", "groq/llama-3.3-70b")
+		require.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Field: "parallel_tool_calls", Action: ActionForce, From: "true", To: "false"},
+			{Field: "logit_bias", Action: ActionStrip, From: `{"50256":-100}`},
+		}, changes, "the temperature is set, so the default does not apply")
+		assert.InDelta(t, 0.7, *req.Temperature, 0.0001)
+		require.NotNil(t, req.ParallelToolCalls)
+		assert.False(t, *req.ParallelToolCalls)
+		assert.Nil(t, req.LogitBias)
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		req := chatRequest(t, `{"model":"claude-sonnet-4-5","messages":[],"max_tokens":8192}`)
+		before := req
+		changes, err := shaper.Apply(&req, "anthropic/claude-sonnet-4-5")
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+		assert.Equal(t, before, req)
+	})
+}
+
+func TestChangeString(t *testing.T) {
+	assert.Equal(t, "max_tokens=8192->4096 (clamp)", Change{Field: "max_tokens", Action: ActionClamp, From: "8192", To: "4096"}.String())
+	assert.Equal(t, "temperature=unset->0.2 (default)", Change{Field: "temperature", Action: ActionDefault, To: "0.2"}.String())
+	assert.Equal(t, `logit_bias={"1":2}->unset (strip)`, Change{Field: "logit_bias", Action: ActionStrip, From: `{"1":2}`}.String())
+}
+
+func TestNewShaperValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+	}{
+		{"NoRules", nil},
+		{"MissingModel", []Rule{{Strip: []string{"seed"}}}},
+		{"EmptyRule", []Rule{{Model: "openai/*"}}},
+		{"UnknownField", []Rule{{Model: "openai/*", Defaults: map[string]any{"temprature": 0.2}}}},
+		{"ReservedField", []Rule{{Model: "openai/*", Force: map[string]any{"model": "gpt-4o"}}}},
+		{"WrongType", []Rule{{Model: "openai/*", Force: map[string]any{"max_tokens": "many"}}}},
+		{"ClampNotNumeric", []Rule{{Model: "openai/*", Clamp: map[string]Range{"user": {Max: ptr(1.0)}}}}},
+		{"ClampNoBounds", []Rule{{Model: "openai/*", Clamp: map[string]Range{"max_tokens": {}}}}},
+		{"ClampInverted", []Rule{{Model: "openai/*", Clamp: map[string]Range{"temperature": {Min: ptr(1.0), Max: ptr(0.5)}}}}},
+		{"ClampFractionalInt", []Rule{{Model: "openai/*", Clamp: map[string]Range{"max_tokens": {Max: ptr(10.5)}}}}},
+		{"StripAndSet", []Rule{{Model: "openai/*", Defaults: map[string]any{"seed": 1}, Strip: []string{"seed"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewShaper(&Config{Rules: tt.rules})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
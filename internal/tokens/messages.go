@@ -0,0 +1,88 @@
+package tokens
+
+import (
+	"encoding/json"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// tokensPerMessagesImage is the size of the largest image the Messages API
+// accepts without downscaling (about width * height / 750 tokens)
+const tokensPerMessagesImage = 1600
+
+// block holds the fields of a Messages API content block that take up tokens;
+// its type selects which ones are set
+type block struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text"`
+	Thinking string          `json:"thinking"`
+	Data     string          `json:"data"`
+	Name     string          `json:"name"`
+	Input    json.RawMessage `json:"input"`
+	Content  json.RawMessage `json:"content"`
+	Source   struct {
+		Type string `json:"type"`
+		Data string `json:"data"`
+	} `json:"source"`
+}
+
+// MessagesRequest counts the input tokens of a Messages API request: the
+// system prompt, the messages and the tool definitions. Images are counted at
+// their maximum size and documents only when given as text.
+func MessagesRequest(model string, req types.MessagesCountTokensRequest) Count {
+	counter := ForModel(model)
+	total := tokensPerReply
+	if req.System != nil {
+		raw, _ := req.System.MarshalJSON()
+		total += counter.blocks(raw)
+	}
+	for _, m := range req.Messages {
+		raw, _ := m.Content.MarshalJSON()
+		total += tokensPerMessage + counter.Text(string(m.Role)) + counter.blocks(raw)
+	}
+	if req.Tools != nil && len(*req.Tools) > 0 {
+		if raw, err := json.Marshal(*req.Tools); err == nil {
+			total += counter.Text(string(raw))
+		}
+	}
+	return Count{
+		Tokens:   total,
+		Method:   counter.Method(),
+		Encoding: counter.Encoding(),
+	}
+}
+
+// blocks counts content given as a string or an array of content blocks
+func (c *Counter) blocks(raw json.RawMessage) int {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return c.Text(text)
+	}
+	var blocks []block
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return 0
+	}
+
+	total := 0
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			total += c.Text(b.Text)
+		case "thinking":
+			total += c.Text(b.Thinking)
+		case "redacted_thinking":
+			total += c.Text(b.Data)
+		case "image":
+			total += tokensPerMessagesImage
+		case "document":
+			if b.Source.Type == "text" {
+				total += c.Text(b.Source.Data)
+			}
+		case "tool_use":
+			total += c.Text(b.Name) + c.Text(string(b.Input))
+		case "tool_result":
+			total += c.blocks(b.Content)
+		}
+	}
+	return total
+}
@@ -0,0 +1,51 @@
+package tokens
+
+import (
+	"encoding/json"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func messagesRequest(t *testing.T, body string) types.MessagesCountTokensRequest {
+	t.Helper()
+	var req types.MessagesCountTokensRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &req))
+	return req
+}
+
+func TestMessagesRequest(t *testing.T) {
+	req := messagesRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"hello world"}]}`)
+	count := MessagesRequest(req.Model, req)
+	// reply priming + message overhead + role + content
+	assert.Equal(t, Count{Tokens: 3 + 3 + 1 + 2, Method: MethodTiktoken, Encoding: EncodingO200kBase}, count)
+
+	blocks := messagesRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":[{"type":"text","text":"hello world"}]}]}`)
+	assert.Equal(t, count.Tokens, MessagesRequest(blocks.Model, blocks).Tokens, "text blocks count like string content")
+
+	system := messagesRequest(t, `{"model":"gpt-4o","system":[{"type":"text","text":"hello world"}],"messages":[{"role":"user","content":"hello world"}]}`)
+	assert.Equal(t, count.Tokens+2, MessagesRequest(system.Model, system).Tokens)
+
+	image := messagesRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":[
+		{"type":"text","text":"hello world"},
+		{"type":"image","source":{"type":"base64","media_type":"image/png","data":"aGVsbG8="}}
+	]}]}`)
+	assert.Equal(t, count.Tokens+tokensPerMessagesImage, MessagesRequest(image.Model, image).Tokens)
+
+	tools := messagesRequest(t, `{"model":"gpt-4o","messages":[
+		{"role":"assistant","content":[{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Paris"}}]},
+		{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"sunny"}]}]}
+	],"tools":[{"name":"get_weather","input_schema":{"type":"object","properties":{"city":{"type":"string"}}}}]}`)
+	withoutTools := messagesRequest(t, `{"model":"gpt-4o","messages":[
+		{"role":"assistant","content":[]},
+		{"role":"user","content":[]}
+	]}`)
+	assert.Greater(t, MessagesRequest(tools.Model, tools).Tokens, MessagesRequest(withoutTools.Model, withoutTools).Tokens+10, "tool use, results and definitions take input tokens")
+
+	heuristic := MessagesRequest("llama3.2", req)
+	assert.Equal(t, MethodHeuristic, heuristic.Method)
+	assert.Equal(t, 3+3+1+3, heuristic.Tokens)
+}
@@ -0,0 +1,213 @@
+// Package tokens estimates how many tokens a prompt takes up in a model's
+// context window, so oversized requests can be rejected before they are sent
+// upstream.
+package tokens
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	tiktoken "github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Counting methods reported with a count
+const (
+	// MethodTiktoken counts with the model's own BPE encoding
+	MethodTiktoken = "tiktoken"
+	// MethodHeuristic estimates from the text length for models whose
+	// tokenizer is not bundled
+	MethodHeuristic = "heuristic"
+)
+
+// BPE encodings bundled with the gateway
+const (
+	EncodingO200kBase  = "o200k_base"
+	EncodingCl100kBase = "cl100k_base"
+)
+
+const (
+	// Chat formatting overhead per OpenAI's published accounting: every
+	// message is wrapped in a few control tokens, and the reply is primed
+	// with the assistant header.
+	tokensPerMessage = 3
+	tokensPerReply   = 3
+
+	// Images are billed by tiles; without decoding the image, count a low
+	// detail image at its flat rate and anything else as a 1024x1024 image.
+	tokensPerLowDetailImage = 85
+	tokensPerImage          = 765
+
+	// bytesPerToken is the heuristic ratio for English text and code
+	bytesPerToken = 4
+)
+
+// encodingPrefixes maps OpenAI model name prefixes to their encoding. Longer
+// prefixes come first so gpt-4o is not matched as gpt-4.
+var encodingPrefixes = []struct {
+	prefix   string
+	encoding string
+}{
+	{"gpt-4o", EncodingO200kBase},
+	{"gpt-4.1", EncodingO200kBase},
+	{"gpt-4.5", EncodingO200kBase},
+	{"gpt-5", EncodingO200kBase},
+	{"chatgpt-4o", EncodingO200kBase},
+	{"o1", EncodingO200kBase},
+	{"o3", EncodingO200kBase},
+	{"o4", EncodingO200kBase},
+	{"gpt-4", EncodingCl100kBase},
+	{"gpt-3.5", EncodingCl100kBase},
+	{"text-embedding-", EncodingCl100kBase},
+}
+
+func init() {
+	// The encodings are embedded in the binary; never download them
+	tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+}
+
+// encoders builds each encoding once; building one parses its vocabulary
+var encoders = map[string]func() (*tiktoken.Tiktoken, error){
+	EncodingO200kBase:  sync.OnceValues(func() (*tiktoken.Tiktoken, error) { return tiktoken.GetEncoding(EncodingO200kBase) }),
+	EncodingCl100kBase: sync.OnceValues(func() (*tiktoken.Tiktoken, error) { return tiktoken.GetEncoding(EncodingCl100kBase) }),
+}
+
+// Count is a token estimate and how it was made
+type Count struct {
+	Tokens int
+	Method string
+	// Encoding is the BPE encoding used, empty for heuristic counts
+	Encoding string
+}
+
+// Counter counts tokens for one model
+type Counter struct {
+	encoding string
+	encoder  *tiktoken.Tiktoken
+}
+
+// ForModel returns the counter for a model name without its provider prefix.
+// OpenAI-family models are counted exactly with their BPE encoding, also when
+// served through an aggregator (e.g. "openai/gpt-4o" on OpenRouter); any
+// other model falls back to a length heuristic.
+func ForModel(model string) *Counter {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, p := range encodingPrefixes {
+		if !strings.HasPrefix(name, p.prefix) {
+			continue
+		}
+		encoder, err := encoders[p.encoding]()
+		if err != nil {
+			break
+		}
+		return &Counter{encoding: p.encoding, encoder: encoder}
+	}
+	return &Counter{}
+}
+
+// Method returns how the counter counts
+func (c *Counter) Method() string {
+	if c.encoder == nil {
+		return MethodHeuristic
+	}
+	return MethodTiktoken
+}
+
+// Encoding returns the BPE encoding of the counter, empty for heuristic counts
+func (c *Counter) Encoding() string {
+	return c.encoding
+}
+
+// Text counts the tokens of plain text
+func (c *Counter) Text(text string) int {
+	if text == "" {
+		return 0
+	}
+	if c.encoder == nil {
+		return (len(text) + bytesPerToken - 1) / bytesPerToken
+	}
+	// Special tokens in user text are encoded as ordinary text
+	return len(c.encoder.Encode(text, nil, nil))
+}
+
+// Messages counts the tokens of a chat conversation and its tool
+// definitions, including the chat formatting overhead
+func (c *Counter) Messages(messages []types.Message, tools *[]types.ChatCompletionTool) int {
+	total := tokensPerReply
+	for i := range messages {
+		m := &messages[i]
+		total += tokensPerMessage + c.Text(string(m.Role))
+		total += c.content(m)
+		if m.ToolCalls != nil {
+			for _, call := range *m.ToolCalls {
+				total += c.Text(call.Function.Name) + c.Text(call.Function.Arguments)
+			}
+		}
+		if m.ToolCallID != nil {
+			total += c.Text(*m.ToolCallID)
+		}
+	}
+	if tools != nil && len(*tools) > 0 {
+		// Tool schemas are rendered into the prompt; their JSON is a close
+		// upper bound of that rendering
+		if raw, err := json.Marshal(*tools); err == nil {
+			total += c.Text(string(raw))
+		}
+	}
+	return total
+}
+
+func (c *Counter) content(m *types.Message) int {
+	if text, err := m.Content.AsMessageContent0(); err == nil {
+		return c.Text(text)
+	}
+	parts, err := m.Content.AsMessageContent1()
+	if err != nil {
+		return 0
+	}
+
+	total := 0
+	for _, part := range parts {
+		if image, err := part.AsImageContentPart(); err == nil && image.Type == "image_url" {
+			if image.ImageURL.Detail != nil && *image.ImageURL.Detail == types.ImageURLDetailLow {
+				total += tokensPerLowDetailImage
+			} else {
+				total += tokensPerImage
+			}
+			continue
+		}
+		if text, err := part.AsTextContentPart(); err == nil {
+			total += c.Text(text.Text)
+		}
+	}
+	return total
+}
+
+// ChatCompletion counts the prompt tokens of a chat completion request.
+// model is the model name without its provider prefix.
+func ChatCompletion(model string, req types.CreateChatCompletionRequest) Count {
+	counter := ForModel(model)
+	return Count{
+		Tokens:   counter.Messages(req.Messages, req.Tools),
+		Method:   counter.Method(),
+		Encoding: counter.Encoding(),
+	}
+}
+
+// CompletionBudget returns the output tokens a chat completion request
+// reserves, or 0 when it sets no limit
+func CompletionBudget(req types.CreateChatCompletionRequest) int {
+	if req.MaxCompletionTokens != nil {
+		return *req.MaxCompletionTokens
+	}
+	if req.MaxTokens != nil {
+		return *req.MaxTokens
+	}
+	return 0
+}
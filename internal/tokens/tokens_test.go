@@ -0,0 +1,96 @@
+package tokens
+
+import (
+	"encoding/json"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func chatRequest(t *testing.T, body string) types.CreateChatCompletionRequest {
+	t.Helper()
+	var req types.CreateChatCompletionRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &req))
+	return req
+}
+
+func TestForModel(t *testing.T) {
+	tests := []struct {
+		model    string
+		method   string
+		encoding string
+	}{
+		{"gpt-4o", MethodTiktoken, EncodingO200kBase},
+		{"gpt-4o-mini-2024-07-18", MethodTiktoken, EncodingO200kBase},
+		{"o3-mini", MethodTiktoken, EncodingO200kBase},
+		{"gpt-4", MethodTiktoken, EncodingCl100kBase},
+		{"GPT-3.5-Turbo", MethodTiktoken, EncodingCl100kBase},
+		{"openai/gpt-4o", MethodTiktoken, EncodingO200kBase},
+		{"llama3.2", MethodHeuristic, ""},
+		{"claude-sonnet-4-5", MethodHeuristic, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			counter := ForModel(tt.model)
+			assert.Equal(t, tt.method, counter.Method())
+			assert.Equal(t, tt.encoding, counter.Encoding())
+		})
+	}
+}
+
+func TestCounterText(t *testing.T) {
+	assert.Equal(t, 2, ForModel("gpt-4o").Text("hello world"))
+	assert.Equal(t, 2, ForModel("gpt-4").Text("hello world"))
+	assert.Equal(t, 0, ForModel("gpt-4o").Text(""))
+	assert.Equal(t, 3, ForModel("llama3.2").Text("hello world"), "heuristic rounds up")
+	assert.Greater(t, ForModel("gpt-4o").Text("<|endoftext|>"), 1, "special tokens are encoded as text")
+}
+
+func TestChatCompletion(t *testing.T) {
+	req := chatRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"hello world"}]}`)
+	count := ChatCompletion(req.Model, req)
+	// reply priming + message overhead + role + content
+	assert.Equal(t, Count{Tokens: 3 + 3 + 1 + 2, Method: MethodTiktoken, Encoding: EncodingO200kBase}, count)
+
+	heuristic := ChatCompletion("llama3.2", req)
+	assert.Equal(t, MethodHeuristic, heuristic.Method)
+	assert.Empty(t, heuristic.Encoding)
+	assert.Equal(t, 3+3+1+3, heuristic.Tokens)
+}
+
+func TestCounterMessages(t *testing.T) {
+	counter := ForModel("gpt-4o")
+	text := chatRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"hello world"}]}`)
+	base := counter.Messages(text.Messages, nil)
+
+	images := chatRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":[
+		{"type":"text","text":"hello world"},
+		{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}},
+		{"type":"image_url","image_url":{"url":"https://example.com/b.png"}}
+	]}]}`)
+	assert.Equal(t, base+tokensPerLowDetailImage+tokensPerImage, counter.Messages(images.Messages, nil))
+
+	tools := chatRequest(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"hello world"}],"tools":[
+		{"type":"function","function":{"name":"get_weather","parameters":{"type":"object","properties":{"city":{"type":"string"}}}}}
+	]}`)
+	assert.Greater(t, counter.Messages(tools.Messages, tools.Tools), base, "tool definitions take prompt tokens")
+
+	calls := chatRequest(t, `{"model":"gpt-4o","messages":[
+		{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},
+		{"role":"tool","content":"sunny","tool_call_id":"call_1"}
+	]}`)
+	withoutCalls := chatRequest(t, `{"model":"gpt-4o","messages":[
+		{"role":"assistant","content":""},
+		{"role":"tool","content":"sunny"}
+	]}`)
+	assert.Greater(t, counter.Messages(calls.Messages, nil), counter.Messages(withoutCalls.Messages, nil), "tool calls take prompt tokens")
+}
+
+func TestCompletionBudget(t *testing.T) {
+	assert.Equal(t, 0, CompletionBudget(chatRequest(t, `{"model":"gpt-4o","messages":[]}`)))
+	assert.Equal(t, 100, CompletionBudget(chatRequest(t, `{"model":"gpt-4o","messages":[],"max_tokens":100}`)))
+	assert.Equal(t, 200, CompletionBudget(chatRequest(t, `{"model":"gpt-4o","messages":[],"max_tokens":100,"max_completion_tokens":200}`)))
+}
@@ -0,0 +1,93 @@
+package truncation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+const summaryInstructions = "You summarize the earlier part of a conversation between a user and an assistant " +
+	"so the assistant can continue it without the original messages. Keep every fact, decision, open task, " +
+	"file name, identifier and tool result the rest of the conversation may depend on. " +
+	"Write the summary in the third person and reply with the summary only."
+
+// ProviderSummarizer writes summaries with a chat completion model of a
+// configured provider. The provider is built from the registry on every call
+// so reloaded credentials take effect.
+type ProviderSummarizer struct {
+	registry  registry.ProviderRegistry
+	client    client.Client
+	provider  types.Provider
+	model     string
+	maxTokens int
+}
+
+// NewProviderSummarizer creates a summarizer using model on provider, writing
+// summaries of at most maxTokens tokens
+func NewProviderSummarizer(providerRegistry registry.ProviderRegistry, httpClient client.Client, provider types.Provider, model string, maxTokens int) *ProviderSummarizer {
+	return &ProviderSummarizer{
+		registry:  providerRegistry,
+		client:    httpClient,
+		provider:  provider,
+		model:     model,
+		maxTokens: maxTokens,
+	}
+}
+
+// Summarize implements Summarizer
+func (s *ProviderSummarizer) Summarize(ctx context.Context, messages []types.Message) (string, error) {
+	provider, err := s.registry.BuildProvider(s.provider, s.client)
+	if err != nil {
+		return "", err
+	}
+
+	var instructions, transcript types.Message
+	instructions.Role = types.System
+	if err := instructions.Content.FromMessageContent0(summaryInstructions); err != nil {
+		return "", err
+	}
+	transcript.Role = types.User
+	if err := transcript.Content.FromMessageContent0(renderTranscript(messages)); err != nil {
+		return "", err
+	}
+
+	maxTokens := s.maxTokens
+	resp, err := provider.ChatCompletions(ctx, types.CreateChatCompletionRequest{
+		Model:     s.model,
+		Messages:  []types.Message{instructions, transcript},
+		MaxTokens: &maxTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("summary request failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("summary response contains no choices")
+	}
+	return resp.Choices[0].Message.TextContent(), nil
+}
+
+// renderTranscript renders messages as plain text for a summary, one block per
+// message; images are left out
+func renderTranscript(messages []types.Message) string {
+	var b strings.Builder
+	for i := range messages {
+		m := &messages[i]
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(string(m.Role))
+		b.WriteString(": ")
+		b.WriteString(m.TextContent())
+		if m.ToolCalls != nil {
+			for _, call := range *m.ToolCalls {
+				fmt.Fprintf(&b, "\n[called %s(%s)]", call.Function.Name, call.Function.Arguments)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,258 @@
+// Package truncation trims chat conversations that do not fit a model's
+// context window, so long-running agents keep working instead of failing once
+// their history outgrows the model.
+package truncation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tokens "github.com/inference-gateway/inference-gateway/internal/tokens"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// Truncation strategies
+const (
+	// StrategyNone leaves the conversation as it is
+	StrategyNone = "none"
+	// StrategyDropOldest drops the oldest messages, keeping system messages
+	StrategyDropOldest = "drop_oldest"
+	// StrategyKeepFirstLast also keeps the first messages of the
+	// conversation, usually the task an agent was given
+	StrategyKeepFirstLast = "keep_first_last"
+	// StrategySummarizeMiddle replaces the messages keep_first_last drops
+	// with a summary of them
+	StrategySummarizeMiddle = "summarize_middle"
+)
+
+// SummaryPrefix starts the system message carrying a summary
+const SummaryPrefix = "Summary of the earlier conversation:\n"
+
+// ErrDoesNotFit is returned when the conversation exceeds the budget even
+// with every droppable message removed
+var ErrDoesNotFit = errors.New("conversation does not fit the context window")
+
+// ParseStrategy returns the strategy named name
+func ParseStrategy(name string) (string, error) {
+	switch strategy := strings.ToLower(strings.TrimSpace(name)); strategy {
+	case StrategyNone, StrategyDropOldest, StrategyKeepFirstLast, StrategySummarizeMiddle:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown truncation strategy %q: expected %s, %s, %s or %s",
+			name, StrategyDropOldest, StrategyKeepFirstLast, StrategySummarizeMiddle, StrategyNone)
+	}
+}
+
+// Summarizer writes a summary of a part of a conversation
+type Summarizer interface {
+	Summarize(ctx context.Context, messages []types.Message) (string, error)
+}
+
+// Options configures a truncation
+type Options struct {
+	Strategy string
+	// KeepFirst is the number of leading non-system messages kept by
+	// keep_first_last and summarize_middle
+	KeepFirst int
+	// Summarizer writes the summaries of summarize_middle; without one the
+	// strategy behaves like keep_first_last
+	Summarizer Summarizer
+	// SummaryMaxTokens is the room reserved for the summary
+	SummaryMaxTokens int
+}
+
+// Result is a truncated conversation
+type Result struct {
+	Messages []types.Message
+	// Strategy is the strategy applied; keep_first_last when summarizing
+	// failed
+	Strategy string
+	// Removed holds the indices of the original messages that were dropped
+	// or summarized, in ascending order
+	Removed []int
+	// Tokens is the prompt size after truncation
+	Tokens int
+	// RemovedTokens is how many prompt tokens the truncation saved
+	RemovedTokens int
+	// SummaryErr is set when summarize_middle fell back to dropping
+	SummaryErr error
+}
+
+// Truncated reports whether any message was removed
+func (r Result) Truncated() bool {
+	return len(r.Removed) > 0
+}
+
+// Truncate removes messages until the conversation and its tools take at most
+// budget tokens as counted by counter. System messages and the last turn are
+// always kept, and an assistant message calling tools is kept or removed
+// together with the tool results answering it, so no tool result is left
+// without its call. A conversation that already fits is returned unchanged.
+func Truncate(ctx context.Context, messages []types.Message, tools *[]types.ChatCompletionTool, counter *tokens.Counter, budget int, opts Options) (Result, error) {
+	base := counter.Messages(nil, tools)
+	costs := make([]int, len(messages))
+	total := base
+	for i := range messages {
+		costs[i] = counter.Messages(messages[i:i+1], nil) - counter.Messages(nil, nil)
+		total += costs[i]
+	}
+
+	result := Result{Messages: messages, Strategy: opts.Strategy, Tokens: total}
+	if total <= budget || opts.Strategy == StrategyNone {
+		return result, nil
+	}
+
+	keepFirst := 0
+	if opts.Strategy != StrategyDropOldest {
+		keepFirst = opts.KeepFirst
+	}
+	target := budget
+	summarize := opts.Strategy == StrategySummarizeMiddle && opts.Summarizer != nil
+	if summarize {
+		target -= opts.SummaryMaxTokens + summaryOverhead(counter)
+	}
+
+	// Drop the oldest droppable turns until the conversation fits
+	removed := make([]bool, len(messages))
+	kept := total
+	for _, turn := range droppableTurns(messages, keepFirst) {
+		if kept <= target {
+			break
+		}
+		for i := turn.start; i < turn.end; i++ {
+			removed[i] = true
+			kept -= costs[i]
+		}
+	}
+	if kept > target {
+		return result, ErrDoesNotFit
+	}
+
+	var dropped []types.Message
+	for i, r := range removed {
+		if r {
+			result.Removed = append(result.Removed, i)
+			dropped = append(dropped, messages[i])
+		}
+	}
+
+	var summary *types.Message
+	if summarize {
+		text, err := opts.Summarizer.Summarize(ctx, dropped)
+		if err == nil {
+			summary, err = summaryMessage(text)
+		}
+		if err != nil {
+			result.SummaryErr = err
+			result.Strategy = StrategyKeepFirstLast
+			summary = nil
+		}
+	}
+
+	truncated := make([]types.Message, 0, len(messages)-len(dropped)+1)
+	for i := range messages {
+		if !removed[i] {
+			truncated = append(truncated, messages[i])
+		} else if summary != nil {
+			// The summary takes the place of the first summarized message
+			truncated = append(truncated, *summary)
+			kept += counter.Messages([]types.Message{*summary}, nil) - counter.Messages(nil, nil)
+			summary = nil
+		}
+	}
+
+	result.Messages = truncated
+	result.Tokens = kept
+	result.RemovedTokens = total - kept
+	return result, nil
+}
+
+// turn is a run of messages that is kept or dropped as a whole
+type turn struct {
+	start, end int
+}
+
+// droppableTurns returns the turns that may be dropped, oldest first: every
+// turn except system messages, the first keepFirst non-system messages and
+// the last turn. An assistant message with tool calls forms one turn with the
+// tool results following it.
+func droppableTurns(messages []types.Message, keepFirst int) []turn {
+	var turns []turn
+	for i := 0; i < len(messages); {
+		end := i + 1
+		if messages[i].Role == types.Assistant && messages[i].ToolCalls != nil && len(*messages[i].ToolCalls) > 0 {
+			for end < len(messages) && messages[end].Role == types.Tool {
+				end++
+			}
+		}
+		turns = append(turns, turn{start: i, end: end})
+		i = end
+	}
+	if len(turns) > 0 {
+		// The last turn is what the model is asked to answer
+		turns = turns[:len(turns)-1]
+	}
+
+	droppable := make([]turn, 0, len(turns))
+	leading := 0
+	for _, t := range turns {
+		if messages[t.start].Role == types.System {
+			continue
+		}
+		if leading < keepFirst {
+			leading += t.end - t.start
+			continue
+		}
+		droppable = append(droppable, t)
+	}
+	return droppable
+}
+
+func summaryMessage(text string) (*types.Message, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("summary is empty")
+	}
+	message := types.Message{Role: types.System}
+	if err := message.Content.FromMessageContent0(SummaryPrefix + text); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// summaryOverhead is the cost of a summary message besides the summary
+func summaryOverhead(counter *tokens.Counter) int {
+	message, err := summaryMessage(".")
+	if err != nil {
+		return 0
+	}
+	return counter.Messages([]types.Message{*message}, nil) - counter.Messages(nil, nil)
+}
+
+// FormatRemoved renders message indices as ranges, e.g. "1-4,7"
+func FormatRemoved(indices []int) string {
+	indices = slices.Clone(indices)
+	slices.Sort(indices)
+
+	var b strings.Builder
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && indices[j+1] == indices[j]+1 {
+			j++
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(indices[i]))
+		if j > i {
+			b.WriteByte('-')
+			b.WriteString(strconv.Itoa(indices[j]))
+		}
+		i = j + 1
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package truncation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	tokens "github.com/inference-gateway/inference-gateway/internal/tokens"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+type fakeSummarizer struct {
+	summary  string
+	err      error
+	received []types.Message
+}
+
+func (f *fakeSummarizer) Summarize(_ context.Context, messages []types.Message) (string, error) {
+	f.received = messages
+	return f.summary, f.err
+}
+
+// conversation returns a system message followed by alternating user and
+// assistant messages of about 100 tokens each, the last one a short question
+func conversation(t *testing.T, turns int) []types.Message {
+	t.Helper()
+	messages := []types.Message{types.NewTextMessage(t, types.System, "You are a helpful assistant.")}
+	for i := range turns {
+		role := types.User
+		if i%2 == 1 {
+			role = types.Assistant
+		}
+		messages = append(messages, types.NewTextMessage(t, role, strings.Repeat("lorem ", 100)))
+	}
+	return append(messages, types.NewTextMessage(t, types.User, "What next?"))
+}
+
+func roles(messages []types.Message) []types.MessageRole {
+	out := make([]types.MessageRole, 0, len(messages))
+	for _, m := range messages {
+		out = append(out, m.Role)
+	}
+	return out
+}
+
+func TestTruncate(t *testing.T) {
+	counter := tokens.ForModel("gpt-4o")
+	messages := conversation(t, 4)
+	total := counter.Messages(messages, nil)
+	// cost is the size of each long message; budget requires dropping two
+	cost := counter.Messages(messages[1:2], nil) - counter.Messages(nil, nil)
+	budget := total - cost - cost/2
+
+	t.Run("FitsUnchanged", func(t *testing.T) {
+		result, err := Truncate(context.Background(), messages, nil, counter, total, Options{Strategy: StrategyDropOldest})
+		require.NoError(t, err)
+		assert.False(t, result.Truncated())
+		assert.Equal(t, messages, result.Messages)
+		assert.Equal(t, total, result.Tokens)
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		result, err := Truncate(context.Background(), messages, nil, counter, budget, Options{Strategy: StrategyDropOldest, KeepFirst: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, result.Removed, "KeepFirst only applies to keep_first_last")
+		assert.Equal(t, []types.MessageRole{types.System, types.User, types.Assistant, types.User}, roles(result.Messages))
+		assert.Equal(t, messages[3], result.Messages[1])
+		assert.Equal(t, counter.Messages(result.Messages, nil), result.Tokens)
+		assert.Equal(t, total-result.Tokens, result.RemovedTokens)
+		assert.LessOrEqual(t, result.Tokens, budget)
+	})
+
+	t.Run("KeepFirstLast", func(t *testing.T) {
+		result, err := Truncate(context.Background(), messages, nil, counter, budget, Options{Strategy: StrategyKeepFirstLast, KeepFirst: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3}, result.Removed)
+		assert.Equal(t, messages[1], result.Messages[1], "the first message is kept")
+		assert.Equal(t, messages[len(messages)-1], result.Messages[len(result.Messages)-1], "the last message is kept")
+	})
+
+	t.Run("SummarizeMiddle", func(t *testing.T) {
+		summarizer := &fakeSummarizer{summary: "The user and the assistant wrote lorem ipsum."}
+		result, err := Truncate(context.Background(), messages, nil, counter, budget, Options{
+			Strategy:         StrategySummarizeMiddle,
+			KeepFirst:        1,
+			Summarizer:       summarizer,
+			SummaryMaxTokens: cost / 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, StrategySummarizeMiddle, result.Strategy)
+		assert.Equal(t, []int{2, 3, 4}, result.Removed, "room is reserved for the summary")
+		assert.Equal(t, messages[2:5], summarizer.received)
+
+		require.Len(t, result.Messages, 4)
+		summary := result.Messages[2]
+		assert.Equal(t, types.System, summary.Role)
+		assert.Equal(t, SummaryPrefix+summarizer.summary, summary.TextContent())
+		assert.Equal(t, counter.Messages(result.Messages, nil), result.Tokens)
+		assert.LessOrEqual(t, result.Tokens, budget)
+	})
+
+	t.Run("SummaryFailureDrops", func(t *testing.T) {
+		summarizer := &fakeSummarizer{err: errors.New("model unavailable")}
+		result, err := Truncate(context.Background(), messages, nil, counter, budget, Options{
+			Strategy:         StrategySummarizeMiddle,
+			KeepFirst:        1,
+			Summarizer:       summarizer,
+			SummaryMaxTokens: cost / 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, StrategyKeepFirstLast, result.Strategy)
+		assert.Error(t, result.SummaryErr)
+		assert.Equal(t, []int{2, 3, 4}, result.Removed)
+		assert.Len(t, result.Messages, 3)
+	})
+
+	t.Run("DoesNotFit", func(t *testing.T) {
+		result, err := Truncate(context.Background(), messages, nil, counter, 10, Options{Strategy: StrategyDropOldest})
+		require.ErrorIs(t, err, ErrDoesNotFit)
+		assert.Equal(t, messages, result.Messages)
+	})
+
+	t.Run("StrategyNone", func(t *testing.T) {
+		result, err := Truncate(context.Background(), messages, nil, counter, 10, Options{Strategy: StrategyNone})
+		require.NoError(t, err)
+		assert.False(t, result.Truncated())
+	})
+}
+
+func TestTruncate_KeepsToolCallsWithTheirResults(t *testing.T) {
+	counter := tokens.ForModel("gpt-4o")
+	calls := []types.ChatCompletionMessageToolCall{{
+		ID:       "call_1",
+		Type:     types.Function,
+		Function: types.ChatCompletionMessageToolCallFunction{Name: "read_file", Arguments: `{"path":"main.go"}`},
+	}}
+	messages := []types.Message{
+		types.NewTextMessage(t, types.User, strings.Repeat("lorem ", 100)),
+		types.NewAssistantMessage(t, "", &calls),
+		types.NewToolMessage(t, "call_1", strings.Repeat("ipsum ", 100)),
+		types.NewTextMessage(t, types.Assistant, "The file is long."),
+		types.NewTextMessage(t, types.User, "Summarize it."),
+	}
+	total := counter.Messages(messages, nil)
+	cost := counter.Messages(messages[:1], nil) - counter.Messages(nil, nil)
+
+	result, err := Truncate(context.Background(), messages, nil, counter, total-cost-1, Options{Strategy: StrategyDropOldest})
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, result.Removed, "the tool result goes with its call")
+	assert.Equal(t, []types.MessageRole{types.Assistant, types.User}, roles(result.Messages))
+}
+
+func TestParseStrategy(t *testing.T) {
+	for _, name := range []string{"none", "drop_oldest", "keep_first_last", " Summarize_Middle "} {
+		_, err := ParseStrategy(name)
+		assert.NoError(t, err, name)
+	}
+	_, err := ParseStrategy("drop_newest")
+	assert.Error(t, err)
+}
+
+func TestFormatRemoved(t *testing.T) {
+	assert.Equal(t, "", FormatRemoved(nil))
+	assert.Equal(t, "3", FormatRemoved([]int{3}))
+	assert.Equal(t, "1-4,7,9-10", FormatRemoved([]int{7, 1, 2, 3, 4, 9, 10}))
+}
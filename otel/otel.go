@@ -55,6 +55,9 @@ type OpenTelemetry interface {
 	RecordRequestDuration(ctx context.Context, source, team, provider, model, errorType string, seconds float64)
 	RecordToolCall(ctx context.Context, source, team, provider, model, toolType, toolName string)
 	RecordGuardrail(ctx context.Context, source, phase, action, path, model string)
+	RecordCacheLookup(ctx context.Context, source, provider, model, result string)
+	RecordRealtimeSession(ctx context.Context, source, team, provider, model, errorType string, seconds float64)
+	RecordRerankUsage(ctx context.Context, source, team, provider, model string, tokens, searchUnits int64)
 
 	// IngestMetrics maps an OTLP push payload onto the gateway's instruments.
 	IngestMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) IngestResult
@@ -77,6 +80,9 @@ type OpenTelemetryImpl struct {
 	executeToolDuration     metric.Float64Histogram // gen_ai.execute_tool.duration (push only)
 	toolCallCounter         metric.Int64Counter     // inference_gateway.tool_calls
 	guardrailCounter        metric.Int64Counter     // inference_gateway.guardrails
+	cacheLookupCounter      metric.Int64Counter     // inference_gateway.cache.lookups
+	realtimeSessionDuration metric.Float64Histogram // inference_gateway.realtime.session.duration
+	rerankSearchUnits       metric.Int64Counter     // inference_gateway.rerank.search_units
 }
 
 // TracesEndpointURL appends the OTLP traces path to a path-less endpoint URL.
@@ -180,7 +186,7 @@ func metricViews() []sdkmetric.View {
 func (o *OpenTelemetryImpl) initInstruments(provider *sdkmetric.MeterProvider) error {
 	o.meter = provider.Meter(config.APPLICATION_NAME)
 
-	var errs [8]error
+	var errs [11]error
 
 	o.tokenUsageHistogram, errs[0] = o.meter.Int64Histogram("gen_ai.client.token.usage",
 		metric.WithDescription("Number of input and output tokens used per operation"),
@@ -214,6 +220,18 @@ func (o *OpenTelemetryImpl) initInstruments(provider *sdkmetric.MeterProvider) e
 		metric.WithDescription("Number of guardrail evaluations"),
 		metric.WithUnit("{evaluation}"))
 
+	o.cacheLookupCounter, errs[8] = o.meter.Int64Counter("inference_gateway.cache.lookups",
+		metric.WithDescription("Number of response cache lookups by result (hit, miss or bypass)"),
+		metric.WithUnit("{lookup}"))
+
+	o.realtimeSessionDuration, errs[9] = o.meter.Float64Histogram("inference_gateway.realtime.session.duration",
+		metric.WithDescription("Duration of relayed Realtime API sessions"),
+		metric.WithUnit("s"))
+
+	o.rerankSearchUnits, errs[10] = o.meter.Int64Counter("inference_gateway.rerank.search_units",
+		metric.WithDescription("Number of search units billed for rerank requests"),
+		metric.WithUnit("{search_unit}"))
+
 	for _, err := range errs {
 		if err != nil {
 			if o.logger != nil {
@@ -283,6 +301,51 @@ func (o *OpenTelemetryImpl) RecordGuardrail(ctx context.Context, source, phase,
 	o.guardrailCounter.Add(ctx, 1, metric.WithAttributes(attributes...))
 }
 
+func (o *OpenTelemetryImpl) RecordCacheLookup(ctx context.Context, source, provider, model, result string) {
+	attributes := []attribute.KeyValue{
+		sourceKey.String(source),
+		teamKey.String(TeamUnknown),
+		semconv.GenAIProviderNameKey.String(provider),
+		semconv.GenAIRequestModel(model),
+		attribute.String("cache.result", result),
+	}
+	o.cacheLookupCounter.Add(ctx, 1, metric.WithAttributes(attributes...))
+}
+
+func (o *OpenTelemetryImpl) RecordRealtimeSession(ctx context.Context, source, team, provider, model, errorType string, seconds float64) {
+	attributes := []attribute.KeyValue{
+		sourceKey.String(source),
+		teamKey.String(cmp.Or(team, TeamUnknown)),
+		semconv.GenAIProviderNameKey.String(provider),
+		semconv.GenAIRequestModel(model),
+	}
+	if errorType != "" {
+		attributes = append(attributes, semconv.ErrorTypeKey.String(errorType))
+	}
+
+	o.realtimeSessionDuration.Record(ctx, seconds, metric.WithAttributes(attributes...))
+}
+
+// RecordRerankUsage records what a rerank request was billed for: its tokens
+// as input token usage and, for providers billing per search, its search units
+func (o *OpenTelemetryImpl) RecordRerankUsage(ctx context.Context, source, team, provider, model string, tokens, searchUnits int64) {
+	attributes := []attribute.KeyValue{
+		sourceKey.String(source),
+		teamKey.String(cmp.Or(team, TeamUnknown)),
+		semconv.GenAIOperationNameKey.String("rerank"),
+		semconv.GenAIProviderNameKey.String(provider),
+		semconv.GenAIRequestModel(model),
+	}
+
+	if tokens > 0 {
+		o.tokenUsageHistogram.Record(ctx, tokens,
+			metric.WithAttributes(append(attributes, semconv.GenAITokenTypeInput)...))
+	}
+	if searchUnits > 0 {
+		o.rerankSearchUnits.Add(ctx, searchUnits, metric.WithAttributes(attributes...))
+	}
+}
+
 func (o *OpenTelemetryImpl) ShutDown(ctx context.Context) error {
 	err := o.meterProvider.Shutdown(ctx)
 	if o.tracerProvider != nil {
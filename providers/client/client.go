@@ -2,10 +2,10 @@ package client
 
 import (
 	"crypto/tls"
-	"io"
 	"net/http"
 	"strings"
 
+	websocket "github.com/gorilla/websocket"
 	otelhttp "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -30,13 +30,12 @@ func SpanNameFormatter() otelhttp.Option {
 }
 
 type ClientImpl struct {
-	scheme   string
-	hostname string
-	port     string
-	client   *http.Client
+	client *http.Client
 }
 
-func NewHTTPClient(cfg *ClientConfig, scheme, hostname, port string) Client {
+// NewHTTPClient creates the instrumented client used for all upstream
+// provider requests. Requests must carry absolute URLs.
+func NewHTTPClient(cfg *ClientConfig) Client {
 	var tlsMinVersion uint16 = tls.VersionTLS12
 	if cfg.ClientTlsMinVersion == "TLS13" {
 		tlsMinVersion = tls.VersionTLS13
@@ -60,36 +59,38 @@ func NewHTTPClient(cfg *ClientConfig, scheme, hostname, port string) Client {
 	}
 
 	return &ClientImpl{
-		scheme:   scheme,
-		hostname: hostname,
-		port:     port,
-		client:   httpClient,
+		client: httpClient,
 	}
 }
 
-func (c *ClientImpl) Do(req *http.Request) (*http.Response, error) {
-	if req.URL.Scheme == "" {
-		req.URL.Scheme = c.scheme
+// NewWebSocketDialer creates the dialer of upstream WebSocket connections,
+// with the TLS settings and timeout of the HTTP client
+func NewWebSocketDialer(cfg *ClientConfig) *websocket.Dialer {
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+		TLSClientConfig:  &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	if cfg == nil {
+		return dialer
+	}
+	if cfg.ClientTlsMinVersion == "TLS13" {
+		dialer.TLSClientConfig.MinVersion = tls.VersionTLS13
 	}
-	if req.URL.Host == "" {
-		req.URL.Host = c.hostname + ":" + c.port
+	if cfg.ClientTimeout > 0 {
+		dialer.HandshakeTimeout = cfg.ClientTimeout
 	}
+	return dialer
+}
 
+func (c *ClientImpl) Do(req *http.Request) (*http.Response, error) {
 	return c.client.Do(req)
 }
 
 func (c *ClientImpl) Get(url string) (*http.Response, error) {
-	fullURL := c.scheme + "://" + c.hostname + ":" + c.port + "/" + strings.TrimPrefix(url, "/")
-	return c.client.Get(fullURL)
+	return c.client.Get(url)
 }
 
 func (c *ClientImpl) Post(url string, bodyType string, body string) (*http.Response, error) {
-	fullURL := c.scheme + "://" + c.hostname + ":" + c.port + "/" + strings.TrimPrefix(url, "/")
-	req, err := http.NewRequest("POST", fullURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", bodyType)
-	req.Body = io.NopCloser(strings.NewReader(body))
-	return c.client.Do(req)
+	return c.client.Post(url, bodyType, strings.NewReader(body))
 }
@@ -26,40 +26,55 @@ const (
 
 // The default endpoints of each provider
 const (
-	AnthropicModelsEndpoint        = "/models"
-	AnthropicChatEndpoint          = "/chat/completions"
-	CloudflareModelsEndpoint       = "/finetunes/public?limit=1000"
-	CloudflareChatEndpoint         = "/v1/chat/completions"
-	CohereModelsEndpoint           = "/v1/models"
-	CohereChatEndpoint             = "/compatibility/v1/chat/completions"
-	DeepseekModelsEndpoint         = "/models"
-	DeepseekChatEndpoint           = "/chat/completions"
-	GoogleModelsEndpoint           = "/models"
-	GoogleChatEndpoint             = "/chat/completions"
-	GroqModelsEndpoint             = "/models"
-	GroqChatEndpoint               = "/chat/completions"
-	LlamacppModelsEndpoint         = "/models"
-	LlamacppChatEndpoint           = "/chat/completions"
-	MinimaxModelsEndpoint          = "/models"
-	MinimaxChatEndpoint            = "/chat/completions"
-	MistralModelsEndpoint          = "/models"
-	MistralChatEndpoint            = "/chat/completions"
-	MoonshotModelsEndpoint         = "/models"
-	MoonshotChatEndpoint           = "/chat/completions"
-	NvidiaModelsEndpoint           = "/models"
-	NvidiaChatEndpoint             = "/chat/completions"
-	OllamaModelsEndpoint           = "/models"
-	OllamaChatEndpoint             = "/chat/completions"
-	OllamaCloudModelsEndpoint      = "/models"
-	OllamaCloudChatEndpoint        = "/chat/completions"
-	OpenaiModelsEndpoint           = "/models"
-	OpenaiChatEndpoint             = "/chat/completions"
-	OpenaiResponsesEndpoint        = "/responses"
-	OpenaiImagesEndpoint           = "/images/generations"
-	OpenaiImagesEditsEndpoint      = "/images/edits"
-	OpenaiImagesVariationsEndpoint = "/images/variations"
-	ZaiModelsEndpoint              = "/models"
-	ZaiChatEndpoint                = "/chat/completions"
+	AnthropicModelsEndpoint            = "/models"
+	AnthropicChatEndpoint              = "/chat/completions"
+	CloudflareModelsEndpoint           = "/finetunes/public?limit=1000"
+	CloudflareChatEndpoint             = "/v1/chat/completions"
+	CohereModelsEndpoint               = "/v1/models"
+	CohereChatEndpoint                 = "/compatibility/v1/chat/completions"
+	CohereRerankEndpoint               = "/v2/rerank"
+	DeepseekModelsEndpoint             = "/models"
+	DeepseekChatEndpoint               = "/chat/completions"
+	DeepseekCompletionsEndpoint        = "/beta/completions"
+	GoogleModelsEndpoint               = "/models"
+	GoogleChatEndpoint                 = "/chat/completions"
+	GroqModelsEndpoint                 = "/models"
+	GroqChatEndpoint                   = "/chat/completions"
+	GroqAudioTranscriptionsEndpoint    = "/audio/transcriptions"
+	GroqAudioTranslationsEndpoint      = "/audio/translations"
+	GroqAudioSpeechEndpoint            = "/audio/speech"
+	LlamacppModelsEndpoint             = "/models"
+	LlamacppChatEndpoint               = "/chat/completions"
+	LlamacppCompletionsEndpoint        = "/completions"
+	LlamacppRerankEndpoint             = "/rerank"
+	MinimaxModelsEndpoint              = "/models"
+	MinimaxChatEndpoint                = "/chat/completions"
+	MistralModelsEndpoint              = "/models"
+	MistralChatEndpoint                = "/chat/completions"
+	MistralAudioTranscriptionsEndpoint = "/audio/transcriptions"
+	MistralModerationsEndpoint         = "/moderations"
+	MoonshotModelsEndpoint             = "/models"
+	MoonshotChatEndpoint               = "/chat/completions"
+	NvidiaModelsEndpoint               = "/models"
+	NvidiaChatEndpoint                 = "/chat/completions"
+	OllamaModelsEndpoint               = "/models"
+	OllamaChatEndpoint                 = "/chat/completions"
+	OllamaCompletionsEndpoint          = "/completions"
+	OllamaCloudModelsEndpoint          = "/models"
+	OllamaCloudChatEndpoint            = "/chat/completions"
+	OpenaiModelsEndpoint               = "/models"
+	OpenaiChatEndpoint                 = "/chat/completions"
+	OpenaiCompletionsEndpoint          = "/completions"
+	OpenaiResponsesEndpoint            = "/responses"
+	OpenaiImagesEndpoint               = "/images/generations"
+	OpenaiImagesEditsEndpoint          = "/images/edits"
+	OpenaiImagesVariationsEndpoint     = "/images/variations"
+	OpenaiAudioTranscriptionsEndpoint  = "/audio/transcriptions"
+	OpenaiAudioTranslationsEndpoint    = "/audio/translations"
+	OpenaiAudioSpeechEndpoint          = "/audio/speech"
+	OpenaiModerationsEndpoint          = "/moderations"
+	ZaiModelsEndpoint                  = "/models"
+	ZaiChatEndpoint                    = "/chat/completions"
 )
 
 // The ID's of each provider
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+)
+
+// ApplyAuth sets the provider's auth credential (header or query param) and
+// extra headers on req. An unrecognized auth type is returned as an error so
+// misconfigured providers fail loudly instead of sending unauthenticated
+// requests upstream.
+//
+// The caller's inbound Authorization header is always removed first so it is
+// never forwarded to the upstream provider: the client authenticates to the
+// gateway, but only the provider's own credential must leave the gateway.
+// Bearer providers overwrite the header below; the others (x-api-key, query
+// key, none) authenticate elsewhere, so without this removal the caller's
+// bearer/OIDC token would leak to third-party providers.
+func ApplyAuth(req *http.Request, provider IProvider) error {
+	req.Header.Del("Authorization")
+
+	token := provider.GetToken()
+	switch provider.GetAuthType() {
+	case constants.AuthTypeBearer:
+		req.Header.Set("Authorization", "Bearer "+token)
+	case constants.AuthTypeXheader:
+		req.Header.Set("x-api-key", token)
+	case constants.AuthTypeQuery:
+		query := req.URL.Query()
+		query.Set("key", token)
+		req.URL.RawQuery = query.Encode()
+	case constants.AuthTypeNone:
+		// Do Nothing
+	default:
+		return fmt.Errorf("unsupported auth type %q", provider.GetAuthType())
+	}
+
+	for key, values := range provider.GetExtraHeaders() {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return nil
+}
+
+// BuildURL joins path and rawQuery onto the provider's base URL without
+// duplicating slashes, keeping any path prefix of the base URL (e.g. /v1).
+func BuildURL(provider IProvider, path, rawQuery string) (*url.URL, error) {
+	base, err := url.Parse(provider.GetURL())
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("provider url %q has no scheme or host", provider.GetURL())
+	}
+
+	return &url.URL{
+		Scheme:   base.Scheme,
+		Host:     base.Host,
+		Path:     strings.TrimSuffix(base.Path, "/") + "/" + strings.TrimPrefix(path, "/"),
+		RawQuery: rawQuery,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+)
+
+// TestApplyAuth_StripsCallerAuthorization verifies the caller's inbound
+// Authorization header never leaks to the upstream provider: bearer providers
+// overwrite it with their own token, and every other auth type removes it while
+// applying the provider credential elsewhere.
+func TestApplyAuth_StripsCallerAuthorization(t *testing.T) {
+	cases := []struct {
+		name         string
+		authType     string
+		wantAuth     string
+		wantAPIKey   string
+		wantQueryKey string
+	}{
+		{"bearer overwrites caller token", constants.AuthTypeBearer, "Bearer provider-secret", "", ""},
+		{"xheader strips caller token", constants.AuthTypeXheader, "", "provider-secret", ""},
+		{"query strips caller token", constants.AuthTypeQuery, "", "", "provider-secret"},
+		{"none strips caller token", constants.AuthTypeNone, "", "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/x", nil)
+			req.Header.Set("Authorization", "Bearer caller-oidc-token")
+			provider := &ProviderImpl{Token: "provider-secret", AuthType: tc.authType}
+
+			if err := ApplyAuth(req, provider); err != nil {
+				t.Fatalf("ApplyAuth() error = %v", err)
+			}
+
+			if got := req.Header.Get("Authorization"); got != tc.wantAuth {
+				t.Errorf("Authorization = %q, want %q: caller Authorization must not leak upstream", got, tc.wantAuth)
+			}
+			if got := req.Header.Get("x-api-key"); got != tc.wantAPIKey {
+				t.Errorf("x-api-key = %q, want %q", got, tc.wantAPIKey)
+			}
+			if got := req.URL.Query().Get("key"); got != tc.wantQueryKey {
+				t.Errorf("key query = %q, want %q", got, tc.wantQueryKey)
+			}
+		})
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	cases := []struct {
+		name     string
+		base     string
+		path     string
+		query    string
+		want     string
+		wantFail bool
+	}{
+		{"keeps base path prefix", "https://api.openai.com/v1", "/chat/completions", "", "https://api.openai.com/v1/chat/completions", false},
+		{"trims duplicate slashes", "http://localhost:11434/v1/", "/models", "", "http://localhost:11434/v1/models", false},
+		{"appends query", "https://example.com", "models", "a=b", "https://example.com/models?a=b", false},
+		{"rejects relative base", "/proxy/openai", "/models", "", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := BuildURL(&ProviderImpl{URL: tc.base}, tc.path, tc.query)
+			if tc.wantFail {
+				if err == nil {
+					t.Fatalf("BuildURL() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildURL() error = %v", err)
+			}
+			if got.String() != tc.want {
+				t.Errorf("BuildURL() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
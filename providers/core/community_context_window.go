@@ -33,6 +33,18 @@ var communityContextWindows = sync.OnceValue(func() map[string]communityContextW
 	return table
 })
 
+// CommunityContextWindow returns the context window of a model from the
+// community table, or false when the model is not listed
+func CommunityContextWindow(provider types.Provider, model string) (int, bool) {
+	table := communityContextWindows()
+	for _, key := range communityLookupKeys(string(provider) + "/" + model) {
+		if entry, ok := table[key]; ok && entry.Context > 0 && entry.Context <= math.MaxInt {
+			return int(entry.Context), true
+		}
+	}
+	return 0, false
+}
+
 // applyCommunityContextWindows fills ContextWindow from the community table
 // for models the provider listing did not resolve, so provider-published
 // windows always win; a runtime lookup later still overrides both. Models
@@ -0,0 +1,302 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode classifies the failures of upstream providers. Clients receive it
+// as the error code, whichever the provider.
+type ErrorCode string
+
+const (
+	ErrorCodeRateLimit      ErrorCode = "rate_limit_exceeded"
+	ErrorCodeContextLength  ErrorCode = "context_length_exceeded"
+	ErrorCodeAuthentication ErrorCode = "authentication_failed"
+	ErrorCodeContentFilter  ErrorCode = "content_filter"
+	ErrorCodeOverloaded     ErrorCode = "overloaded"
+	ErrorCodeTimeout        ErrorCode = "timeout"
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+	ErrorCodeUpstream       ErrorCode = "upstream_error"
+	// ErrorCodeProviderNotConfigured is a request the gateway cannot send,
+	// as the provider it is for is unknown or misconfigured
+	ErrorCodeProviderNotConfigured ErrorCode = "provider_not_configured"
+)
+
+// UpstreamRequestIDHeader is the response header carrying the ID the
+// provider gave to the failed request
+const UpstreamRequestIDHeader = "X-Upstream-Request-Id"
+
+// requestIDHeaders are the headers providers return their request IDs in
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "Cf-Ray"}
+
+// UpstreamError is an upstream failure as reported to clients
+type UpstreamError struct {
+	Code ErrorCode
+	// StatusCode is the HTTP status of the response to the client: the
+	// status of the provider, or 504 and 502 when it timed out or could not
+	// be reached
+	StatusCode int
+	// Message is the message of the provider, taken out of its error
+	// envelope
+	Message string
+	// RetryAfter is the Retry-After header of the provider, if any
+	RetryAfter string
+	// RequestID is the ID the provider gave to the request, if any
+	RequestID string
+}
+
+func (e *UpstreamError) Error() string {
+	return e.Message
+}
+
+// ClassifyError maps an error returned by a provider to an UpstreamError.
+// Errors that are neither provider responses nor timeouts get a generic
+// message, as they can carry gateway internals; callers log the error.
+func ClassifyError(err error) *UpstreamError {
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		message, hint := errorDetails([]byte(httpErr.Message))
+		return &UpstreamError{
+			Code:       classify(httpErr.StatusCode, hint+" "+message),
+			StatusCode: httpErr.StatusCode,
+			Message:    message,
+			RetryAfter: httpErr.RetryAfter,
+			RequestID:  httpErr.RequestID,
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &UpstreamError{Code: ErrorCodeTimeout, StatusCode: http.StatusGatewayTimeout, Message: "Request timed out"}
+	}
+	return &UpstreamError{Code: ErrorCodeUpstream, StatusCode: http.StatusBadGateway, Message: "Upstream request failed"}
+}
+
+// streamError maps the error payload of a server-sent event to an
+// UpstreamError; the status is unknown once the stream has started
+func streamError(payload []byte) *UpstreamError {
+	message, hint := errorDetails(payload)
+	code := classify(0, hint+" "+message)
+	return &UpstreamError{Code: code, StatusCode: code.status(), Message: message}
+}
+
+// errorDetails extracts the message of an upstream error body, along with the
+// type and code the provider gave it. Bodies that are not an error envelope
+// are the message; Google wraps its envelope in an array.
+func errorDetails(body []byte) (message, hint string) {
+	var wrapped []json.RawMessage
+	if json.Unmarshal(body, &wrapped) == nil && len(wrapped) > 0 {
+		body = wrapped[0]
+	}
+
+	var envelope struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+		Detail  string          `json:"detail"`
+		Type    string          `json:"type"`
+	}
+	if json.Unmarshal(body, &envelope) != nil {
+		return strings.TrimSpace(string(body)), ""
+	}
+
+	var object struct {
+		Message string          `json:"message"`
+		Type    string          `json:"type"`
+		Code    json.RawMessage `json:"code"`
+		Status  json.RawMessage `json:"status"`
+	}
+	var text string
+	switch {
+	case json.Unmarshal(envelope.Error, &text) == nil && text != "":
+		return text, envelope.Type
+	case json.Unmarshal(envelope.Error, &object) == nil && object.Message != "":
+		return object.Message, strings.Join([]string{object.Type, string(object.Code), string(object.Status)}, " ")
+	case envelope.Message != "":
+		return envelope.Message, envelope.Type
+	case envelope.Detail != "":
+		return envelope.Detail, envelope.Type
+	}
+	return strings.TrimSpace(string(body)), ""
+}
+
+// classify picks the code of an upstream failure from its status, when known,
+// and the wording of the provider
+func classify(status int, text string) ErrorCode {
+	text = strings.ToLower(text)
+	contains := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(text, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorCodeRateLimit
+	case contains("context_length_exceeded", "context length", "context window", "prompt is too long", "maximum context", "too many tokens"):
+		return ErrorCodeContextLength
+	case contains("content_filter", "content_policy", "content policy", "content management policy"):
+		return ErrorCodeContentFilter
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || contains("authentication", "invalid_api_key", "invalid api key", "permission_error"):
+		return ErrorCodeAuthentication
+	case status == http.StatusServiceUnavailable || status == 529 || contains("overloaded"):
+		return ErrorCodeOverloaded
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout || contains("timeout", "timed out"):
+		return ErrorCodeTimeout
+	case contains("rate_limit", "rate limit", "too many requests"):
+		return ErrorCodeRateLimit
+	case status == 0 || status >= http.StatusInternalServerError:
+		return ErrorCodeUpstream
+	}
+	return ErrorCodeInvalidRequest
+}
+
+// status is the HTTP status matching the code, for failures without one
+func (c ErrorCode) status() int {
+	switch c {
+	case ErrorCodeRateLimit:
+		return http.StatusTooManyRequests
+	case ErrorCodeContextLength, ErrorCodeContentFilter, ErrorCodeInvalidRequest, ErrorCodeProviderNotConfigured:
+		return http.StatusBadRequest
+	case ErrorCodeAuthentication:
+		return http.StatusUnauthorized
+	case ErrorCodeOverloaded:
+		return http.StatusServiceUnavailable
+	case ErrorCodeTimeout:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// openAIType is the OpenAI error type of the code
+func (c ErrorCode) openAIType() string {
+	switch c {
+	case ErrorCodeRateLimit:
+		return "rate_limit_error"
+	case ErrorCodeContextLength, ErrorCodeContentFilter, ErrorCodeInvalidRequest, ErrorCodeProviderNotConfigured:
+		return "invalid_request_error"
+	case ErrorCodeAuthentication:
+		return "authentication_error"
+	case ErrorCodeTimeout:
+		return "timeout_error"
+	}
+	return "server_error"
+}
+
+// anthropicType is the Anthropic error type of the code
+func (c ErrorCode) anthropicType(status int) string {
+	switch c {
+	case ErrorCodeRateLimit:
+		return "rate_limit_error"
+	case ErrorCodeContextLength, ErrorCodeContentFilter, ErrorCodeInvalidRequest, ErrorCodeProviderNotConfigured:
+		return "invalid_request_error"
+	case ErrorCodeAuthentication:
+		if status == http.StatusForbidden {
+			return "permission_error"
+		}
+		return "authentication_error"
+	case ErrorCodeOverloaded:
+		return "overloaded_error"
+	case ErrorCodeTimeout:
+		return "timeout_error"
+	}
+	return "api_error"
+}
+
+// OpenAIErrorBody is the error envelope of the OpenAI API
+type OpenAIErrorBody struct {
+	Error OpenAIError `json:"error"`
+}
+
+// OpenAIError is the error object of the OpenAI API
+type OpenAIError struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"`
+	Code    string  `json:"code"`
+	Param   *string `json:"param"`
+}
+
+// OpenAI renders the error in the OpenAI envelope
+func (e *UpstreamError) OpenAI() OpenAIErrorBody {
+	return OpenAIErrorBody{Error: OpenAIError{Message: e.Message, Type: e.Code.openAIType(), Code: string(e.Code)}}
+}
+
+// AnthropicErrorBody is the error envelope of the Anthropic API
+type AnthropicErrorBody struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Anthropic renders the error in the Anthropic envelope
+func (e *UpstreamError) Anthropic() AnthropicErrorBody {
+	body := AnthropicErrorBody{Type: "error"}
+	body.Error.Type = e.Code.anthropicType(e.StatusCode)
+	body.Error.Message = e.Message
+	return body
+}
+
+// ResponsesErrorEvent is the error event of an OpenAI Responses API stream
+type ResponsesErrorEvent struct {
+	Type    string  `json:"type"`
+	Code    string  `json:"code"`
+	Message string  `json:"message"`
+	Param   *string `json:"param"`
+}
+
+// ResponsesEvent renders the error as a Responses API stream error event
+func (e *UpstreamError) ResponsesEvent() ResponsesErrorEvent {
+	return ResponsesErrorEvent{Type: "error", Code: string(e.Code), Message: e.Message}
+}
+
+// Headers are the response headers of the error: Retry-After and the
+// request ID of the provider, when known
+func (e *UpstreamError) Headers() map[string]string {
+	headers := map[string]string{}
+	if e.RetryAfter != "" {
+		headers["Retry-After"] = e.RetryAfter
+	}
+	if e.RequestID != "" {
+		headers[UpstreamRequestIDHeader] = e.RequestID
+	}
+	return headers
+}
+
+// UpstreamHeaders picks the headers of a provider response worth relaying
+// along an error: Retry-After and the request ID
+func UpstreamHeaders(header http.Header) map[string]string {
+	err := UpstreamError{RetryAfter: header.Get("Retry-After"), RequestID: requestID(header)}
+	return err.Headers()
+}
+
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// errorEvent renders an error as the data of an OpenAI server-sent event
+func errorEvent(err error) []byte {
+	data, marshalErr := json.Marshal(ClassifyError(err).OpenAI())
+	if marshalErr != nil {
+		return []byte(`{"error":{"message":"Upstream request failed"}}`)
+	}
+	return data
+}
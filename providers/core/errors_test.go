@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    ErrorCode
+		status  int
+		message string
+	}{
+		{
+			name:    "OpenAI rate limit",
+			err:     &HTTPError{StatusCode: http.StatusTooManyRequests, Message: `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`},
+			code:    ErrorCodeRateLimit,
+			status:  http.StatusTooManyRequests,
+			message: "Rate limit reached",
+		},
+		{
+			name:    "OpenAI context length",
+			err:     &HTTPError{StatusCode: http.StatusBadRequest, Message: `{"error":{"message":"This model's maximum context length is 8192 tokens","type":"invalid_request_error","code":"context_length_exceeded"}}`},
+			code:    ErrorCodeContextLength,
+			status:  http.StatusBadRequest,
+			message: "This model's maximum context length is 8192 tokens",
+		},
+		{
+			name:    "Anthropic prompt too long",
+			err:     &HTTPError{StatusCode: http.StatusBadRequest, Message: `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 210000 tokens > 200000 maximum"}}`},
+			code:    ErrorCodeContextLength,
+			status:  http.StatusBadRequest,
+			message: "prompt is too long: 210000 tokens > 200000 maximum",
+		},
+		{
+			name:    "Anthropic overloaded",
+			err:     &HTTPError{StatusCode: 529, Message: `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`},
+			code:    ErrorCodeOverloaded,
+			status:  529,
+			message: "Overloaded",
+		},
+		{
+			name:    "Azure content filter",
+			err:     &HTTPError{StatusCode: http.StatusBadRequest, Message: `{"error":{"message":"The response was filtered","code":"content_filter","status":400}}`},
+			code:    ErrorCodeContentFilter,
+			status:  http.StatusBadRequest,
+			message: "The response was filtered",
+		},
+		{
+			name:    "Google authentication",
+			err:     &HTTPError{StatusCode: http.StatusForbidden, Message: `[{"error":{"code":403,"message":"API key not valid","status":"PERMISSION_DENIED"}}]`},
+			code:    ErrorCodeAuthentication,
+			status:  http.StatusForbidden,
+			message: "API key not valid",
+		},
+		{
+			name:    "Ollama string error",
+			err:     &HTTPError{StatusCode: http.StatusNotFound, Message: `{"error":"model \"llama9\" not found"}`},
+			code:    ErrorCodeInvalidRequest,
+			status:  http.StatusNotFound,
+			message: `model "llama9" not found`,
+		},
+		{
+			name:    "Plain text gateway timeout",
+			err:     &HTTPError{StatusCode: http.StatusGatewayTimeout, Message: "upstream request timeout\n"},
+			code:    ErrorCodeTimeout,
+			status:  http.StatusGatewayTimeout,
+			message: "upstream request timeout",
+		},
+		{
+			name:    "Server error",
+			err:     &HTTPError{StatusCode: http.StatusInternalServerError, Message: `{"detail":"Internal Server Error"}`},
+			code:    ErrorCodeUpstream,
+			status:  http.StatusInternalServerError,
+			message: "Internal Server Error",
+		},
+		{
+			name:    "Deadline exceeded",
+			err:     fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			code:    ErrorCodeTimeout,
+			status:  http.StatusGatewayTimeout,
+			message: "Request timed out",
+		},
+		{
+			name:    "Transport failure",
+			err:     errors.New("connection refused"),
+			code:    ErrorCodeUpstream,
+			status:  http.StatusBadGateway,
+			message: "Upstream request failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstreamErr := ClassifyError(tt.err)
+			assert.Equal(t, tt.code, upstreamErr.Code)
+			assert.Equal(t, tt.status, upstreamErr.StatusCode)
+			assert.Equal(t, tt.message, upstreamErr.Message)
+		})
+	}
+}
+
+func TestUpstreamErrorEnvelopes(t *testing.T) {
+	upstreamErr := ClassifyError(&HTTPError{
+		StatusCode: http.StatusForbidden,
+		Message:    `{"error":{"message":"Project does not have access"}}`,
+		RetryAfter: "30",
+		RequestID:  "req_1",
+	})
+
+	assert.Equal(t, map[string]string{"Retry-After": "30", UpstreamRequestIDHeader: "req_1"}, upstreamErr.Headers())
+	assert.Equal(t, OpenAIError{Message: "Project does not have access", Type: "authentication_error", Code: "authentication_failed"}, upstreamErr.OpenAI().Error)
+	assert.Equal(t, "permission_error", upstreamErr.Anthropic().Error.Type)
+	assert.Equal(t, ResponsesErrorEvent{Type: "error", Code: "authentication_failed", Message: "Project does not have access"}, upstreamErr.ResponsesEvent())
+}
+
+func TestStreamErrorClassification(t *testing.T) {
+	events, err := parseStreamData([]byte(`{"error":{"message":"Request too large for gpt-4o: maximum context length exceeded","type":"invalid_request_error"}}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	var upstreamErr *UpstreamError
+	require.ErrorAs(t, events[0].Err, &upstreamErr)
+	assert.Equal(t, ErrorCodeContextLength, upstreamErr.Code)
+	assert.Equal(t, http.StatusBadRequest, upstreamErr.StatusCode)
+}
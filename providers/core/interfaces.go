@@ -20,5 +20,5 @@ type IProvider interface {
 	// Fetchers
 	ListModels(ctx context.Context) (types.ListModelsResponse, error)
 	ChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (types.CreateChatCompletionResponse, error)
-	StreamChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (<-chan []byte, error)
+	StreamChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (<-chan StreamEvent, error)
 }
@@ -16,7 +16,6 @@ import (
 
 	l "github.com/inference-gateway/inference-gateway/logger"
 	client "github.com/inference-gateway/inference-gateway/providers/client"
-	constants "github.com/inference-gateway/inference-gateway/providers/constants"
 	transformers "github.com/inference-gateway/inference-gateway/providers/transformers"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
@@ -25,6 +24,10 @@ import (
 type HTTPError struct {
 	StatusCode int
 	Message    string
+	// RetryAfter is the Retry-After header of the response, if any
+	RetryAfter string
+	// RequestID is the ID the provider gave to the request, if any
+	RequestID string
 }
 
 func (e *HTTPError) Error() string {
@@ -80,23 +83,34 @@ func (p *ProviderImpl) EndpointChat() string {
 }
 
 // Helper functions for common operations
-func (p *ProviderImpl) buildProviderURL() string {
-	return "/proxy/" + string(*p.GetID()) + p.EndpointChat()
+func (p *ProviderImpl) buildProviderURL(endpoint string) (string, error) {
+	u, err := BuildURL(p, endpoint, "")
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
 }
 
 func (p *ProviderImpl) prepareStreamingRequest(clientReq types.CreateChatCompletionRequest) types.CreateChatCompletionRequest {
-	// Enforce usage tracking for streaming completions
+	// Enforce usage tracking for streaming completions; providers rejecting
+	// stream_options drop it in normalizeRequest
 	clientReq.StreamOptions = &types.ChatCompletionStreamOptions{
 		IncludeUsage: true,
 	}
 
-	if *p.GetID() == constants.CohereID || *p.GetID() == constants.MistralID {
-		clientReq.StreamOptions = nil
-	}
-
 	return clientReq
 }
 
+// encodeChatRequest encodes a chat completion request in the format of the
+// provider
+func (p *ProviderImpl) encodeChatRequest(clientReq types.CreateChatCompletionRequest) ([]byte, error) {
+	body, err := json.Marshal(clientReq)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeRequest(*p.GetID(), clientReq.Model, body)
+}
+
 func (p *ProviderImpl) createHTTPRequest(ctx context.Context, url string, body []byte) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
@@ -108,8 +122,8 @@ func (p *ProviderImpl) createHTTPRequest(ctx context.Context, url string, body [
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
 
-	if authToken, ok := ctx.Value(types.AuthTokenContextKey).(string); ok && authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+authToken)
+	if err := ApplyAuth(req, p); err != nil {
+		return nil, err
 	}
 
 	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
@@ -128,6 +142,8 @@ func (p *ProviderImpl) handleHTTPError(response *http.Response, operation string
 		return &HTTPError{
 			StatusCode: response.StatusCode,
 			Message:    fmt.Sprintf("failed to read response body (status %d)", response.StatusCode),
+			RetryAfter: response.Header.Get("Retry-After"),
+			RequestID:  requestID(response.Header),
 		}
 	}
 
@@ -135,6 +151,8 @@ func (p *ProviderImpl) handleHTTPError(response *http.Response, operation string
 	err := &HTTPError{
 		StatusCode: response.StatusCode,
 		Message:    errorMsg,
+		RetryAfter: response.Header.Get("Retry-After"),
+		RequestID:  requestID(response.Header),
 	}
 	p.Logger.Error("non-200 status code", err, "provider", p.GetName(), "statusCode", response.StatusCode, "operation", operation)
 	return err
@@ -142,16 +160,22 @@ func (p *ProviderImpl) handleHTTPError(response *http.Response, operation string
 
 // ListModels fetches the list of models available from the provider and returns them in OpenAI compatible format
 func (p *ProviderImpl) ListModels(ctx context.Context) (types.ListModelsResponse, error) {
-	url := "/proxy/" + string(*p.GetID()) + p.EndpointModels()
+	url, err := p.buildProviderURL(p.EndpointModels())
+	if err != nil {
+		p.Logger.Error("Failed to build provider url", err, "provider", p.GetName())
+		return types.ListModelsResponse{}, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		p.Logger.Error("Failed to create request", err, "provider", p.GetName(), "url", url)
 		return types.ListModelsResponse{}, err
 	}
+	req.Header.Set("Accept", "application/json")
 
-	if authToken, ok := ctx.Value(types.AuthTokenContextKey).(string); ok && authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+authToken)
+	if err := ApplyAuth(req, p); err != nil {
+		p.Logger.Error("Failed to apply provider auth", err, "provider", p.GetName())
+		return types.ListModelsResponse{}, err
 	}
 
 	otelapi.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
@@ -192,11 +216,16 @@ func (p *ProviderImpl) ListModels(ctx context.Context) (types.ListModelsResponse
 	return resp, nil
 }
 
-// ChatCompletions generates chat completions from the provider
+// ChatCompletions generates chat completions from the provider. A provider
+// answering with a stream has it aggregated into a single completion.
 func (p *ProviderImpl) ChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (types.CreateChatCompletionResponse, error) {
-	url := p.buildProviderURL()
+	url, err := p.buildProviderURL(p.EndpointChat())
+	if err != nil {
+		p.Logger.Error("Failed to build provider url", err, "provider", p.GetName())
+		return types.CreateChatCompletionResponse{}, err
+	}
 
-	reqBody, err := json.Marshal(clientReq)
+	reqBody, err := p.encodeChatRequest(clientReq)
 	if err != nil {
 		p.Logger.Error("Failed to marshal request", err, "provider", p.GetName())
 		return types.CreateChatCompletionResponse{}, err
@@ -219,6 +248,16 @@ func (p *ProviderImpl) ChatCompletions(ctx context.Context, clientReq types.Crea
 		return types.CreateChatCompletionResponse{}, err
 	}
 
+	if isEventStream(response) {
+		p.Logger.Debug("provider streamed a non-streaming completion, aggregating it", "provider", p.GetName())
+		resp, err := aggregateStream(readStream(ctx, response.Body, p.Logger, p.GetName()))
+		if err != nil {
+			p.Logger.Error("Failed to aggregate streamed response", err, "provider", p.GetName())
+			return types.CreateChatCompletionResponse{}, err
+		}
+		return resp, nil
+	}
+
 	var resp types.CreateChatCompletionResponse
 	if err := json.NewDecoder(response.Body).Decode(&resp); err != nil {
 		p.Logger.Error("Failed to unmarshal response", err, "provider", p.GetName())
@@ -228,15 +267,21 @@ func (p *ProviderImpl) ChatCompletions(ctx context.Context, clientReq types.Crea
 	return resp, nil
 }
 
-// StreamChatCompletions generates chat completions from the provider using streaming
-func (p *ProviderImpl) StreamChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (<-chan []byte, error) {
-	url := p.buildProviderURL()
+// StreamChatCompletions generates chat completions from the provider using
+// streaming. The events are normalized to the OpenAI format, see readStream;
+// a provider answering with a single completion has its stream synthesized.
+func (p *ProviderImpl) StreamChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (<-chan StreamEvent, error) {
+	url, err := p.buildProviderURL(p.EndpointChat())
+	if err != nil {
+		p.Logger.Error("failed to build provider url", err, "provider", p.GetName())
+		return nil, err
+	}
 
 	streamReq := p.prepareStreamingRequest(clientReq)
 
 	p.Logger.Debug("streaming chat completions", "provider", p.GetName(), "url", url, "request", streamReq)
 
-	reqBody, err := json.Marshal(streamReq)
+	reqBody, err := p.encodeChatRequest(streamReq)
 	if err != nil {
 		p.Logger.Error("failed to marshal request", err, "provider", p.GetName())
 		return nil, err
@@ -259,41 +304,29 @@ func (p *ProviderImpl) StreamChatCompletions(ctx context.Context, clientReq type
 		return nil, err
 	}
 
-	stream := make(chan []byte, 100)
-	go func() {
-		defer response.Body.Close()
-		defer close(stream)
-
-		reader := bufio.NewReaderSize(response.Body, 4096)
-
-		for {
-			select {
-			case <-ctx.Done():
-				p.Logger.Debug("stream cancelled due to context", "provider", p.GetName())
-				return
-			default:
+	body := io.ReadCloser(response.Body)
+	if !isEventStream(response) {
+		reader := bufio.NewReader(response.Body)
+		if isJSONObject(reader) {
+			defer response.Body.Close()
+			var resp types.CreateChatCompletionResponse
+			if err := json.NewDecoder(reader).Decode(&resp); err != nil {
+				p.Logger.Error("failed to unmarshal response", err, "provider", p.GetName())
+				return nil, err
 			}
-
-			line, err := reader.ReadBytes('\n')
-			if err != nil {
-				if err != io.EOF {
-					p.Logger.Error("error reading stream", err, "provider", p.GetName())
-				} else {
-					p.Logger.Debug("stream ended gracefully", "provider", p.GetName())
-				}
-				return
-			}
-
-			if len(line) > 0 {
-				select {
-				case stream <- line:
-				case <-ctx.Done():
-					p.Logger.Debug("stream cancelled while sending data", "provider", p.GetName())
-					return
-				}
+			if len(resp.Choices) == 0 {
+				err := errors.New("provider returned a completion without choices")
+				p.Logger.Error("failed to synthesize stream", err, "provider", p.GetName())
+				return nil, err
 			}
+			p.Logger.Debug("provider ignored stream, synthesizing it", "provider", p.GetName())
+			return synthesizedStream(resp), nil
 		}
-	}()
+		body = struct {
+			io.Reader
+			io.Closer
+		}{reader, response.Body}
+	}
 
-	return stream, nil
+	return readStream(ctx, body, p.Logger, p.GetName()), nil
 }
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// requestQuirks describes how the chat completions API of a provider (or of
+// some of its models) departs from the OpenAI request format the gateway
+// accepts. Fields are named as in the request body.
+type requestQuirks struct {
+	// Values maps the values of enum fields the provider spells differently:
+	// field -> gateway value -> provider value. An empty provider value
+	// removes the field.
+	Values map[string]map[string]string
+	// Rename maps fields to the name the provider expects them under. A
+	// field already set under the new name wins over the renamed one.
+	Rename map[string]string
+	// Drop lists fields the provider rejects
+	Drop []string
+}
+
+// providerQuirks holds the request quirks of a provider
+type providerQuirks struct {
+	requestQuirks
+	// Extensions lists the gateway extensions of the request format (see
+	// extensionFields) the provider understands; the others are removed
+	Extensions []string
+	// Models holds additional quirks of the models whose name starts with a
+	// prefix, applied after the provider's
+	Models map[string]requestQuirks
+}
+
+// extensionFields are request fields the gateway accepts on top of the OpenAI
+// format. They are only sent to providers listing them in Extensions and are
+// removed from the requests to every other provider, which would reject them
+// as unknown: reasoning_format only reaches groq and llamacpp.
+var extensionFields = []string{"reasoning_format"}
+
+// openaiReasoningModels only accept max_completion_tokens
+var openaiReasoningModels = requestQuirks{
+	Rename: map[string]string{"max_tokens": "max_completion_tokens"},
+}
+
+// quirkTable is the request quirk table. Providers not listed accept the
+// OpenAI format as it is; the gateway extensions are stripped from their
+// requests.
+var quirkTable = map[types.Provider]providerQuirks{
+	constants.CohereID: {
+		requestQuirks: requestQuirks{Drop: []string{"stream_options"}},
+	},
+	constants.DeepseekID: {
+		// Reasoning is selected with the model (deepseek-reasoner)
+		requestQuirks: requestQuirks{Drop: []string{"reasoning_effort"}},
+	},
+	constants.GroqID: {
+		requestQuirks: requestQuirks{
+			Values: map[string]map[string]string{
+				"reasoning_effort": {"minimal": "low"},
+			},
+		},
+		Extensions: []string{"reasoning_format"},
+	},
+	constants.LlamacppID: {
+		requestQuirks: requestQuirks{
+			Values: map[string]map[string]string{
+				"reasoning_format": {"parsed": "deepseek", "raw": "none"},
+			},
+		},
+		Extensions: []string{"reasoning_format"},
+	},
+	constants.MistralID: {
+		requestQuirks: requestQuirks{Drop: []string{"stream_options"}},
+	},
+	constants.OpenaiID: {
+		Models: map[string]requestQuirks{
+			"o1":    openaiReasoningModels,
+			"o3":    openaiReasoningModels,
+			"o4":    openaiReasoningModels,
+			"gpt-5": openaiReasoningModels,
+		},
+	},
+}
+
+// normalizeRequest rewrites an encoded chat completion request for model on
+// provider according to the quirk table
+func normalizeRequest(provider types.Provider, model string, body []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	quirks := quirkTable[provider]
+	for _, field := range extensionFields {
+		if !slices.Contains(quirks.Extensions, field) {
+			delete(fields, field)
+		}
+	}
+	quirks.apply(fields)
+
+	model = strings.ToLower(model)
+	for _, prefix := range slices.Sorted(maps.Keys(quirks.Models)) {
+		if strings.HasPrefix(model, prefix) {
+			modelQuirks := quirks.Models[prefix]
+			modelQuirks.apply(fields)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// apply rewrites the request fields: enum values first, then renames and
+// removals
+func (q *requestQuirks) apply(fields map[string]json.RawMessage) {
+	for field, mapping := range q.Values {
+		raw, ok := fields[field]
+		if !ok {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue
+		}
+		mapped, ok := mapping[value]
+		if !ok {
+			continue
+		}
+		if mapped == "" {
+			delete(fields, field)
+			continue
+		}
+		fields[field], _ = json.Marshal(mapped)
+	}
+
+	for from, to := range q.Rename {
+		raw, ok := fields[from]
+		if !ok {
+			continue
+		}
+		delete(fields, from)
+		if _, set := fields[to]; !set {
+			fields[to] = raw
+		}
+	}
+
+	for _, field := range q.Drop {
+		delete(fields, field)
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// TestNormalizeRequest exercises each kind of quirk: provider-level drops and
+// enum mappings, model-prefix renames, and the gateway extensions that only
+// reach providers understanding them.
+func TestNormalizeRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider types.Provider
+		model    string
+		body     string
+		want     string
+	}{
+		{
+			name:     "UnlistedProviderLosesExtensions",
+			provider: constants.OpenaiID,
+			model:    "gpt-4o",
+			body:     `{"model":"gpt-4o","max_tokens":10,"reasoning_format":"parsed"}`,
+			want:     `{"model":"gpt-4o","max_tokens":10}`,
+		},
+		{
+			name:     "ReasoningModelRenamesMaxTokens",
+			provider: constants.OpenaiID,
+			model:    "o3-mini",
+			body:     `{"model":"o3-mini","max_tokens":10}`,
+			want:     `{"model":"o3-mini","max_completion_tokens":10}`,
+		},
+		{
+			name:     "RenameKeepsExplicitTarget",
+			provider: constants.OpenaiID,
+			model:    "GPT-5",
+			body:     `{"model":"GPT-5","max_tokens":10,"max_completion_tokens":20}`,
+			want:     `{"model":"GPT-5","max_completion_tokens":20}`,
+		},
+		{
+			name:     "StreamOptionsDropped",
+			provider: constants.MistralID,
+			model:    "mistral-large-latest",
+			body:     `{"model":"mistral-large-latest","stream":true,"stream_options":{"include_usage":true}}`,
+			want:     `{"model":"mistral-large-latest","stream":true}`,
+		},
+		{
+			name:     "EnumMappedAndExtensionKept",
+			provider: constants.GroqID,
+			model:    "openai/gpt-oss-120b",
+			body:     `{"model":"openai/gpt-oss-120b","reasoning_effort":"minimal","reasoning_format":"parsed"}`,
+			want:     `{"model":"openai/gpt-oss-120b","reasoning_effort":"low","reasoning_format":"parsed"}`,
+		},
+		{
+			name:     "ExtensionValueMapped",
+			provider: constants.LlamacppID,
+			model:    "qwen3",
+			body:     `{"model":"qwen3","reasoning_format":"parsed"}`,
+			want:     `{"model":"qwen3","reasoning_format":"deepseek"}`,
+		},
+		{
+			name:     "FieldDropped",
+			provider: constants.DeepseekID,
+			model:    "deepseek-reasoner",
+			body:     `{"model":"deepseek-reasoner","reasoning_effort":"high"}`,
+			want:     `{"model":"deepseek-reasoner"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeRequest(tt.provider, tt.model, []byte(tt.body))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+// TestNormalizeRequestStripsExtensions pins which providers receive the
+// gateway extensions of the request format
+func TestNormalizeRequestStripsExtensions(t *testing.T) {
+	providers := []types.Provider{
+		constants.AnthropicID, constants.CloudflareID, constants.CohereID, constants.DeepseekID,
+		constants.GoogleID, constants.GroqID, constants.LlamacppID, constants.MinimaxID,
+		constants.MistralID, constants.MoonshotID, constants.NvidiaID, constants.OllamaID,
+		constants.OllamaCloudID, constants.OpenaiID, constants.ZaiID,
+	}
+	for _, provider := range providers {
+		t.Run(string(provider), func(t *testing.T) {
+			got, err := normalizeRequest(provider, "model", []byte(`{"model":"model","reasoning_format":"hidden"}`))
+			require.NoError(t, err)
+			switch provider {
+			case constants.GroqID, constants.LlamacppID:
+				assert.JSONEq(t, `{"model":"model","reasoning_format":"hidden"}`, string(got))
+			default:
+				assert.JSONEq(t, `{"model":"model"}`, string(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	l "github.com/inference-gateway/inference-gateway/logger"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// StreamDone is the server-sent event terminating an OpenAI chat completion
+// stream
+var StreamDone = []byte("data: [DONE]\n\n")
+
+// StreamEvent is an event of a chat completion stream: a chunk, or the error
+// ending the stream. The stream itself ends when its channel is closed; the
+// upstream [DONE] marker is not an event, so consumers relaying the stream
+// write StreamDone once the channel closes without an error.
+type StreamEvent struct {
+	// Chunk is the parsed chunk; nil for an error event
+	Chunk *types.CreateChatCompletionStreamResponse
+	// Data is the chunk as JSON in the OpenAI format, including the fields
+	// Chunk does not model
+	Data []byte
+	// Usage is the token usage carried by the chunk, if any. Usage is always
+	// reported in a chunk of its own, with no choices.
+	Usage *types.CompletionUsage
+	// Err is the error ending the stream; errors sent by the provider are
+	// UpstreamErrors
+	Err error
+}
+
+// SSE encodes the event as an OpenAI server-sent event; errors are rendered
+// in the OpenAI error envelope, see ClassifyError
+func (e StreamEvent) SSE() []byte {
+	data := e.Data
+	if e.Err != nil {
+		data = errorEvent(e.Err)
+	}
+	return fmt.Appendf(nil, "data: %s\n\n", data)
+}
+
+// streamFields are the chunk fields copied to the usage chunk split from a
+// chunk carrying both choices and usage
+var streamFields = []string{"id", "object", "created", "model", "system_fingerprint"}
+
+// readStream parses the server-sent events of an OpenAI-compatible chat
+// completion stream into events, normalizing the deviations of providers:
+//   - the reasoning of a delta is always reported as reasoning_content
+//   - usage is always reported in a chunk of its own, including usage sent
+//     along the last choices (e.g. Mistral, without stream_options) or under
+//     x_groq
+//   - a stream ending without [DONE] ends like any other
+//
+// The channel is closed when the stream ends, fails or ctx is done; body is
+// closed then.
+func readStream(ctx context.Context, body io.ReadCloser, logger l.Logger, provider string) <-chan StreamEvent {
+	stream := make(chan StreamEvent, 100)
+	go func() {
+		defer body.Close()
+		defer close(stream)
+
+		send := func(event StreamEvent) bool {
+			select {
+			case stream <- event:
+				return true
+			case <-ctx.Done():
+				logger.Debug("stream cancelled while sending data", "provider", provider)
+				return false
+			}
+		}
+
+		reader := bufio.NewReaderSize(body, 4096)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:")); ok {
+				data = bytes.TrimSpace(data)
+				if bytes.Equal(data, []byte("[DONE]")) {
+					logger.Debug("stream ended gracefully", "provider", provider)
+					return
+				}
+				if len(data) > 0 {
+					events, perr := parseStreamData(data)
+					if perr != nil {
+						logger.Debug("skipping malformed stream chunk", "provider", provider, "error", perr.Error(), "chunk", string(data))
+					}
+					for _, event := range events {
+						if !send(event) || event.Err != nil {
+							return
+						}
+					}
+				}
+			}
+
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					logger.Debug("stream ended without [DONE]", "provider", provider)
+				} else if ctx.Err() == nil {
+					logger.Error("error reading stream", err, "provider", provider)
+					send(StreamEvent{Err: fmt.Errorf("failed to read stream: %w", err)})
+				}
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				logger.Debug("stream cancelled due to context", "provider", provider)
+				return
+			default:
+			}
+		}
+	}()
+	return stream
+}
+
+// parseStreamData parses the data of a server-sent event into the events it
+// stands for: an error, a chunk, or a chunk followed by its usage
+func parseStreamData(data []byte) ([]StreamEvent, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	if upstreamErr, ok := fields["error"]; ok && isSet(upstreamErr) {
+		return []StreamEvent{{Err: streamError(data)}}, nil
+	}
+
+	changed := normalizeReasoning(fields)
+	if groq, ok := fields["x_groq"]; ok {
+		var extension struct {
+			Usage json.RawMessage `json:"usage"`
+		}
+		if json.Unmarshal(groq, &extension) == nil && isSet(extension.Usage) && !isSet(fields["usage"]) {
+			fields["usage"] = extension.Usage
+			changed = true
+		}
+	}
+
+	var usageFields map[string]json.RawMessage
+	if isSet(fields["usage"]) && hasChoices(fields["choices"]) {
+		usageFields = map[string]json.RawMessage{"choices": json.RawMessage("[]"), "usage": fields["usage"]}
+		for _, name := range streamFields {
+			if value, ok := fields[name]; ok {
+				usageFields[name] = value
+			}
+		}
+		delete(fields, "usage")
+		changed = true
+	}
+
+	if changed {
+		var err error
+		if data, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+	}
+	chunk, err := chunkEvent(data)
+	if err != nil {
+		return nil, err
+	}
+	events := []StreamEvent{chunk}
+	if usageFields != nil {
+		usageData, err := json.Marshal(usageFields)
+		if err != nil {
+			return nil, err
+		}
+		usage, err := chunkEvent(usageData)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, usage)
+	}
+	return events, nil
+}
+
+func chunkEvent(data []byte) (StreamEvent, error) {
+	var chunk types.CreateChatCompletionStreamResponse
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return StreamEvent{}, err
+	}
+	return StreamEvent{Chunk: &chunk, Data: data, Usage: chunk.Usage}, nil
+}
+
+// normalizeReasoning renames the reasoning of the choice deltas to
+// reasoning_content, reporting whether any delta changed
+func normalizeReasoning(fields map[string]json.RawMessage) bool {
+	var choices []map[string]json.RawMessage
+	if json.Unmarshal(fields["choices"], &choices) != nil {
+		return false
+	}
+	changed := false
+	for _, choice := range choices {
+		var delta map[string]json.RawMessage
+		if json.Unmarshal(choice["delta"], &delta) != nil {
+			continue
+		}
+		reasoning, ok := delta["reasoning"]
+		if !ok {
+			continue
+		}
+		delete(delta, "reasoning")
+		if isSet(reasoning) && !isSet(delta["reasoning_content"]) {
+			delta["reasoning_content"] = reasoning
+		}
+		choice["delta"], _ = json.Marshal(delta)
+		changed = true
+	}
+	if changed {
+		fields["choices"], _ = json.Marshal(choices)
+	}
+	return changed
+}
+
+func hasChoices(raw json.RawMessage) bool {
+	var choices []json.RawMessage
+	return json.Unmarshal(raw, &choices) == nil && len(choices) > 0
+}
+
+func isSet(raw json.RawMessage) bool {
+	return len(raw) > 0 && string(raw) != "null"
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	l "github.com/inference-gateway/inference-gateway/logger"
+)
+
+func collectStream(t *testing.T, body string) []StreamEvent {
+	t.Helper()
+	var events []StreamEvent
+	for event := range readStream(context.Background(), io.NopCloser(strings.NewReader(body)), l.NewNoopLogger(), "test") {
+		events = append(events, event)
+	}
+	return events
+}
+
+func eventData(events []StreamEvent) []string {
+	data := make([]string, len(events))
+	for i, event := range events {
+		data[i] = string(event.Data)
+	}
+	return data
+}
+
+func TestReadStream(t *testing.T) {
+	t.Run("StopsAtDone", func(t *testing.T) {
+		events := collectStream(t, ": keep-alive\n\n"+
+			"data: {\"id\":\"a\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"+
+			"data: [DONE]\n\n"+
+			"data: {\"id\":\"b\",\"choices\":[]}\n\n")
+		require.Len(t, events, 1)
+		require.NotNil(t, events[0].Chunk)
+		assert.Equal(t, "Hi", events[0].Chunk.Choices[0].Delta.Content)
+		assert.Equal(t, "data: {\"id\":\"a\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n", string(events[0].SSE()))
+	})
+
+	t.Run("MissingDone", func(t *testing.T) {
+		events := collectStream(t, "data: {\"id\":\"a\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}")
+		require.Len(t, events, 1)
+		assert.NoError(t, events[0].Err)
+	})
+
+	t.Run("ReasoningRenamed", func(t *testing.T) {
+		events := collectStream(t, "data: {\"id\":\"a\",\"choices\":[{\"index\":0,\"delta\":{\"reasoning\":\"Hmm\"}}]}\n\n")
+		require.Len(t, events, 1)
+		assert.JSONEq(t, `{"id":"a","choices":[{"index":0,"delta":{"reasoning_content":"Hmm"}}]}`, string(events[0].Data))
+		require.NotNil(t, events[0].Chunk.Choices[0].Delta.ReasoningContent)
+		assert.Equal(t, "Hmm", *events[0].Chunk.Choices[0].Delta.ReasoningContent)
+	})
+
+	t.Run("UsageSplitFromChoices", func(t *testing.T) {
+		events := collectStream(t, "data: {\"id\":\"a\",\"model\":\"mistral-small\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}],"+
+			"\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2,\"total_tokens\":5}}\n\n")
+		require.Len(t, events, 2)
+		assert.Nil(t, events[0].Usage)
+		assert.JSONEq(t, `{"id":"a","model":"mistral-small","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`, string(events[0].Data))
+		assert.JSONEq(t, `{"id":"a","model":"mistral-small","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`, string(events[1].Data))
+		require.NotNil(t, events[1].Usage)
+		assert.Equal(t, int64(5), events[1].Usage.TotalTokens)
+	})
+
+	t.Run("GroqUsageLifted", func(t *testing.T) {
+		events := collectStream(t, "data: {\"id\":\"a\",\"choices\":[],\"x_groq\":{\"id\":\"req_1\",\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2,\"total_tokens\":5}}}\n\n")
+		require.Len(t, events, 1)
+		require.NotNil(t, events[0].Usage)
+		assert.Equal(t, int64(3), events[0].Usage.PromptTokens)
+	})
+
+	t.Run("ErrorEndsStream", func(t *testing.T) {
+		events := collectStream(t, "data: {\"error\":{\"message\":\"overloaded\"}}\n\n"+
+			"data: {\"id\":\"a\",\"choices\":[]}\n\n")
+		require.Len(t, events, 1)
+		var upstreamErr *UpstreamError
+		require.ErrorAs(t, events[0].Err, &upstreamErr)
+		assert.Equal(t, ErrorCodeOverloaded, upstreamErr.Code)
+		assert.Equal(t, "data: {\"error\":{\"message\":\"overloaded\",\"type\":\"server_error\",\"code\":\"overloaded\",\"param\":null}}\n\n", string(events[0].SSE()))
+	})
+
+	t.Run("MalformedChunkSkipped", func(t *testing.T) {
+		events := collectStream(t, "data: {not json\n\ndata: {\"id\":\"a\",\"choices\":[]}\n\n")
+		require.Len(t, events, 1)
+		assert.Equal(t, []string{`{"id":"a","choices":[]}`}, eventData(events))
+	})
+}
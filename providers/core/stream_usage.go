@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// streamUsageKey is the context key of the StreamUsage of a request
+type streamUsageKey struct{}
+
+// StreamUsage collects the token usage and tool calls of the chat completion
+// streams answering a request from their typed events, so that telemetry does
+// not have to parse the server-sent events written to the client. A request
+// answered by the MCP agent streams once per agent iteration: usage is summed
+// over the streams and the tool calls of every stream are kept.
+//
+// The methods are safe to call on a nil StreamUsage, which records nothing.
+type StreamUsage struct {
+	mu        sync.Mutex
+	usage     types.CompletionUsage
+	reported  bool
+	toolCalls []types.ChatCompletionMessageToolCall
+	// stream accumulates the stream being observed
+	stream *StreamAccumulator
+}
+
+// WithStreamUsage returns a copy of ctx carrying a new StreamUsage
+func WithStreamUsage(ctx context.Context) (context.Context, *StreamUsage) {
+	usage := &StreamUsage{}
+	return context.WithValue(ctx, streamUsageKey{}, usage), usage
+}
+
+// StreamUsageFrom returns the StreamUsage of ctx, or nil when there is none
+func StreamUsageFrom(ctx context.Context) *StreamUsage {
+	usage, _ := ctx.Value(streamUsageKey{}).(*StreamUsage)
+	return usage
+}
+
+// Observe records a stream event; error events are ignored
+func (u *StreamUsage) Observe(event StreamEvent) {
+	if u == nil || event.Err != nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if event.Usage != nil {
+		u.usage.PromptTokens += event.Usage.PromptTokens
+		u.usage.CompletionTokens += event.Usage.CompletionTokens
+		u.usage.TotalTokens += event.Usage.TotalTokens
+		u.reported = true
+	}
+	if event.Chunk != nil && len(event.Chunk.Choices) > 0 {
+		if u.stream == nil {
+			u.stream = NewStreamAccumulator()
+		}
+		u.stream.Add(*event.Chunk)
+	}
+}
+
+// EndStream marks the end of the observed stream; the next event starts a
+// new one
+func (u *StreamUsage) EndStream() {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.endStream()
+}
+
+func (u *StreamUsage) endStream() {
+	if u.stream == nil {
+		return
+	}
+	if resp, err := u.stream.Response(); err == nil && resp.Choices[0].Message.ToolCalls != nil {
+		for _, toolCall := range *resp.Choices[0].Message.ToolCalls {
+			// Like AccumulateStreamingToolCalls, calls that never received a
+			// function name are dropped
+			if toolCall.Function.Name != "" {
+				u.toolCalls = append(u.toolCalls, toolCall)
+			}
+		}
+	}
+	u.stream = nil
+}
+
+// Usage returns the summed token usage of the observed streams and whether
+// any of them reported usage
+func (u *StreamUsage) Usage() (types.CompletionUsage, bool) {
+	if u == nil {
+		return types.CompletionUsage{}, false
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.usage, u.reported
+}
+
+// ToolCalls returns the tool calls of the observed streams, ending the
+// observed stream
+func (u *StreamUsage) ToolCalls() []types.ChatCompletionMessageToolCall {
+	if u == nil {
+		return nil
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.endStream()
+	return u.toolCalls
+}
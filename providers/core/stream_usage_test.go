@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func usageEvent(t *testing.T, data string) StreamEvent {
+	t.Helper()
+	var chunk types.CreateChatCompletionStreamResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+	return StreamEvent{Chunk: &chunk, Data: []byte(data), Usage: chunk.Usage}
+}
+
+func TestStreamUsage(t *testing.T) {
+	t.Run("SumsStreamsAndKeepsTheirToolCalls", func(t *testing.T) {
+		ctx, usage := WithStreamUsage(context.Background())
+		require.Same(t, usage, StreamUsageFrom(ctx))
+
+		// Two agent iterations whose tool calls both have index 0
+		usage.Observe(usageEvent(t, `{"id":"a","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"search","arguments":"{}"}}]}}]}`))
+		usage.Observe(usageEvent(t, `{"id":"a","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12}}`))
+		usage.EndStream()
+		usage.Observe(usageEvent(t, `{"id":"b","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_2","type":"function","function":{"name":"fetch","arguments":"{\"url\":"}}]}}]}`))
+		usage.Observe(usageEvent(t, `{"id":"b","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"x\"}"}}]}}]}`))
+		usage.Observe(StreamEvent{Err: context.Canceled})
+		usage.Observe(usageEvent(t, `{"id":"b","choices":[],"usage":{"prompt_tokens":20,"completion_tokens":4,"total_tokens":24}}`))
+
+		total, ok := usage.Usage()
+		require.True(t, ok)
+		assert.Equal(t, int64(30), total.PromptTokens)
+		assert.Equal(t, int64(6), total.CompletionTokens)
+		assert.Equal(t, int64(36), total.TotalTokens)
+
+		toolCalls := usage.ToolCalls()
+		require.Len(t, toolCalls, 2)
+		assert.Equal(t, "search", toolCalls[0].Function.Name)
+		assert.Equal(t, "fetch", toolCalls[1].Function.Name)
+		assert.Equal(t, `{"url":"x"}`, toolCalls[1].Function.Arguments)
+	})
+
+	t.Run("NoUsageReported", func(t *testing.T) {
+		_, usage := WithStreamUsage(context.Background())
+		usage.Observe(usageEvent(t, `{"id":"a","choices":[{"index":0,"delta":{"content":"Hi"}}]}`))
+		_, ok := usage.Usage()
+		assert.False(t, ok)
+		assert.Empty(t, usage.ToolCalls())
+	})
+
+	t.Run("NilIsANoop", func(t *testing.T) {
+		usage := StreamUsageFrom(context.Background())
+		assert.Nil(t, usage)
+		usage.Observe(StreamEvent{Usage: &types.CompletionUsage{PromptTokens: 1}})
+		usage.EndStream()
+		_, ok := usage.Usage()
+		assert.False(t, ok)
+		assert.Nil(t, usage.ToolCalls())
+	})
+}
@@ -0,0 +1,242 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// SynthesizeStream converts a chat completion into the chunks of the stream a
+// provider would have sent for it. Each choice gets a role delta, then its
+// reasoning, content and tool call deltas, and a final delta with the finish
+// reason; a usage-only chunk follows when usage is known.
+func SynthesizeStream(resp types.CreateChatCompletionResponse) []types.CreateChatCompletionStreamResponse {
+	var chunks []types.CreateChatCompletionStreamResponse
+	newChunk := func(choices ...types.ChatCompletionStreamChoice) types.CreateChatCompletionStreamResponse {
+		return types.CreateChatCompletionStreamResponse{
+			ID:      resp.ID,
+			Object:  "chat.completion.chunk",
+			Created: resp.Created,
+			Model:   resp.Model,
+			Choices: choices,
+		}
+	}
+
+	for _, choice := range resp.Choices {
+		delta := func(d types.ChatCompletionStreamResponseDelta) {
+			d.Role = types.Assistant
+			chunks = append(chunks, newChunk(types.ChatCompletionStreamChoice{Index: choice.Index, Delta: d}))
+		}
+
+		delta(types.ChatCompletionStreamResponseDelta{})
+		if reasoning := choice.Message.ReasoningContent; reasoning != nil && *reasoning != "" {
+			delta(types.ChatCompletionStreamResponseDelta{ReasoningContent: reasoning})
+		} else if reasoning := choice.Message.Reasoning; reasoning != nil && *reasoning != "" {
+			delta(types.ChatCompletionStreamResponseDelta{ReasoningContent: reasoning})
+		}
+		if content := choice.Message.TextContent(); content != "" {
+			delta(types.ChatCompletionStreamResponseDelta{Content: content})
+		}
+		if choice.Message.ToolCalls != nil {
+			for i, tc := range *choice.Message.ToolCalls {
+				id, typ, function := tc.ID, string(tc.Type), tc.Function
+				delta(types.ChatCompletionStreamResponseDelta{ToolCalls: &[]types.ChatCompletionMessageToolCallChunk{{
+					Index:        i,
+					ID:           &id,
+					Type:         &typ,
+					Function:     &function,
+					ExtraContent: tc.ExtraContent,
+				}}})
+			}
+		}
+
+		last := newChunk(types.ChatCompletionStreamChoice{
+			Index:        choice.Index,
+			Delta:        types.ChatCompletionStreamResponseDelta{Role: types.Assistant},
+			FinishReason: choice.FinishReason,
+			Logprobs:     choice.Logprobs,
+		})
+		chunks = append(chunks, last)
+	}
+
+	if resp.Usage != nil {
+		usage := newChunk()
+		usage.Choices = []types.ChatCompletionStreamChoice{}
+		usage.Usage = resp.Usage
+		chunks = append(chunks, usage)
+	}
+	return chunks
+}
+
+// synthesizedStream streams a chat completion returned by a provider that
+// ignored the stream parameter, see SynthesizeStream
+func synthesizedStream(resp types.CreateChatCompletionResponse) <-chan StreamEvent {
+	chunks := SynthesizeStream(resp)
+	stream := make(chan StreamEvent, len(chunks))
+	defer close(stream)
+	for _, chunk := range chunks {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			stream <- StreamEvent{Err: fmt.Errorf("failed to encode chunk: %w", err)}
+			break
+		}
+		stream <- StreamEvent{Chunk: &chunk, Data: data, Usage: chunk.Usage}
+	}
+	return stream
+}
+
+// streamChoice accumulates the deltas of one choice of a stream
+type streamChoice struct {
+	content          strings.Builder
+	reasoning        strings.Builder
+	reasoningContent strings.Builder
+	finishReason     types.FinishReason
+	toolCalls        []types.ChatCompletionMessageToolCall
+}
+
+// StreamAccumulator rebuilds a chat completion from the chunks of its stream
+type StreamAccumulator struct {
+	resp    types.CreateChatCompletionResponse
+	choices map[int]*streamChoice
+}
+
+// NewStreamAccumulator returns an empty accumulator
+func NewStreamAccumulator() *StreamAccumulator {
+	return &StreamAccumulator{
+		resp:    types.CreateChatCompletionResponse{Object: "chat.completion"},
+		choices: make(map[int]*streamChoice),
+	}
+}
+
+// Add accumulates a chunk
+func (a *StreamAccumulator) Add(chunk types.CreateChatCompletionStreamResponse) {
+	if chunk.ID != "" {
+		a.resp.ID, a.resp.Created, a.resp.Model = chunk.ID, chunk.Created, chunk.Model
+	}
+	if chunk.Usage != nil {
+		a.resp.Usage = chunk.Usage
+	}
+	for _, c := range chunk.Choices {
+		choice, exists := a.choices[c.Index]
+		if !exists {
+			choice = &streamChoice{}
+			a.choices[c.Index] = choice
+		}
+		choice.content.WriteString(c.Delta.Content)
+		if c.Delta.Reasoning != nil {
+			choice.reasoning.WriteString(*c.Delta.Reasoning)
+		}
+		if c.Delta.ReasoningContent != nil {
+			choice.reasoningContent.WriteString(*c.Delta.ReasoningContent)
+		}
+		if c.FinishReason != "" {
+			choice.finishReason = c.FinishReason
+		}
+		if c.Delta.ToolCalls != nil {
+			choice.addToolCalls(*c.Delta.ToolCalls)
+		}
+	}
+}
+
+// Response returns the accumulated chat completion. It fails when no choice
+// was streamed or the choice indices have gaps.
+func (a *StreamAccumulator) Response() (types.CreateChatCompletionResponse, error) {
+	if len(a.choices) == 0 {
+		return types.CreateChatCompletionResponse{}, errors.New("stream has no choices")
+	}
+
+	resp := a.resp
+	resp.Choices = make([]types.ChatCompletionChoice, 0, len(a.choices))
+	for i := range len(a.choices) {
+		choice, exists := a.choices[i]
+		if !exists {
+			return types.CreateChatCompletionResponse{}, fmt.Errorf("stream has no choice %d", i)
+		}
+		msg := types.Message{Role: types.Assistant}
+		if err := msg.Content.FromMessageContent0(choice.content.String()); err != nil {
+			return types.CreateChatCompletionResponse{}, err
+		}
+		if choice.reasoning.Len() > 0 {
+			reasoning := choice.reasoning.String()
+			msg.Reasoning = &reasoning
+		}
+		if choice.reasoningContent.Len() > 0 {
+			reasoning := choice.reasoningContent.String()
+			msg.ReasoningContent = &reasoning
+		}
+		if len(choice.toolCalls) > 0 {
+			toolCalls := choice.toolCalls
+			msg.ToolCalls = &toolCalls
+		}
+		resp.Choices = append(resp.Choices, types.ChatCompletionChoice{
+			Index:        i,
+			FinishReason: choice.finishReason,
+			Message:      msg,
+		})
+	}
+	return resp, nil
+}
+
+func (c *streamChoice) addToolCalls(deltas []types.ChatCompletionMessageToolCallChunk) {
+	for _, delta := range deltas {
+		for len(c.toolCalls) <= delta.Index {
+			c.toolCalls = append(c.toolCalls, types.ChatCompletionMessageToolCall{Type: types.Function})
+		}
+		toolCall := &c.toolCalls[delta.Index]
+		if delta.ID != nil {
+			toolCall.ID = *delta.ID
+		}
+		if delta.Type != nil {
+			toolCall.Type = types.ChatCompletionToolType(*delta.Type)
+		}
+		if delta.ExtraContent != nil {
+			toolCall.ExtraContent = delta.ExtraContent
+		}
+		if delta.Function != nil {
+			if delta.Function.Name != "" {
+				toolCall.Function.Name = delta.Function.Name
+			}
+			toolCall.Function.Arguments += delta.Function.Arguments
+		}
+	}
+}
+
+// aggregateStream reads a stream into the chat completion it carries, for
+// providers answering a non-streaming request with a stream
+func aggregateStream(stream <-chan StreamEvent) (types.CreateChatCompletionResponse, error) {
+	acc := NewStreamAccumulator()
+	for event := range stream {
+		if event.Err != nil {
+			return types.CreateChatCompletionResponse{}, event.Err
+		}
+		acc.Add(*event.Chunk)
+	}
+	return acc.Response()
+}
+
+// isEventStream reports whether a response is a server-sent event stream
+func isEventStream(response *http.Response) bool {
+	return strings.HasPrefix(response.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// isJSONObject reports whether the body read by r is a JSON object rather
+// than server-sent events, skipping the leading whitespace
+func isJSONObject(r *bufio.Reader) bool {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.ReadByte()
+		default:
+			return b[0] == '{'
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func TestSynthesizeStream(t *testing.T) {
+	var resp types.CreateChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "c1", "object": "chat.completion", "created": 1, "model": "gpt-4o",
+		"choices": [{
+			"index": 0,
+			"finish_reason": "tool_calls",
+			"message": {
+				"role": "assistant",
+				"content": "Checking",
+				"reasoning": "The user wants the weather",
+				"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}}]
+			}
+		}],
+		"usage": {"prompt_tokens": 3, "completion_tokens": 2, "total_tokens": 5}
+	}`), &resp))
+
+	chunks := SynthesizeStream(resp)
+	data := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		encoded, err := json.Marshal(chunk)
+		require.NoError(t, err)
+		data[i] = string(encoded)
+	}
+	require.Len(t, data, 6)
+	assert.JSONEq(t, `{"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":""},"finish_reason":""}]}`, data[0])
+	assert.JSONEq(t, `{"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"","reasoning_content":"The user wants the weather"},"finish_reason":""}]}`, data[1])
+	assert.JSONEq(t, `{"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Checking"},"finish_reason":""}]}`, data[2])
+	assert.JSONEq(t, `{"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},"finish_reason":""}]}`, data[3])
+	assert.JSONEq(t, `{"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":""},"finish_reason":"tool_calls"}]}`, data[4])
+	assert.JSONEq(t, `{"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`, data[5])
+
+	// Accumulating the stream gives the completion back, reasoning included
+	acc := NewStreamAccumulator()
+	for _, chunk := range chunks {
+		acc.Add(chunk)
+	}
+	again, err := acc.Response()
+	require.NoError(t, err)
+	assert.Equal(t, resp.ID, again.ID)
+	assert.Equal(t, resp.Usage, again.Usage)
+	require.Len(t, again.Choices, 1)
+	assert.Equal(t, "Checking", again.Choices[0].Message.TextContent())
+	assert.Equal(t, types.ToolCalls, again.Choices[0].FinishReason)
+	assert.Equal(t, resp.Choices[0].Message.ToolCalls, again.Choices[0].Message.ToolCalls)
+	require.NotNil(t, again.Choices[0].Message.ReasoningContent)
+	assert.Equal(t, "The user wants the weather", *again.Choices[0].Message.ReasoningContent)
+}
+
+func TestStreamAccumulatorRejectsGaps(t *testing.T) {
+	acc := NewStreamAccumulator()
+	_, err := acc.Response()
+	assert.Error(t, err, "a stream without choices has no completion")
+
+	acc.Add(types.CreateChatCompletionStreamResponse{ID: "c1", Choices: []types.ChatCompletionStreamChoice{{Index: 1}}})
+	_, err = acc.Response()
+	assert.Error(t, err, "choice 0 is missing")
+}
+
+func TestIsJSONObject(t *testing.T) {
+	assert.True(t, isJSONObject(bufio.NewReader(strings.NewReader("\n  {\"id\":\"c1\"}"))))
+	assert.False(t, isJSONObject(bufio.NewReader(strings.NewReader("data: {\"id\":\"c1\"}\n\n"))))
+	assert.False(t, isJSONObject(bufio.NewReader(strings.NewReader(""))))
+}
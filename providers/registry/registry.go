@@ -2,6 +2,7 @@ package registry
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	logger "github.com/inference-gateway/inference-gateway/logger"
 	client "github.com/inference-gateway/inference-gateway/providers/client"
@@ -25,26 +26,34 @@ type ProviderConfig struct {
 type ProviderRegistry interface {
 	GetProviders() map[types.Provider]*ProviderConfig
 	BuildProvider(providerID types.Provider, c client.Client) (core.IProvider, error)
+	// Reload atomically replaces the provider configurations. Providers that
+	// were already built keep the configuration they were built with.
+	Reload(cfg map[types.Provider]*ProviderConfig)
 }
 
 type ProviderRegistryImpl struct {
-	cfg    map[types.Provider]*ProviderConfig
+	cfg    atomic.Pointer[map[types.Provider]*ProviderConfig]
 	logger logger.Logger
 }
 
 func NewProviderRegistry(cfg map[types.Provider]*ProviderConfig, logger logger.Logger) ProviderRegistry {
-	return &ProviderRegistryImpl{
-		cfg:    cfg,
+	p := &ProviderRegistryImpl{
 		logger: logger,
 	}
+	p.cfg.Store(&cfg)
+	return p
 }
 
 func (p *ProviderRegistryImpl) GetProviders() map[types.Provider]*ProviderConfig {
-	return p.cfg
+	return *p.cfg.Load()
+}
+
+func (p *ProviderRegistryImpl) Reload(cfg map[types.Provider]*ProviderConfig) {
+	p.cfg.Store(&cfg)
 }
 
 func (p *ProviderRegistryImpl) BuildProvider(providerID types.Provider, c client.Client) (core.IProvider, error) {
-	provider, ok := p.cfg[providerID]
+	provider, ok := p.GetProviders()[providerID]
 	if !ok {
 		return nil, fmt.Errorf("provider %s not found", providerID)
 	}
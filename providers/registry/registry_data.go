@@ -39,6 +39,7 @@ var Registry = map[types.Provider]*ProviderConfig{
 		Endpoints: types.Endpoints{
 			Models: constants.CohereModelsEndpoint,
 			Chat:   constants.CohereChatEndpoint,
+			Rerank: ptr(constants.CohereRerankEndpoint),
 		},
 	},
 	constants.DeepseekID: {
@@ -47,8 +48,9 @@ var Registry = map[types.Provider]*ProviderConfig{
 		URL:      constants.DeepseekDefaultBaseURL,
 		AuthType: constants.AuthTypeBearer,
 		Endpoints: types.Endpoints{
-			Models: constants.DeepseekModelsEndpoint,
-			Chat:   constants.DeepseekChatEndpoint,
+			Models:      constants.DeepseekModelsEndpoint,
+			Chat:        constants.DeepseekChatEndpoint,
+			Completions: ptr(constants.DeepseekCompletionsEndpoint),
 		},
 	},
 	constants.GoogleID: {
@@ -67,8 +69,11 @@ var Registry = map[types.Provider]*ProviderConfig{
 		URL:      constants.GroqDefaultBaseURL,
 		AuthType: constants.AuthTypeBearer,
 		Endpoints: types.Endpoints{
-			Models: constants.GroqModelsEndpoint,
-			Chat:   constants.GroqChatEndpoint,
+			Models:              constants.GroqModelsEndpoint,
+			Chat:                constants.GroqChatEndpoint,
+			AudioTranscriptions: ptr(constants.GroqAudioTranscriptionsEndpoint),
+			AudioTranslations:   ptr(constants.GroqAudioTranslationsEndpoint),
+			AudioSpeech:         ptr(constants.GroqAudioSpeechEndpoint),
 		},
 	},
 	constants.LlamacppID: {
@@ -77,8 +82,10 @@ var Registry = map[types.Provider]*ProviderConfig{
 		URL:      constants.LlamacppDefaultBaseURL,
 		AuthType: constants.AuthTypeBearer,
 		Endpoints: types.Endpoints{
-			Models: constants.LlamacppModelsEndpoint,
-			Chat:   constants.LlamacppChatEndpoint,
+			Models:      constants.LlamacppModelsEndpoint,
+			Chat:        constants.LlamacppChatEndpoint,
+			Completions: ptr(constants.LlamacppCompletionsEndpoint),
+			Rerank:      ptr(constants.LlamacppRerankEndpoint),
 		},
 	},
 	constants.MinimaxID: {
@@ -97,8 +104,10 @@ var Registry = map[types.Provider]*ProviderConfig{
 		URL:      constants.MistralDefaultBaseURL,
 		AuthType: constants.AuthTypeBearer,
 		Endpoints: types.Endpoints{
-			Models: constants.MistralModelsEndpoint,
-			Chat:   constants.MistralChatEndpoint,
+			Models:              constants.MistralModelsEndpoint,
+			Chat:                constants.MistralChatEndpoint,
+			AudioTranscriptions: ptr(constants.MistralAudioTranscriptionsEndpoint),
+			Moderations:         ptr(constants.MistralModerationsEndpoint),
 		},
 	},
 	constants.MoonshotID: {
@@ -127,8 +136,9 @@ var Registry = map[types.Provider]*ProviderConfig{
 		URL:      constants.OllamaDefaultBaseURL,
 		AuthType: constants.AuthTypeNone,
 		Endpoints: types.Endpoints{
-			Models: constants.OllamaModelsEndpoint,
-			Chat:   constants.OllamaChatEndpoint,
+			Models:      constants.OllamaModelsEndpoint,
+			Chat:        constants.OllamaChatEndpoint,
+			Completions: ptr(constants.OllamaCompletionsEndpoint),
 		},
 	},
 	constants.OllamaCloudID: {
@@ -147,12 +157,17 @@ var Registry = map[types.Provider]*ProviderConfig{
 		URL:      constants.OpenaiDefaultBaseURL,
 		AuthType: constants.AuthTypeBearer,
 		Endpoints: types.Endpoints{
-			Models:           constants.OpenaiModelsEndpoint,
-			Chat:             constants.OpenaiChatEndpoint,
-			Responses:        ptr(constants.OpenaiResponsesEndpoint),
-			Images:           ptr(constants.OpenaiImagesEndpoint),
-			ImagesEdits:      ptr(constants.OpenaiImagesEditsEndpoint),
-			ImagesVariations: ptr(constants.OpenaiImagesVariationsEndpoint),
+			Models:              constants.OpenaiModelsEndpoint,
+			Chat:                constants.OpenaiChatEndpoint,
+			Completions:         ptr(constants.OpenaiCompletionsEndpoint),
+			Responses:           ptr(constants.OpenaiResponsesEndpoint),
+			Images:              ptr(constants.OpenaiImagesEndpoint),
+			ImagesEdits:         ptr(constants.OpenaiImagesEditsEndpoint),
+			ImagesVariations:    ptr(constants.OpenaiImagesVariationsEndpoint),
+			AudioTranscriptions: ptr(constants.OpenaiAudioTranscriptionsEndpoint),
+			AudioTranslations:   ptr(constants.OpenaiAudioTranslationsEndpoint),
+			AudioSpeech:         ptr(constants.OpenaiAudioSpeechEndpoint),
+			Moderations:         ptr(constants.OpenaiModerationsEndpoint),
 		},
 	},
 	constants.ZaiID: {
@@ -1874,12 +1874,18 @@ type CreateResponseRequest struct {
 
 // Endpoints defines model for Endpoints.
 type Endpoints struct {
-	Chat             string  `json:"chat"`
-	Images           *string `json:"images,omitempty"`
-	ImagesEdits      *string `json:"images_edits,omitempty"`
-	ImagesVariations *string `json:"images_variations,omitempty"`
-	Models           string  `json:"models"`
-	Responses        *string `json:"responses,omitempty"`
+	AudioSpeech         *string `json:"audio_speech,omitempty"`
+	AudioTranscriptions *string `json:"audio_transcriptions,omitempty"`
+	AudioTranslations   *string `json:"audio_translations,omitempty"`
+	Chat                string  `json:"chat"`
+	Completions         *string `json:"completions,omitempty"`
+	Images              *string `json:"images,omitempty"`
+	ImagesEdits         *string `json:"images_edits,omitempty"`
+	ImagesVariations    *string `json:"images_variations,omitempty"`
+	Models              string  `json:"models"`
+	Moderations         *string `json:"moderations,omitempty"`
+	Rerank              *string `json:"rerank,omitempty"`
+	Responses           *string `json:"responses,omitempty"`
 }
 
 // Error defines model for Error.
@@ -1991,6 +1997,9 @@ type ListModelsResponse struct {
 	Data     []Model   `json:"data"`
 	Object   string    `json:"object"`
 	Provider *Provider `json:"provider,omitempty"`
+
+	// Providers Freshness of each provider's models (present when the model catalog cache is enabled)
+	Providers *[]ProviderModelsStatus `json:"providers,omitempty"`
 }
 
 // ListToolsResponse Response structure for listing MCP tools
@@ -2048,6 +2057,47 @@ type MessageContent1 = []ContentPart
 // MessageRole Role of the message sender
 type MessageRole string
 
+// MessagesCountTokensRequest Request body for counting the input tokens of a Messages API request.
+// Accepts the fields of a message request that take up input tokens.
+type MessagesCountTokensRequest struct {
+	// Messages The messages to count.
+	Messages []MessagesMessage `json:"messages"`
+
+	// Model The model whose tokenizer counts the request.
+	Model string `json:"model"`
+
+	// System The system prompt. Can be a string or an array of system content
+	// blocks.
+	System *MessagesCountTokensRequest_System `json:"system,omitempty"`
+
+	// ToolChoice Controls which (if any) tool is called by the model. `auto` means
+	// the model can decide, `any` means the model must use a tool, and
+	// `tool` forces a specific tool.
+	ToolChoice *MessagesToolChoice `json:"tool_choice,omitempty"`
+
+	// Tools Definitions of tools the model may call.
+	Tools *[]MessagesTool `json:"tools,omitempty"`
+}
+
+// MessagesCountTokensRequestSystem0 System prompt as a string.
+type MessagesCountTokensRequestSystem0 = string
+
+// MessagesCountTokensRequestSystem1 defines model for MessagesCountTokensRequest.System.1.
+type MessagesCountTokensRequestSystem1 = []MessagesTextBlock
+
+// MessagesCountTokensRequest_System The system prompt. Can be a string or an array of system content
+// blocks.
+type MessagesCountTokensRequest_System struct {
+	union json.RawMessage
+}
+
+// MessagesCountTokensResponse The input token count of a Messages API request.
+type MessagesCountTokensResponse struct {
+	// InputTokens The total number of tokens across the system prompt, messages
+	// and tools.
+	InputTokens int `json:"input_tokens"`
+}
+
 // MessagesDocumentBlock A document content block in a Messages API request.
 type MessagesDocumentBlock struct {
 	// CacheControl Cache control settings for prompt caching. Currently only
@@ -2506,6 +2556,19 @@ type Provider string
 // ProviderAuthType Authentication type for providers
 type ProviderAuthType string
 
+// ProviderModelsStatus Freshness of a provider's models in the model catalog cache
+type ProviderModelsStatus struct {
+	// Error Error of the last failed refresh, cleared by the next successful one
+	Error *string `json:"error,omitempty"`
+
+	// LastSuccess When the provider last listed its models successfully
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	Provider    Provider   `json:"provider"`
+
+	// Stale Whether the models are older than `CACHE_MODELS_TTL` and being refreshed
+	Stale bool `json:"stale"`
+}
+
 // ProviderSpecificResponse Provider-specific response format. Examples:
 //
 // OpenAI GET /v1/models?provider=openai response:
@@ -2547,6 +2610,64 @@ type ProviderAuthType string
 // ```
 type ProviderSpecificResponse = map[string]any
 
+// RerankRequest Request to rank documents by their relevance to a query. The same
+// schema is accepted for every provider; the gateway translates it to the
+// provider's native rerank API where needed.
+type RerankRequest struct {
+	// Documents The documents to rank against the query.
+	Documents []string `json:"documents"`
+
+	// Model Model ID to use for reranking, e.g. `cohere/rerank-v3.5`.
+	Model string `json:"model"`
+
+	// Query The search query.
+	Query string `json:"query"`
+
+	// ReturnDocuments Include the text of each document in the results.
+	ReturnDocuments *bool `json:"return_documents,omitempty"`
+
+	// TopN Number of most relevant documents to return. Defaults to all documents.
+	TopN *int `json:"top_n,omitempty"`
+}
+
+// RerankResponse Documents ranked by their relevance to the query, most relevant first.
+type RerankResponse struct {
+	// ID The identifier of the rerank request, when the provider returns one.
+	ID *string `json:"id,omitempty"`
+
+	// Model The model that ranked the documents.
+	Model   *string        `json:"model,omitempty"`
+	Results []RerankResult `json:"results"`
+
+	// Usage What the request was billed for: tokens for token-priced rerankers,
+	// search units for Cohere.
+	Usage *RerankUsage `json:"usage,omitempty"`
+}
+
+// RerankResult defines model for RerankResult.
+type RerankResult struct {
+	// Document The document, when `return_documents` was set.
+	Document *struct {
+		Text string `json:"text"`
+	} `json:"document,omitempty"`
+
+	// Index The position of the document in the request's `documents`.
+	Index int `json:"index"`
+
+	// RelevanceScore The relevance of the document to the query, higher is more relevant.
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+// RerankUsage What the request was billed for: tokens for token-priced rerankers,
+// search units for Cohere.
+type RerankUsage struct {
+	// SearchUnits Number of search units billed.
+	SearchUnits *int64 `json:"search_units,omitempty"`
+
+	// TotalTokens Number of tokens processed.
+	TotalTokens *int64 `json:"total_tokens,omitempty"`
+}
+
 // Response Represents a model response returned by the Responses API.
 type Response struct {
 	// CreatedAt Unix timestamp (in seconds) of when the response was created.
@@ -3003,6 +3124,39 @@ type TextContentPart struct {
 // TextContentPartType Content type identifier
 type TextContentPartType string
 
+// TokenizeRequest Prompt to count the tokens of, as chat messages or plain text
+type TokenizeRequest struct {
+	// Input Plain text to count instead of messages
+	Input *string `json:"input,omitempty"`
+
+	// Messages Chat messages to count, including the chat formatting overhead
+	Messages *[]Message `json:"messages,omitempty"`
+
+	// Model Model ID, in provider/model format unless the provider is given with ?provider=
+	Model string `json:"model"`
+
+	// Tools Tool definitions sent along with the messages
+	Tools *[]ChatCompletionTool `json:"tools,omitempty"`
+}
+
+// TokenizeResponse Token count of a prompt
+type TokenizeResponse struct {
+	// ContextWindow Context window of the model in tokens, when known
+	ContextWindow *int `json:"context_window,omitempty"`
+
+	// Encoding BPE encoding of exact counts
+	Encoding *string `json:"encoding,omitempty"`
+
+	// Method How the tokens were counted, `tiktoken` (exact) or `heuristic` (estimated from the text length)
+	Method string `json:"method"`
+
+	// Model Model ID the tokens were counted for
+	Model string `json:"model"`
+
+	// Tokens Number of prompt tokens
+	Tokens int `json:"tokens"`
+}
+
 // ToolCallExtraContent Provider-specific opaque data attached to a tool call. The contents are
 // not interpreted by the gateway, but must be echoed back verbatim on the
 // next request that references this tool call. Currently used by Google
@@ -3191,6 +3345,12 @@ type CreateMessageParams struct {
 // PushMetricsJSONBody defines parameters for PushMetrics.
 type PushMetricsJSONBody = map[string]any
 
+// CountMessageTokensParams defines parameters for CountMessageTokens.
+type CountMessageTokensParams struct {
+	// Provider Specific provider to use (default determined by model)
+	Provider *Provider `form:"provider,omitempty" json:"provider,omitempty"`
+}
+
 // ListModelsParams defines parameters for ListModels.
 type ListModelsParams struct {
 	// Provider Specific provider to query (optional)
@@ -3200,6 +3360,10 @@ type ListModelsParams struct {
 	// Supported values: `pricing`, `context_window`, `modalities`.
 	// When omitted, the response remains unchanged (backward compatible).
 	Include *[]ListModelsParamsInclude `form:"include,omitempty" json:"include,omitempty"`
+
+	// Refresh Fetch the models from the providers instead of the model catalog
+	// cache. Only has an effect when `CACHE_MODELS_ENABLED` is true.
+	Refresh *bool `form:"refresh,omitempty" json:"refresh,omitempty"`
 }
 
 // ListModelsParamsInclude defines parameters for ListModels.
@@ -3241,6 +3405,12 @@ type CreateResponseParams struct {
 	Provider *Provider `form:"provider,omitempty" json:"provider,omitempty"`
 }
 
+// TokenizeParams defines parameters for Tokenize.
+type TokenizeParams struct {
+	// Provider Specific provider to use (default determined by model)
+	Provider *Provider `form:"provider,omitempty" json:"provider,omitempty"`
+}
+
 // CreateChatCompletionJSONRequestBody defines body for CreateChatCompletion for application/json ContentType.
 type CreateChatCompletionJSONRequestBody = CreateChatCompletionRequest
 
@@ -3256,6 +3426,9 @@ type CreateImageVariationMultipartRequestBody CreateImageVariationMultipartBody
 // CreateMessageJSONRequestBody defines body for CreateMessage for application/json ContentType.
 type CreateMessageJSONRequestBody = CreateMessagesRequest
 
+// CountMessageTokensJSONRequestBody defines body for CountMessageTokens for application/json ContentType.
+type CountMessageTokensJSONRequestBody = MessagesCountTokensRequest
+
 // PushMetricsJSONRequestBody defines body for PushMetrics for application/json ContentType.
 type PushMetricsJSONRequestBody = PushMetricsJSONBody
 
@@ -3271,6 +3444,9 @@ type ProxyPutJSONRequestBody ProxyPutJSONBody
 // CreateResponseJSONRequestBody defines body for CreateResponse for application/json ContentType.
 type CreateResponseJSONRequestBody = CreateResponseRequest
 
+// TokenizeJSONRequestBody defines body for Tokenize for application/json ContentType.
+type TokenizeJSONRequestBody = TokenizeRequest
+
 // Getter for additional properties for ToolCallExtraContent_Google. Returns the specified
 // element and whether it was found
 func (a ToolCallExtraContent_Google) Get(fieldName string) (value any, found bool) {
@@ -3737,6 +3913,68 @@ func (t *MessageContent) UnmarshalJSON(b []byte) error {
 	return err
 }
 
+// AsMessagesCountTokensRequestSystem0 returns the union data inside the MessagesCountTokensRequest_System as a MessagesCountTokensRequestSystem0
+func (t MessagesCountTokensRequest_System) AsMessagesCountTokensRequestSystem0() (MessagesCountTokensRequestSystem0, error) {
+	var body MessagesCountTokensRequestSystem0
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromMessagesCountTokensRequestSystem0 overwrites any union data inside the MessagesCountTokensRequest_System as the provided MessagesCountTokensRequestSystem0
+func (t *MessagesCountTokensRequest_System) FromMessagesCountTokensRequestSystem0(v MessagesCountTokensRequestSystem0) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeMessagesCountTokensRequestSystem0 performs a merge with any union data inside the MessagesCountTokensRequest_System, using the provided MessagesCountTokensRequestSystem0
+func (t *MessagesCountTokensRequest_System) MergeMessagesCountTokensRequestSystem0(v MessagesCountTokensRequestSystem0) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsMessagesCountTokensRequestSystem1 returns the union data inside the MessagesCountTokensRequest_System as a MessagesCountTokensRequestSystem1
+func (t MessagesCountTokensRequest_System) AsMessagesCountTokensRequestSystem1() (MessagesCountTokensRequestSystem1, error) {
+	var body MessagesCountTokensRequestSystem1
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromMessagesCountTokensRequestSystem1 overwrites any union data inside the MessagesCountTokensRequest_System as the provided MessagesCountTokensRequestSystem1
+func (t *MessagesCountTokensRequest_System) FromMessagesCountTokensRequestSystem1(v MessagesCountTokensRequestSystem1) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeMessagesCountTokensRequestSystem1 performs a merge with any union data inside the MessagesCountTokensRequest_System, using the provided MessagesCountTokensRequestSystem1
+func (t *MessagesCountTokensRequest_System) MergeMessagesCountTokensRequestSystem1(v MessagesCountTokensRequestSystem1) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t MessagesCountTokensRequest_System) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *MessagesCountTokensRequest_System) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
 // AsMessagesMessageContent0 returns the union data inside the MessagesMessage_Content as a MessagesMessageContent0
 func (t MessagesMessage_Content) AsMessagesMessageContent0() (MessagesMessageContent0, error) {
 	var body MessagesMessageContent0
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	gin "github.com/gin-gonic/gin"
+
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+func TestChatCompletionsAdmission_DisallowedModelIsNotServedFromCache(t *testing.T) {
+	upstreamCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4o","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	mockClient := providersmocks.NewMockClient(ctrl)
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(http.DefaultClient.Do).AnyTimes()
+
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+
+	providerCfg := map[types.Provider]*registry.ProviderConfig{
+		constants.OpenaiID: {
+			ID:        constants.OpenaiID,
+			Name:      constants.OpenaiDisplayName,
+			URL:       server.URL,
+			Token:     "test-openai-key",
+			AuthType:  constants.AuthTypeBearer,
+			Endpoints: registry.Registry[constants.OpenaiID].Endpoints,
+		},
+	}
+	cfg := config.Config{
+		Server: &config.ServerConfig{
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		},
+		Cache: &config.CacheConfig{
+			Enabled:    true,
+			Backend:    "memory",
+			Ttl:        time.Minute,
+			MaxEntries: 10,
+		},
+		Providers: providerCfg,
+	}
+
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, nil, nil, nil)
+	responseCache, err := middlewares.NewResponseCacheMiddleware(cfg, nil, nil, nil, log)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(middlewares.NewRequestBodyMiddleware(cfg).Middleware())
+	r.Use(router.ChatCompletionsAdmission())
+	r.Use(responseCache.Middleware())
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+	post := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"openai/gpt-4o","temperature":0,"messages":[{"role":"user","content":"hi"}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, post().Code)
+	cached := post()
+	require.Equal(t, http.StatusOK, cached.Code)
+	require.Equal(t, middlewares.CacheStatusHit, cached.Header().Get(middlewares.CacheStatusHeader))
+
+	reloaded := cfg
+	reloaded.AllowedModels = "openai/gpt-4o-mini"
+	router.Reload(reloaded)
+
+	denied := post()
+	assert.Equal(t, http.StatusForbidden, denied.Code, denied.Body.String())
+	assert.Empty(t, denied.Header().Get(middlewares.CacheStatusHeader), "the cache must not be consulted for a disallowed model")
+	assert.Equal(t, 1, upstreamCalls)
+}
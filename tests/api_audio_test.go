@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	gin "github.com/gin-gonic/gin"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+)
+
+func enableAudio(cfg *config.Config) { cfg.EnableAudio = true }
+
+func TestAudioTranscriptionsHandler_HappyPath(t *testing.T) {
+	var gotPath, gotModel, gotFormat, gotFile string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		gotModel = r.FormValue("model")
+		gotFormat = r.FormValue("response_format")
+		gotFile = readUploadedFile(t, r, "file")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("Hello from the recording.\n"))
+	}))
+	defer server.Close()
+
+	router := newImagesTestRouter(t, server.URL, false, enableAudio)
+	r := gin.New()
+	r.POST("/v1/audio/transcriptions", router.AudioTranscriptionsHandler)
+
+	body, contentType := buildImagesMultipart(t, []imagesMultipartField{
+		{name: "file", filename: "speech.mp3", value: "MP3-AUDIO-BYTES"},
+		{name: "model", value: "openai/whisper-1"},
+		{name: "response_format", value: "text"},
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/v1/audio/transcriptions", body)
+	req.Header.Set("Content-Type", contentType)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "/audio/transcriptions", gotPath)
+	assert.Equal(t, "whisper-1", gotModel)
+	assert.Equal(t, "text", gotFormat)
+	assert.Equal(t, "MP3-AUDIO-BYTES", gotFile)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Hello from the recording.\n", w.Body.String())
+}
+
+func TestAudioTranscriptionsHandler_Streaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hello\"}\n\n")
+		_, _ = io.WriteString(w, "data: {\"type\":\"transcript.text.done\",\"text\":\"Hello\"}\n\n")
+	}))
+	defer server.Close()
+
+	router := newImagesTestRouter(t, server.URL, false, enableAudio)
+	r := gin.New()
+	r.POST("/v1/audio/transcriptions", router.AudioTranscriptionsHandler)
+	gateway := httptest.NewServer(r)
+	defer gateway.Close()
+
+	body, contentType := buildImagesMultipart(t, []imagesMultipartField{
+		{name: "file", filename: "speech.mp3", value: "MP3-AUDIO-BYTES"},
+		{name: "model", value: "openai/gpt-4o-mini-transcribe"},
+		{name: "stream", value: "true"},
+	})
+	resp, err := http.Post(gateway.URL+"/v1/audio/transcriptions", contentType, body)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	events, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(events))
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"))
+	assert.Contains(t, string(events), `"transcript.text.delta"`)
+	assert.Contains(t, string(events), `"transcript.text.done"`)
+}
+
+func TestAudioTranslationsHandler_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected upstream request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		opts   []func(*config.Config)
+		fields []imagesMultipartField
+		status int
+		error  string
+	}{
+		{
+			name:   "disabled",
+			fields: []imagesMultipartField{{name: "file", filename: "speech.mp3", value: "MP3"}, {name: "model", value: "openai/whisper-1"}},
+			status: http.StatusNotFound,
+			error:  "The Audio API is not enabled. Set ENABLE_AUDIO=true to enable it.",
+		},
+		{
+			name:   "missing file",
+			opts:   []func(*config.Config){enableAudio},
+			fields: []imagesMultipartField{{name: "model", value: "openai/whisper-1"}},
+			status: http.StatusBadRequest,
+			error:  "The audio 'file' is required.",
+		},
+		{
+			name:   "provider without audio support",
+			opts:   []func(*config.Config){enableAudio},
+			fields: []imagesMultipartField{{name: "file", filename: "speech.mp3", value: "MP3"}, {name: "model", value: "cohere/whisper-1"}},
+			status: http.StatusBadRequest,
+			error:  "The Audio API is not supported by this provider yet.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newImagesTestRouter(t, server.URL, false, tt.opts...)
+			r := gin.New()
+			r.POST("/v1/audio/translations", router.AudioTranslationsHandler)
+
+			body, contentType := buildImagesMultipart(t, tt.fields)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/v1/audio/translations", body)
+			req.Header.Set("Content-Type", contentType)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			var resp core.OpenAIErrorBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.error, resp.Error.Message)
+			assert.Equal(t, string(core.ErrorCodeInvalidRequest), resp.Error.Code)
+		})
+	}
+}
+
+func TestAudioSpeechHandler_RelaysAudio(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte{0xff, 0xfb, 0x90, 0x64})
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte{0x00, 0x01})
+	}))
+	defer server.Close()
+
+	router := newImagesTestRouter(t, server.URL, false, enableAudio)
+	r := gin.New()
+	r.POST("/v1/audio/speech", router.AudioSpeechHandler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/v1/audio/speech", strings.NewReader(`{"model":"openai/gpt-4o-mini-tts","input":"Hello there","voice":"coral","speed":1.25}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "/audio/speech", gotPath)
+	assert.Equal(t, "gpt-4o-mini-tts", gotBody["model"])
+	assert.Equal(t, "coral", gotBody["voice"])
+	assert.Equal(t, 1.25, gotBody["speed"])
+	assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, []byte{0xff, 0xfb, 0x90, 0x64, 0x00, 0x01}, w.Body.Bytes())
+}
+
+func TestAudioSpeechHandler_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"Invalid voice","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		error  string
+	}{
+		{name: "missing input", body: `{"model":"openai/gpt-4o-mini-tts","voice":"coral"}`, status: http.StatusBadRequest, error: `{"error":{"message":"The 'input' field is required.","type":"invalid_request_error","code":"invalid_request","param":null}}`},
+		{name: "provider without audio support", body: `{"model":"cohere/tts","input":"Hi"}`, status: http.StatusBadRequest, error: `{"error":{"message":"The Audio API is not supported by this provider yet.","type":"invalid_request_error","code":"invalid_request","param":null}}`},
+		{name: "upstream error", body: `{"model":"openai/gpt-4o-mini-tts","input":"Hi","voice":"nobody"}`, status: http.StatusBadRequest, error: `{"error":{"message":"Invalid voice","type":"invalid_request_error"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newImagesTestRouter(t, server.URL, false, enableAudio)
+			r := gin.New()
+			r.POST("/v1/audio/speech", router.AudioSpeechHandler)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/v1/audio/speech", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, tt.error, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	gin "github.com/gin-gonic/gin"
+
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	shaping "github.com/inference-gateway/inference-gateway/internal/shaping"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// newCompletionsGateway serves /v1/completions with llamacpp (which has a
+// native completions API) and groq (which only has chat completions) both
+// pointing at upstreamURL
+func newCompletionsGateway(t *testing.T, upstreamURL string, configure ...func(*config.Config)) *httptest.Server {
+	t.Helper()
+	return newAdmittingCompletionsGateway(t, upstreamURL, nil, nil, configure...)
+}
+
+// newAdmittingCompletionsGateway is newCompletionsGateway with a model
+// selector and request shaper, for the admission rules completions share with
+// chat completions
+func newAdmittingCompletionsGateway(t *testing.T, upstreamURL string, sel *routing.Selector, shaper *shaping.Shaper, configure ...func(*config.Config)) *httptest.Server {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	mockClient := providersmocks.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			return http.DefaultClient.Do(req)
+		}).
+		AnyTimes()
+
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+
+	providerCfg := map[types.Provider]*registry.ProviderConfig{}
+	for _, id := range []types.Provider{constants.LlamacppID, constants.GroqID} {
+		providerCfg[id] = &registry.ProviderConfig{
+			ID:        id,
+			Name:      registry.Registry[id].Name,
+			URL:       upstreamURL,
+			Token:     "test-" + string(id) + "-key",
+			AuthType:  constants.AuthTypeBearer,
+			Endpoints: registry.Registry[id].Endpoints,
+		}
+	}
+	cfg := config.Config{
+		Server: &config.ServerConfig{
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		},
+		Providers: providerCfg,
+	}
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, nil, sel, shaper)
+	r := gin.New()
+	r.Use(middlewares.NewRequestBodyMiddleware(cfg).Middleware())
+	r.POST("/v1/completions", router.CompletionsHandler)
+	gateway := httptest.NewServer(r)
+	t.Cleanup(gateway.Close)
+	return gateway
+}
+
+func postCompletion(t *testing.T, gateway *httptest.Server, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(gateway.URL+"/v1/completions", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestCompletionsHandler_RelaysNativeCompletions(t *testing.T) {
+	const upstreamResponse = `{"id":"cmpl-1","object":"text_completion","created":1742165657,"model":"qwen2.5-coder","choices":[{"text":"return a + b","index":0,"logprobs":null,"finish_reason":"stop"}]}`
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(upstreamResponse))
+	}))
+	defer server.Close()
+
+	status, body := postCompletion(t, newCompletionsGateway(t, server.URL), `{"model":"llamacpp/qwen2.5-coder","prompt":"def add(a, b):\n    ","suffix":"\n\nprint(add(1, 2))","max_tokens":16}`)
+
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, "/completions", gotPath)
+	assert.Equal(t, "Bearer test-llamacpp-key", gotAuth)
+	assert.Equal(t, map[string]any{
+		"model":      "qwen2.5-coder",
+		"prompt":     "def add(a, b):\n    ",
+		"suffix":     "\n\nprint(add(1, 2))",
+		"max_tokens": float64(16),
+	}, gotBody, "the body is relayed as-is, with the provider prefix stripped")
+	assert.JSONEq(t, upstreamResponse, body)
+}
+
+func TestCompletionsHandler_AdaptsChatCompletions(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":1742165657,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"message":{"role":"assistant","content":"Paris."},"finish_reason":"stop"}],"usage":{"prompt_tokens":7,"completion_tokens":2,"total_tokens":9}}`))
+	}))
+	defer server.Close()
+
+	status, body := postCompletion(t, newCompletionsGateway(t, server.URL), `{"model":"groq/llama-3.3-70b-versatile","prompt":["The capital of France is"],"max_tokens":8,"stop":"\n"}`)
+
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, "/chat/completions", gotPath)
+	assert.Equal(t, "llama-3.3-70b-versatile", gotBody["model"])
+	assert.Equal(t, []any{map[string]any{"role": "user", "content": "The capital of France is"}}, gotBody["messages"])
+	assert.Equal(t, float64(8), gotBody["max_tokens"])
+	assert.Equal(t, "\n", gotBody["stop"])
+	assert.JSONEq(t, `{
+		"id": "chatcmpl-1",
+		"object": "text_completion",
+		"created": 1742165657,
+		"model": "llama-3.3-70b-versatile",
+		"choices": [{"text": "Paris.", "index": 0, "logprobs": null, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 7, "completion_tokens": 2, "total_tokens": 9}
+	}`, body)
+}
+
+func TestCompletionsHandler_AdaptsChatCompletionStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1742165657,\"model\":\"llama-3.3-70b-versatile\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Par\"},\"finish_reason\":null}]}\n\n")
+		_, _ = io.WriteString(w, "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1742165657,\"model\":\"llama-3.3-70b-versatile\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"is.\"},\"finish_reason\":\"stop\"}]}\n\n")
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	status, body := postCompletion(t, newCompletionsGateway(t, server.URL), `{"model":"groq/llama-3.3-70b-versatile","prompt":"The capital of France is","stream":true}`)
+
+	require.Equal(t, http.StatusOK, status, body)
+	var texts []string
+	var finishReasons []any
+	for line := range strings.SplitSeq(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk map[string]any
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk), data)
+		assert.Equal(t, "text_completion", chunk["object"])
+		for _, choice := range chunk["choices"].([]any) {
+			choice := choice.(map[string]any)
+			texts = append(texts, choice["text"].(string))
+			finishReasons = append(finishReasons, choice["finish_reason"])
+		}
+	}
+	assert.Equal(t, []string{"Par", "is."}, texts)
+	assert.Equal(t, []any{nil, "stop"}, finishReasons)
+	assert.True(t, strings.HasSuffix(body, "data: [DONE]\n\n"), body)
+}
+
+func TestCompletionsHandler_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected upstream request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+	gateway := newCompletionsGateway(t, server.URL)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		error  string
+	}{
+		{name: "missing prompt", body: `{"model":"groq/llama-3.3-70b-versatile"}`, status: http.StatusBadRequest, error: "The 'prompt' field is required."},
+		{name: "batched prompt without native completions", body: `{"model":"groq/llama-3.3-70b-versatile","prompt":["a","b"]}`, status: http.StatusBadRequest, error: "This provider has no native completions API: 'prompt' must be a single string."},
+		{name: "token prompt without native completions", body: `{"model":"groq/llama-3.3-70b-versatile","prompt":[1,2,3]}`, status: http.StatusBadRequest, error: "This provider has no native completions API: 'prompt' must be a single string."},
+		{name: "suffix without native completions", body: `{"model":"groq/llama-3.3-70b-versatile","prompt":"def add(a, b):","suffix":"return"}`, status: http.StatusBadRequest, error: "This provider has no native completions API: the 'suffix' field is not supported."},
+		{name: "echo without native completions", body: `{"model":"groq/llama-3.3-70b-versatile","prompt":"hello","echo":true}`, status: http.StatusBadRequest, error: "This provider has no native completions API: the 'echo' field is not supported."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := postCompletion(t, gateway, tt.body)
+			assert.Equal(t, tt.status, status)
+			var resp core.OpenAIErrorBody
+			require.NoError(t, json.Unmarshal([]byte(body), &resp), body)
+			assert.Equal(t, tt.error, resp.Error.Message)
+			assert.Equal(t, string(core.ErrorCodeInvalidRequest), resp.Error.Code)
+		})
+	}
+}
+
+// Completions adapted to chat completions go through the same admission as
+// chat completions: the alias is routed by the selector and the resolved
+// request is shaped before it reaches the provider.
+func TestCompletionsHandler_AdmitsAdaptedChatCompletions(t *testing.T) {
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":1742165657,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"message":{"role":"assistant","content":"Paris."},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	shaper, err := shaping.NewShaper(&shaping.Config{Rules: []shaping.Rule{
+		{Model: "fast", Force: map[string]any{"seed": 7}},
+		{Model: "groq/*", Clamp: map[string]shaping.Range{"max_tokens": {Max: ptr(4.0)}}},
+	}})
+	require.NoError(t, err)
+	sel := routingSelector(t, "fast",
+		routing.Deployment{Provider: "groq", Model: "llama-3.3-70b-versatile"},
+		routing.Deployment{Provider: "groq", Model: "llama-3.3-70b-versatile"},
+	)
+	gateway := newAdmittingCompletionsGateway(t, server.URL, sel, shaper)
+
+	resp, err := http.Post(gateway.URL+"/v1/completions", "application/json", strings.NewReader(`{"model":"fast","prompt":"The capital of France is","max_tokens":8}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+	assert.Equal(t, "groq", resp.Header.Get("X-Selected-Provider"))
+	assert.Equal(t, "llama-3.3-70b-versatile", resp.Header.Get("X-Selected-Model"))
+	assert.Equal(t, "llama-3.3-70b-versatile", gotBody["model"])
+	assert.Equal(t, float64(7), gotBody["seed"])
+	assert.Equal(t, float64(4), gotBody["max_tokens"])
+}
+
+func TestCompletionsHandler_AdmissionRejections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected upstream request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		configure func(*config.Config)
+		body      string
+		status    int
+		code      core.ErrorCode
+		error     string
+	}{
+		{
+			name:      "disallowed model",
+			configure: func(cfg *config.Config) { cfg.DisallowedModels = "groq/llama-3.3-70b-versatile" },
+			body:      `{"model":"groq/llama-3.3-70b-versatile","prompt":"hello"}`,
+			status:    http.StatusForbidden,
+			code:      core.ErrorCodeInvalidRequest,
+			error:     "Model is disallowed. Please use a different model.",
+		},
+		{
+			name:      "context window exceeded",
+			configure: func(cfg *config.Config) { cfg.EnforceContextWindow = true },
+			body:      `{"model":"groq/llama-3.3-70b-versatile","prompt":"hello","max_tokens":200000}`,
+			status:    http.StatusBadRequest,
+			code:      core.ErrorCodeContextLength,
+		},
+		{
+			name:      "body too large",
+			configure: func(cfg *config.Config) { cfg.Server.MaxRequestBodySize = 16 },
+			body:      `{"model":"groq/llama-3.3-70b-versatile","prompt":"hello"}`,
+			status:    http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := postCompletion(t, newCompletionsGateway(t, server.URL, tt.configure), tt.body)
+			require.Equal(t, tt.status, status, body)
+			if tt.code == "" {
+				return
+			}
+			var resp core.OpenAIErrorBody
+			require.NoError(t, json.Unmarshal([]byte(body), &resp), body)
+			assert.Equal(t, string(tt.code), resp.Error.Code)
+			if tt.error != "" {
+				assert.Equal(t, tt.error, resp.Error.Message)
+			}
+		})
+	}
+}
+
+// The native relay forwards the client's query string, minus the gateway's
+// own provider parameter.
+func TestCompletionsHandler_RelayKeepsQuery(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"cmpl-1","object":"text_completion","created":1742165657,"model":"qwen2.5-coder","choices":[]}`))
+	}))
+	defer server.Close()
+	gateway := newCompletionsGateway(t, server.URL)
+
+	resp, err := http.Post(gateway.URL+"/v1/completions?provider=llamacpp&api-version=2024-10-21", "application/json", strings.NewReader(`{"model":"qwen2.5-coder","prompt":"hello"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+	assert.Equal(t, "api-version=2024-10-21", gotQuery)
+}
@@ -105,14 +105,14 @@ func BenchmarkListModelsContextWindow1000ProviderModels(b *testing.B) {
 	payload := mockModelsPayload(b, numModels, map[string]any{"max_context_length": 32768})
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/proxy/mistral/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/mistral/models", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write(payload)
 	})
 	server := httptest.NewServer(mux)
 	defer server.Close()
 
-	r := newContextWindowRouter(b, server, contextWindowProviderConfig(server.URL, constants.MistralID))
+	r := newContextWindowRouter(b, contextWindowProviderConfig(server.URL, constants.MistralID))
 	benchmarkModelsEndpoint(b, r, numModels)
 }
 
@@ -124,7 +124,7 @@ func BenchmarkListModelsContextWindow1000RuntimeModels(b *testing.B) {
 	payload := mockModelsPayload(b, numModels, nil)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/proxy/ollama/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/ollama/models", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write(payload)
 	})
@@ -135,6 +135,6 @@ func BenchmarkListModelsContextWindow1000RuntimeModels(b *testing.B) {
 	server := httptest.NewServer(mux)
 	defer server.Close()
 
-	r := newContextWindowRouter(b, server, contextWindowProviderConfig(server.URL, constants.OllamaID))
+	r := newContextWindowRouter(b, contextWindowProviderConfig(server.URL, constants.OllamaID))
 	benchmarkModelsEndpoint(b, r, numModels)
 }
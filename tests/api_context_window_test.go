@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,10 +22,9 @@ import (
 	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
 )
 
-// newContextWindowRouter builds a models router whose mock client forwards
-// every request to the given test server: self-proxy calls (relative URLs) by
-// path, runtime lookups (absolute URLs pointing at the server) as-is.
-func newContextWindowRouter(t testing.TB, server *httptest.Server, providerCfg map[types.Provider]*registry.ProviderConfig) *gin.Engine {
+// newContextWindowRouter builds a models router whose mock client sends every
+// request as-is; provider URLs in providerCfg point at the test server.
+func newContextWindowRouter(t testing.TB, providerCfg map[types.Provider]*registry.ProviderConfig) *gin.Engine {
 	t.Helper()
 
 	ctrl := gomock.NewController(t)
@@ -33,14 +33,7 @@ func newContextWindowRouter(t testing.TB, server *httptest.Server, providerCfg m
 	mockClient.EXPECT().
 		Do(gomock.Any()).
 		DoAndReturn(func(req *http.Request) (*http.Response, error) {
-			if req.URL.Host != "" {
-				return http.DefaultClient.Do(req)
-			}
-			fwd, err := http.NewRequest(req.Method, server.URL+req.URL.Path, req.Body)
-			if err != nil {
-				return nil, err
-			}
-			return http.DefaultClient.Do(fwd)
+			return http.DefaultClient.Do(req)
 		}).
 		AnyTimes()
 
@@ -54,7 +47,7 @@ func newContextWindowRouter(t testing.TB, server *httptest.Server, providerCfg m
 		},
 		Providers: providerCfg,
 	}
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
 
 	r := gin.New()
 	r.GET("/v1/models", router.ListModelsHandler)
@@ -73,7 +66,7 @@ func contextWindowProviderConfig(serverURL string, providers ...types.Provider)
 		cfg[id] = &registry.ProviderConfig{
 			ID:       id,
 			Name:     string(id),
-			URL:      serverURL,
+			URL:      serverURL + "/" + string(id),
 			Token:    token,
 			AuthType: authType,
 			Endpoints: types.Endpoints{
@@ -108,25 +101,25 @@ func TestListModelsHandler_ContextWindowResolution(t *testing.T) {
 		_, _ = w.Write([]byte(payload))
 	}
 
-	mux.HandleFunc("/proxy/llamacpp/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/llamacpp/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"qwen3-coder","object":"model","created":1750000000,"owned_by":"qwen","max_context_length":131072}]}`)
 	})
 	mux.HandleFunc("/props", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"default_generation_settings":{"n_ctx":32768}}`)
 	})
 
-	mux.HandleFunc("/proxy/ollama/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/ollama/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"llama3","object":"model","created":1750000000,"owned_by":"meta"}]}`)
 	})
 	mux.HandleFunc("/api/show", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"parameters":"num_ctx 8192\nstop \"<|end|>\"","model_info":{"llama.context_length":131072}}`)
 	})
 
-	mux.HandleFunc("/proxy/mistral/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/mistral/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"mistral-large","object":"model","created":1750000000,"owned_by":"mistralai","max_context_length":32768}]}`)
 	})
 
-	mux.HandleFunc("/proxy/openai/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/openai/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"gpt-4","object":"model","created":1750000000,"owned_by":"openai"},{"id":"gpt-nonexistent","object":"model","created":1750000000,"owned_by":"openai"}]}`)
 	})
 
@@ -135,7 +128,7 @@ func TestListModelsHandler_ContextWindowResolution(t *testing.T) {
 
 	providerCfg := contextWindowProviderConfig(server.URL,
 		constants.LlamacppID, constants.OllamaID, constants.MistralID, constants.OpenaiID)
-	r := newContextWindowRouter(t, server, providerCfg)
+	r := newContextWindowRouter(t, providerCfg)
 
 	t.Run("include resolves runtime, provider, community, and null windows", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -200,7 +193,7 @@ func TestListModelsHandler_ContextWindowResolution(t *testing.T) {
 
 func TestListModelsHandler_ContextWindowLookupFailure(t *testing.T) {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/proxy/llamacpp/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/llamacpp/models", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"qwen3-coder","object":"model","created":1750000000,"owned_by":"qwen"}]}`))
 	})
@@ -212,7 +205,7 @@ func TestListModelsHandler_ContextWindowLookupFailure(t *testing.T) {
 	defer server.Close()
 
 	providerCfg := contextWindowProviderConfig(server.URL, constants.LlamacppID)
-	r := newContextWindowRouter(t, server, providerCfg)
+	r := newContextWindowRouter(t, providerCfg)
 
 	w := httptest.NewRecorder()
 	req, err := http.NewRequest("GET", "/v1/models?include=context_window", nil)
@@ -228,3 +221,51 @@ func TestListModelsHandler_ContextWindowLookupFailure(t *testing.T) {
 	require.True(t, exists, "context_window key should be present")
 	assert.Nil(t, val, "unresolved window should be an explicit null")
 }
+
+func TestChatCompletionsHandler_EnforceRuntimeContextWindow(t *testing.T) {
+	propsCalls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/props", func(w http.ResponseWriter, r *http.Request) {
+		propsCalls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"default_generation_settings":{"n_ctx":512}}`))
+	})
+	mux.HandleFunc("/llamacpp/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"my-finetune","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	mockClient := providersmocks.NewMockClient(ctrl)
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(http.DefaultClient.Do).AnyTimes()
+
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+	providerCfg := contextWindowProviderConfig(server.URL, constants.LlamacppID)
+	providerCfg[constants.LlamacppID].Endpoints = registry.Registry[constants.LlamacppID].Endpoints
+	// The model catalog cache is disabled: the window comes from the runtime
+	cfg := config.Config{
+		Server:               &config.ServerConfig{ReadTimeout: 5 * time.Second, WriteTimeout: 5 * time.Second},
+		Providers:            providerCfg,
+		EnforceContextWindow: true,
+	}
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, nil, nil, nil)
+	r := gin.New()
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+	chat := func(prompt string) *httptest.ResponseRecorder {
+		return postJSON(t, r, "/v1/chat/completions", types.CreateChatCompletionRequest{
+			Model:    "llamacpp/my-finetune",
+			Messages: []types.Message{types.NewTextMessage(t, types.User, prompt)},
+		})
+	}
+
+	w := chat("hello world")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = chat(strings.Repeat("hello ", 1000))
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "maximum context length is 512 tokens")
+	assert.Equal(t, 1, propsCalls, "the runtime window is memoized")
+}
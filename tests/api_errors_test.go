@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	gomock "go.uber.org/mock/gomock"
+
+	gin "github.com/gin-gonic/gin"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+)
+
+// rateLimitedUpstream answers every request with a rate limit error in the
+// envelope of the provider
+func rateLimitedUpstream(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "7")
+		w.Header().Set("X-Request-Id", "req_upstream_1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestChatCompletionsHandler_MapsUpstreamErrors(t *testing.T) {
+	server := rateLimitedUpstream(t, `{"error":{"message":"Rate limit reached for gpt-4o","type":"requests","code":"rate_limit_exceeded"}}`)
+
+	router := newMessagesTestRouter(t, server.URL)
+	r := gin.New()
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+	for _, stream := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, chatRequest(t, "openai/gpt-4o", stream))
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "7", w.Header().Get("Retry-After"))
+		assert.Equal(t, "req_upstream_1", w.Header().Get(core.UpstreamRequestIDHeader))
+		assert.JSONEq(t, `{"error":{"message":"Rate limit reached for gpt-4o","type":"rate_limit_error","code":"rate_limit_exceeded","param":null}}`, w.Body.String())
+	}
+}
+
+func TestMessagesHandler_RelaysUpstreamErrorHeaders(t *testing.T) {
+	body := `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`
+	server := rateLimitedUpstream(t, body)
+
+	router := newMessagesTestRouter(t, server.URL)
+	r := gin.New()
+	r.POST("/v1/messages", router.MessagesHandler)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/v1/messages", strings.NewReader(`{"model":"anthropic/claude-sonnet-4-5","max_tokens":16,"messages":[{"role":"user","content":"Hello"}]}`))
+	require.NoError(t, err)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "7", w.Header().Get("Retry-After"))
+	assert.Equal(t, "req_upstream_1", w.Header().Get(core.UpstreamRequestIDHeader))
+	assert.JSONEq(t, body, w.Body.String(), "Anthropic errors are relayed in their own envelope")
+}
+
+func TestMessagesHandler_UnreachableUpstream(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	router := newMessagesTestRouter(t, server.URL)
+	r := gin.New()
+	r.POST("/v1/messages", router.MessagesHandler)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/v1/messages", strings.NewReader(`{"model":"anthropic/claude-sonnet-4-5","max_tokens":16,"messages":[{"role":"user","content":"Hello"}]}`))
+	require.NoError(t, err)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	var response core.AnthropicErrorBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "error", response.Type)
+	assert.Equal(t, "api_error", response.Error.Type)
+	assert.Equal(t, "Failed to reach upstream server", response.Error.Message)
+}
+
+// Requests rejected by the gateway itself get the same OpenAI error envelope
+// as upstream failures.
+func TestChatCompletionsHandler_GatewayErrorEnvelopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  string
+		prompt   string
+		status   int
+		expected core.OpenAIError
+	}{
+		{
+			name:     "DisallowedModel",
+			allowed:  "openai/gpt-4o",
+			prompt:   "hello",
+			status:   http.StatusForbidden,
+			expected: core.OpenAIError{Message: "Model not allowed. Please check the list of allowed models.", Type: "invalid_request_error", Code: "invalid_request"},
+		},
+		{
+			// openai/gpt-4 has an 8192 token window in the community table
+			name:     "ContextWindowExceeded",
+			prompt:   strings.Repeat("hello ", 9000),
+			status:   http.StatusBadRequest,
+			expected: core.OpenAIError{Message: "This model's maximum context length is 8192 tokens, however the messages take 9008 tokens. Please reduce the length of the messages.", Type: "invalid_request_error", Code: "context_length_exceeded"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			log, cfg := routingTestSetup(t)
+			cfg.AllowedModels = tt.allowed
+			cfg.EnforceContextWindow = true
+
+			mockClient := providersmocks.NewMockClient(ctrl)
+			reg := providersmocks.NewMockProviderRegistry(ctrl)
+			reg.EXPECT().BuildProvider(constants.OpenaiID, mockClient).Return(providersmocks.NewMockIProvider(ctrl), nil).AnyTimes()
+
+			router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
+			r := gin.New()
+			r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+			w := postJSON(t, r, "/v1/chat/completions", types.CreateChatCompletionRequest{
+				Model:    "openai/gpt-4",
+				Messages: []types.Message{types.NewTextMessage(t, types.User, tt.prompt)},
+			})
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+			var body core.OpenAIErrorBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expected, body.Error)
+		})
+	}
+}
+
+func TestGatewayRejections_ErrorEnvelopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected upstream request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	chat := func(router api.Router) gin.HandlerFunc { return router.ChatCompletionsHandler }
+	moderations := func(router api.Router) gin.HandlerFunc { return router.ModerationsHandler }
+
+	tests := []struct {
+		name     string
+		path     string
+		handler  func(api.Router) gin.HandlerFunc
+		body     string
+		opts     []func(*config.Config)
+		status   int
+		expected core.OpenAIError
+	}{
+		{
+			name:     "DecodeFailure",
+			path:     "/v1/chat/completions",
+			handler:  chat,
+			body:     `{"model":`,
+			status:   http.StatusBadRequest,
+			expected: core.OpenAIError{Message: "Failed to decode request", Type: "invalid_request_error", Code: "invalid_request"},
+		},
+		{
+			name:     "UndeterminedProvider",
+			path:     "/v1/chat/completions",
+			handler:  chat,
+			body:     `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`,
+			status:   http.StatusBadRequest,
+			expected: core.OpenAIError{Message: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/gpt-4).", Type: "invalid_request_error", Code: "invalid_request"},
+		},
+		{
+			name:     "UnknownProvider",
+			path:     "/v1/chat/completions",
+			handler:  chat,
+			body:     `{"model":"groq/llama-3.3-70b-versatile","messages":[{"role":"user","content":"Hi"}]}`,
+			status:   http.StatusBadRequest,
+			expected: core.OpenAIError{Message: "Provider not found. Please check the list of supported providers.", Type: "invalid_request_error", Code: "provider_not_configured"},
+		},
+		{
+			name:    "MissingAPIKey",
+			path:    "/v1/chat/completions",
+			handler: chat,
+			body:    `{"model":"openai/gpt-4o","messages":[{"role":"user","content":"Hi"}]}`,
+			opts: []func(*config.Config){func(cfg *config.Config) {
+				cfg.Providers[constants.OpenaiID].Token = ""
+			}},
+			status:   http.StatusBadRequest,
+			expected: core.OpenAIError{Message: "Provider requires an API key. Please configure the provider's API key.", Type: "invalid_request_error", Code: "provider_not_configured"},
+		},
+		{
+			name:     "UnsupportedAPI",
+			path:     "/v1/moderations",
+			handler:  moderations,
+			body:     `{"model":"cohere/command-r","input":"Hi"}`,
+			status:   http.StatusBadRequest,
+			expected: core.OpenAIError{Message: "The Moderations API is not supported by this provider yet.", Type: "invalid_request_error", Code: "invalid_request"},
+		},
+		{
+			name:    "UnsupportedAuthType",
+			path:    "/v1/moderations",
+			handler: moderations,
+			body:    `{"model":"openai/omni-moderation-latest","input":"Hi"}`,
+			opts: []func(*config.Config){func(cfg *config.Config) {
+				cfg.Providers[constants.OpenaiID].AuthType = "signature"
+			}},
+			status:   http.StatusUnprocessableEntity,
+			expected: core.OpenAIError{Message: "Unsupported auth type", Type: "invalid_request_error", Code: "provider_not_configured"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newImagesTestRouter(t, server.URL, false, tt.opts...)
+			r := gin.New()
+			r.POST(tt.path, tt.handler(router))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+			var body core.OpenAIErrorBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expected, body.Error)
+		})
+	}
+}
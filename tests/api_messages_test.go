@@ -19,13 +19,14 @@ import (
 
 	api "github.com/inference-gateway/inference-gateway/api"
 	config "github.com/inference-gateway/inference-gateway/config"
+	tokens "github.com/inference-gateway/inference-gateway/internal/tokens"
 	logger "github.com/inference-gateway/inference-gateway/logger"
 	constants "github.com/inference-gateway/inference-gateway/providers/constants"
 	registry "github.com/inference-gateway/inference-gateway/providers/registry"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
-func newMessagesTestRouter(t *testing.T, upstreamURL string) api.Router {
+func newMessagesTestRouter(t *testing.T, upstreamURL string, configure ...func(*config.Config)) api.Router {
 	t.Helper()
 	ctrl := gomock.NewController(t)
 	t.Cleanup(ctrl.Finish)
@@ -68,8 +69,11 @@ func newMessagesTestRouter(t *testing.T, upstreamURL string) api.Router {
 		},
 		Providers: providerCfg,
 	}
+	for _, fn := range configure {
+		fn(&cfg)
+	}
 
-	return api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, nil, nil)
+	return api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, nil, nil, nil)
 }
 
 func TestMessagesHandler_NonStreamingPassthrough(t *testing.T) {
@@ -187,3 +191,95 @@ func TestMessagesHandler_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestMessagesCountTokensHandler_AnthropicPassthrough(t *testing.T) {
+	var upstreamBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/messages/count_tokens", r.URL.Path)
+		assert.Equal(t, "test-anthropic-key", r.Header.Get("x-api-key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&upstreamBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"input_tokens":14}`))
+	}))
+	defer server.Close()
+
+	router := newMessagesTestRouter(t, server.URL)
+	r := gin.New()
+	r.POST("/v1/messages/count_tokens", router.MessagesCountTokensHandler)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/v1/messages/count_tokens", strings.NewReader(`{"model":"anthropic/claude-sonnet-4-5","messages":[{"role":"user","content":"Hello"}]}`))
+	require.NoError(t, err)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "claude-sonnet-4-5", upstreamBody["model"], "provider prefix should be stripped")
+	assert.JSONEq(t, `{"input_tokens":14}`, w.Body.String())
+}
+
+func TestMessagesCountTokensHandler_LocalEstimate(t *testing.T) {
+	router := newMessagesTestRouter(t, "http://localhost:0")
+	r := gin.New()
+	r.POST("/v1/messages/count_tokens", router.MessagesCountTokensHandler)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/v1/messages/count_tokens", strings.NewReader(`{"model":"openai/gpt-4o","system":"be brief","messages":[{"role":"user","content":"Hello"}]}`))
+	require.NoError(t, err)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response types.MessagesCountTokensResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	counter := tokens.ForModel("gpt-4o")
+	assert.Equal(t, 3+counter.Text("be brief")+3+counter.Text("user")+counter.Text("Hello"), response.InputTokens)
+}
+
+func TestMessagesCountTokensHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:           "Model not in ALLOWED_MODELS returns 403",
+			body:           `{"model":"openai/gpt-4","messages":[{"role":"user","content":"Hello"}]}`,
+			expectedStatus: http.StatusForbidden,
+			expectedMsg:    "Model not allowed",
+		},
+		{
+			name:           "Missing messages returns 400",
+			body:           `{"model":"openai/gpt-4o"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "messages: Field required",
+		},
+		{
+			name:           "Unknown provider prefix returns 400",
+			body:           `{"model":"claude-sonnet-4-5","messages":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Unable to determine provider for model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newMessagesTestRouter(t, "http://localhost:0", func(cfg *config.Config) {
+				cfg.AllowedModels = "openai/gpt-4o"
+			})
+			r := gin.New()
+			r.POST("/v1/messages/count_tokens", router.MessagesCountTokensHandler)
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("POST", "/v1/messages/count_tokens", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			var response types.MessagesError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "invalid_request_error", response.Error.Type)
+			assert.Contains(t, response.Error.Message, tt.expectedMsg)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+)
+
+// newModelCatalogRouter builds a models router with the model catalog cache
+// enabled; provider URLs in providerCfg point at the test server.
+func newModelCatalogRouter(t *testing.T, providerCfg map[types.Provider]*registry.ProviderConfig, ttl time.Duration) (*gin.Engine, api.Router) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockClient := providersmocks.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			return http.DefaultClient.Do(req)
+		}).
+		AnyTimes()
+
+	cfg := config.Config{
+		Server: &config.ServerConfig{
+			ReadTimeout: 5 * time.Second,
+		},
+		Cache: &config.CacheConfig{
+			ModelsEnabled: true,
+			ModelsTtl:     ttl,
+		},
+		Providers: providerCfg,
+	}
+	log := logger.NewNoopLogger()
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, nil, nil, nil)
+
+	r := gin.New()
+	r.GET("/v1/models", router.ListModelsHandler)
+	return r, router
+}
+
+// modelsUpstream serves a model list per provider path and counts the calls.
+// Setting fail makes every provider answer with an error.
+type modelsUpstream struct {
+	calls atomic.Int32
+	model atomic.Value // id of the single model listed
+	fail  atomic.Bool
+	delay atomic.Int64 // nanoseconds
+}
+
+func newModelsUpstream(t *testing.T) (*modelsUpstream, *httptest.Server) {
+	t.Helper()
+	u := &modelsUpstream{}
+	u.model.Store("model-a")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.calls.Add(1)
+		time.Sleep(time.Duration(u.delay.Load()))
+		if u.fail.Load() {
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"` + u.model.Load().(string) + `","object":"model","created":1750000000,"owned_by":"test"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return u, server
+}
+
+func getModels(t *testing.T, r http.Handler, query string) (int, types.ListModelsResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/v1/models"+query, nil)
+	require.NoError(t, err)
+	r.ServeHTTP(w, req)
+
+	var resp types.ListModelsResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+func modelIDs(resp types.ListModelsResponse) []string {
+	ids := make([]string, 0, len(resp.Data))
+	for _, model := range resp.Data {
+		ids = append(ids, model.ID)
+	}
+	return ids
+}
+
+func TestListModelsHandler_CatalogCache(t *testing.T) {
+	upstream, server := newModelsUpstream(t)
+	r, _ := newModelCatalogRouter(t, contextWindowProviderConfig(server.URL, constants.OpenaiID, constants.GroqID), time.Hour)
+
+	code, first := getModels(t, r, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []string{"openai/model-a", "groq/model-a"}, modelIDs(first))
+	require.NotNil(t, first.Providers)
+	require.Len(t, *first.Providers, 2)
+	for _, status := range *first.Providers {
+		assert.NotNil(t, status.LastSuccess, "provider %s", status.Provider)
+		assert.False(t, status.Stale)
+		assert.Nil(t, status.Error)
+	}
+	assert.Equal(t, int32(2), upstream.calls.Load())
+
+	upstream.model.Store("model-b")
+	_, second := getModels(t, r, "")
+	assert.ElementsMatch(t, []string{"openai/model-a", "groq/model-a"}, modelIDs(second), "fresh lists must be served from the cache")
+	assert.Equal(t, int32(2), upstream.calls.Load())
+
+	_, single := getModels(t, r, "?provider=groq")
+	assert.Equal(t, []string{"groq/model-a"}, modelIDs(single))
+	require.NotNil(t, single.Provider)
+	assert.Equal(t, constants.GroqID, *single.Provider)
+	assert.Equal(t, int32(2), upstream.calls.Load())
+
+	_, refreshed := getModels(t, r, "?refresh=true")
+	assert.ElementsMatch(t, []string{"openai/model-b", "groq/model-b"}, modelIDs(refreshed))
+	assert.Equal(t, int32(4), upstream.calls.Load())
+
+	code, _ = getModels(t, r, "?refresh=maybe")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestListModelsHandler_CatalogStaleWhileRevalidate(t *testing.T) {
+	upstream, server := newModelsUpstream(t)
+	r, _ := newModelCatalogRouter(t, contextWindowProviderConfig(server.URL, constants.OpenaiID), 50*time.Millisecond)
+
+	getModels(t, r, "")
+	upstream.model.Store("model-b")
+	delay := 200 * time.Millisecond
+	upstream.delay.Store(int64(delay))
+	time.Sleep(60 * time.Millisecond)
+
+	start := time.Now()
+	_, stale := getModels(t, r, "")
+	assert.Less(t, time.Since(start), delay, "a stale list must not wait on the provider")
+	assert.Equal(t, []string{"openai/model-a"}, modelIDs(stale))
+	require.Len(t, *stale.Providers, 1)
+	assert.True(t, (*stale.Providers)[0].Stale)
+
+	assert.Eventually(t, func() bool {
+		_, resp := getModels(t, r, "")
+		return len(resp.Data) == 1 && resp.Data[0].ID == "openai/model-b"
+	}, 2*time.Second, 20*time.Millisecond, "the stale list must be refreshed in the background")
+}
+
+func TestListModelsHandler_CatalogRefreshFailure(t *testing.T) {
+	upstream, server := newModelsUpstream(t)
+	r, _ := newModelCatalogRouter(t, contextWindowProviderConfig(server.URL, constants.OpenaiID), time.Hour)
+
+	_, first := getModels(t, r, "")
+	lastSuccess := (*first.Providers)[0].LastSuccess
+
+	upstream.fail.Store(true)
+	code, resp := getModels(t, r, "?provider=openai&refresh=true")
+	require.Equal(t, http.StatusOK, code, "the last good list must be served when a refresh fails")
+	assert.Equal(t, []string{"openai/model-a"}, modelIDs(resp))
+	status := (*resp.Providers)[0]
+	require.NotNil(t, status.Error)
+	assert.True(t, status.LastSuccess.Equal(*lastSuccess))
+
+	upstream.fail.Store(false)
+	_, resp = getModels(t, r, "?refresh=true")
+	assert.Nil(t, (*resp.Providers)[0].Error, "a successful refresh must clear the error")
+}
+
+func TestListModelsHandler_CatalogNeverListed(t *testing.T) {
+	upstream, server := newModelsUpstream(t)
+	upstream.fail.Store(true)
+	r, _ := newModelCatalogRouter(t, contextWindowProviderConfig(server.URL, constants.OpenaiID), time.Hour)
+
+	code, _ := getModels(t, r, "?provider=openai")
+	assert.Equal(t, http.StatusBadGateway, code)
+
+	code, resp := getModels(t, r, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Data)
+	status := (*resp.Providers)[0]
+	assert.Nil(t, status.LastSuccess)
+	assert.True(t, status.Stale)
+	assert.NotNil(t, status.Error)
+}
+
+func TestListModelsHandler_CatalogBackgroundRefresh(t *testing.T) {
+	upstream, server := newModelsUpstream(t)
+	r, router := newModelCatalogRouter(t, contextWindowProviderConfig(server.URL, constants.OpenaiID), time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router.StartModelRefresh(ctx)
+	require.Eventually(t, func() bool { return upstream.calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond,
+		"the catalog must be warmed up when refreshing starts")
+
+	_, resp := getModels(t, r, "")
+	assert.Equal(t, []string{"openai/model-a"}, modelIDs(resp))
+	assert.Equal(t, int32(1), upstream.calls.Load())
+
+	// A reload marks the lists stale so the new configuration is used next
+	router.Reload(config.Config{})
+	_, resp = getModels(t, r, "")
+	assert.True(t, (*resp.Providers)[0].Stale)
+	assert.Eventually(t, func() bool { return upstream.calls.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+}
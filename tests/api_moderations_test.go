@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	gin "github.com/gin-gonic/gin"
+
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+)
+
+func postModeration(t *testing.T, upstreamURL, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := gin.New()
+	r.POST("/v1/moderations", newImagesTestRouter(t, upstreamURL, false).ModerationsHandler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/v1/moderations", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestModerationsHandler_RelaysToProvider(t *testing.T) {
+	const upstreamResponse = `{"id":"modr-1","model":"omni-moderation-latest","results":[{"flagged":true,"categories":{"violence":true,"hate":false},"category_scores":{"violence":0.93,"hate":0.01}}]}`
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(upstreamResponse))
+	}))
+	defer server.Close()
+
+	w := postModeration(t, server.URL, `{"model":"openai/omni-moderation-latest","input":["first text","second text"]}`)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "/moderations", gotPath)
+	assert.Equal(t, "Bearer test-openai-key", gotAuth)
+	assert.Equal(t, map[string]any{
+		"model": "omni-moderation-latest",
+		"input": []any{"first text", "second text"},
+	}, gotBody)
+	assert.JSONEq(t, upstreamResponse, w.Body.String())
+}
+
+func TestModerationsHandler_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("upstream must not be called")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		error  string
+	}{
+		{name: "missing input", body: `{"model":"openai/omni-moderation-latest"}`, status: http.StatusBadRequest, error: "The 'input' field is required."},
+		{name: "provider without moderations support", body: `{"model":"cohere/command-r","input":"hello"}`, status: http.StatusBadRequest, error: "The Moderations API is not supported by this provider yet."},
+		{name: "unknown provider", body: `{"model":"omni-moderation-latest","input":"hello"}`, status: http.StatusBadRequest, error: "Unable to determine provider for model. Please specify a provider using the ?provider= query parameter or use the provider/model format (e.g., openai/omni-moderation-latest)."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postModeration(t, server.URL, tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			var resp core.OpenAIErrorBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.error, resp.Error.Message)
+			assert.Equal(t, string(core.ErrorCodeInvalidRequest), resp.Error.Code)
+		})
+	}
+}
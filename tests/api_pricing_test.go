@@ -19,27 +19,27 @@ func TestListModelsHandler_PricingResolution(t *testing.T) {
 		_, _ = w.Write([]byte(payload))
 	}
 
-	mux.HandleFunc("/proxy/deepseek/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/deepseek/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"deepseek-chat","object":"model","created":1750000000,"owned_by":"deepseek","pricing":{"prompt":"0.00000027","completion":"0.00000110","input_cache_read":"0.00000007","input_cache_write":"0.00000027"}}]}`)
 	})
 
-	mux.HandleFunc("/proxy/groq/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/groq/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"llama-3.3-70b","object":"model","created":1750000000,"owned_by":"meta","pricing":{"prompt":0.00000059,"completion":"0.00000079","input_cache_read":"0","input_cache_write":0}}]}`)
 	})
 
-	mux.HandleFunc("/proxy/openai/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/openai/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"gpt-4","object":"model","created":1750000000,"owned_by":"openai"},{"id":"gpt-nonexistent","object":"model","created":1750000000,"owned_by":"openai"}]}`)
 	})
 
-	mux.HandleFunc("/proxy/anthropic/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/anthropic/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"data":[{"id":"claude-sonnet-4-5-20250929","object":"model","created":1750000000,"owned_by":"anthropic"}]}`)
 	})
 
-	mux.HandleFunc("/proxy/nvidia/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/nvidia/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"meta/llama-3.1-8b-instruct","object":"model","created":1750000000,"owned_by":"meta"}]}`)
 	})
 
-	mux.HandleFunc("/proxy/ollama_cloud/models", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/ollama_cloud/models", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, `{"object":"list","data":[{"id":"deepseek-v4-pro","object":"model","created":1750000000,"owned_by":"ollama"}]}`)
 	})
 
@@ -49,7 +49,7 @@ func TestListModelsHandler_PricingResolution(t *testing.T) {
 	providerCfg := contextWindowProviderConfig(server.URL,
 		constants.DeepseekID, constants.GroqID, constants.OpenaiID, constants.AnthropicID, constants.NvidiaID,
 		constants.OllamaCloudID)
-	r := newContextWindowRouter(t, server, providerCfg)
+	r := newContextWindowRouter(t, providerCfg)
 
 	t.Run("include resolves provider, community, and null pricing", func(t *testing.T) {
 		w := httptest.NewRecorder()
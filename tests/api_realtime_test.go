@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	gin "github.com/gin-gonic/gin"
+	websocket "github.com/gorilla/websocket"
+
+	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// realtimeUpstream is a fake Realtime API: it checks the handshake of the
+// gateway, answers response.create with a response.done carrying usage and
+// records the other events it receives
+type realtimeUpstream struct {
+	*httptest.Server
+	handshakes chan *http.Request
+	events     chan string
+}
+
+func newRealtimeUpstream(t *testing.T) *realtimeUpstream {
+	t.Helper()
+	upstream := &realtimeUpstream{handshakes: make(chan *http.Request, 1), events: make(chan string, 10)}
+	upgrader := websocket.Upgrader{Subprotocols: []string{"realtime"}}
+	upstream.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-openai-key" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error","code":"invalid_api_key"}}`))
+			return
+		}
+		upstream.handshakes <- r
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if strings.Contains(string(data), `"response.create"`) {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"response.done","event_id":"event_2","response":{"status":"completed","usage":{"total_tokens":46,"input_tokens":12,"output_tokens":34}}}`))
+				continue
+			}
+			upstream.events <- string(data)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+func newRealtimeTestRouter(t *testing.T, upstreamURL string, telemetry *mocks.MockOpenTelemetry, configure ...func(*config.Config)) string {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+
+	providerCfg := map[types.Provider]*registry.ProviderConfig{
+		constants.OpenaiID: {
+			ID:       constants.OpenaiID,
+			Name:     constants.OpenaiDisplayName,
+			URL:      upstreamURL + "/v1",
+			Token:    "test-openai-key",
+			AuthType: constants.AuthTypeBearer,
+		},
+		constants.AnthropicID: {
+			ID:       constants.AnthropicID,
+			Name:     constants.AnthropicDisplayName,
+			URL:      upstreamURL,
+			Token:    "test-anthropic-key",
+			AuthType: constants.AuthTypeXheader,
+		},
+	}
+	cfg := config.Config{
+		Server: &config.ServerConfig{
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		},
+		Providers: providerCfg,
+	}
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+
+	var otelImpl otel.OpenTelemetry
+	if telemetry != nil {
+		otelImpl = telemetry
+	}
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), providersmocks.NewMockClient(ctrl), nil, otelImpl, nil, nil)
+	r := gin.New()
+	r.GET("/v1/realtime", router.RealtimeHandler)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/realtime"
+}
+
+func TestRealtimeHandler_RelaysSession(t *testing.T) {
+	upstream := newRealtimeUpstream(t)
+
+	ctrl := gomock.NewController(t)
+	telemetry := mocks.NewMockOpenTelemetry(ctrl)
+	telemetry.EXPECT().RecordTokenUsage(gomock.Any(), "gateway", "unknown", "openai", "gpt-realtime-mini", int64(12), int64(34))
+	closed := make(chan struct{})
+	telemetry.EXPECT().
+		RecordRealtimeSession(gomock.Any(), "gateway", "unknown", "openai", "gpt-realtime-mini", "", gomock.Any()).
+		Do(func(context.Context, string, string, string, string, string, float64) { close(closed) })
+
+	url := newRealtimeTestRouter(t, upstream.URL, telemetry)
+	header := http.Header{"Sec-WebSocket-Protocol": {"realtime, bearer.gateway-token, openai-insecure-api-key.sk-client"}}
+	conn, resp, err := websocket.DefaultDialer.Dial(url+"?model=openai/gpt-realtime", header)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "realtime", resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	handshake := <-upstream.handshakes
+	assert.Equal(t, "/v1/realtime", handshake.URL.Path)
+	assert.Equal(t, "gpt-realtime", handshake.URL.Query().Get("model"))
+	assert.Equal(t, []string{"realtime"}, websocket.Subprotocols(handshake), "credentials offered as subprotocols must not reach the provider")
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"session.update","event_id":"event_1","session":{"type":"realtime","model":"openai/gpt-realtime-mini","max_output_tokens":512}}`)))
+	assert.JSONEq(t, `{"type":"session.update","event_id":"event_1","session":{"type":"realtime","model":"gpt-realtime-mini","max_output_tokens":512}}`, <-upstream.events)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"response.create"}`)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"response.done"`)
+
+	require.NoError(t, conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session duration was not recorded")
+	}
+}
+
+func TestRealtimeHandler_EnforcesModelAllowList(t *testing.T) {
+	upstream := newRealtimeUpstream(t)
+
+	ctrl := gomock.NewController(t)
+	telemetry := mocks.NewMockOpenTelemetry(ctrl)
+	telemetry.EXPECT().RecordRealtimeSession(gomock.Any(), "gateway", "unknown", "openai", "gpt-realtime", gomock.Any(), gomock.Any()).AnyTimes()
+
+	url := newRealtimeTestRouter(t, upstream.URL, telemetry, func(cfg *config.Config) {
+		cfg.AllowedModels = "openai/gpt-realtime"
+	})
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?model=openai/gpt-realtime-mini", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?model=gpt-realtime-mini&provider=openai", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "models named without their provider are checked too")
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?model=openai/gpt-realtime", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	<-upstream.handshakes
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"session.update","event_id":"event_1","session":{"model":"gpt-realtime-mini"}}`)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"error","error":{"type":"invalid_request_error","code":"model_not_allowed","message":"Model not allowed. Please check the list of allowed models.","param":"session.model","event_id":"event_1"}}`, string(data))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"input_audio_buffer.clear"}`)))
+	assert.Equal(t, `{"type":"input_audio_buffer.clear"}`, <-upstream.events, "the rejected event must not reach the provider")
+}
+
+func TestRealtimeHandler_HandshakeFailures(t *testing.T) {
+	upstream := newRealtimeUpstream(t)
+
+	tests := []struct {
+		name   string
+		url    func(string) string
+		token  string
+		status int
+		body   string
+	}{
+		{
+			name:   "upstream rejects the key",
+			url:    func(url string) string { return url + "?model=openai/gpt-realtime" },
+			token:  "wrong-key",
+			status: http.StatusUnauthorized,
+			body:   `{"error":{"message":"Incorrect API key provided","type":"authentication_error","code":"authentication_failed","param":null}}`,
+		},
+		{
+			name:   "provider without a Realtime API",
+			url:    func(url string) string { return url + "?model=anthropic/claude-sonnet-4-5" },
+			status: http.StatusBadRequest,
+			body:   `{"error":{"message":"The Realtime API is not supported by this provider yet.","type":"invalid_request_error","code":"invalid_request","param":null}}`,
+		},
+		{
+			name:   "no model",
+			url:    func(url string) string { return url },
+			status: http.StatusBadRequest,
+			body:   `{"error":{"message":"The model query parameter is required","type":"invalid_request_error","code":"invalid_request","param":null}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := newRealtimeTestRouter(t, upstream.URL, nil, func(cfg *config.Config) {
+				if tt.token != "" {
+					cfg.Providers[constants.OpenaiID].Token = tt.token
+				}
+			})
+			_, resp, err := websocket.DefaultDialer.Dial(tt.url(url), nil)
+			require.Error(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			body := make([]byte, 512)
+			n, _ := resp.Body.Read(body)
+			assert.JSONEq(t, tt.body, string(body[:n]))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	gin "github.com/gin-gonic/gin"
+
+	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// newRerankTestRouter serves /v1/rerank with cohere and llamacpp (which
+// support reranking) and openai (which does not) all pointing at upstreamURL
+func newRerankTestRouter(t *testing.T, upstreamURL string, telemetry *mocks.MockOpenTelemetry) *gin.Engine {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	mockClient := providersmocks.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			return http.DefaultClient.Do(req)
+		}).
+		AnyTimes()
+
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+
+	providerCfg := map[types.Provider]*registry.ProviderConfig{}
+	for _, id := range []types.Provider{constants.CohereID, constants.LlamacppID, constants.OpenaiID} {
+		providerCfg[id] = &registry.ProviderConfig{
+			ID:        id,
+			Name:      registry.Registry[id].Name,
+			URL:       upstreamURL,
+			Token:     "test-" + string(id) + "-key",
+			AuthType:  constants.AuthTypeBearer,
+			Endpoints: registry.Registry[id].Endpoints,
+		}
+	}
+	cfg := config.Config{
+		Server: &config.ServerConfig{
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		},
+		Providers: providerCfg,
+	}
+
+	var otelImpl otel.OpenTelemetry
+	if telemetry != nil {
+		otelImpl = telemetry
+	}
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), mockClient, nil, otelImpl, nil, nil)
+	r := gin.New()
+	r.POST("/v1/rerank", router.RerankHandler)
+	return r
+}
+
+func postRerank(t *testing.T, r *gin.Engine, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/v1/rerank", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRerankHandler_TranslatesCohere(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"rr-1","results":[{"index":2,"relevance_score":0.91},{"index":0,"relevance_score":0.12}],"meta":{"api_version":{"version":"2"},"billed_units":{"search_units":1}}}`))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	telemetry := mocks.NewMockOpenTelemetry(ctrl)
+	telemetry.EXPECT().RecordRerankUsage(gomock.Any(), otel.SourceGateway, otel.TeamUnknown, "cohere", "rerank-v3.5", int64(0), int64(1))
+
+	r := newRerankTestRouter(t, server.URL, telemetry)
+	w := postRerank(t, r, `{"model":"cohere/rerank-v3.5","query":"capital of France","documents":["Berlin is in Germany","Madrid is in Spain","Paris is the capital of France"],"top_n":2,"return_documents":true}`)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "/v2/rerank", gotPath)
+	assert.Equal(t, "Bearer test-cohere-key", gotAuth)
+	assert.Equal(t, map[string]any{
+		"model":     "rerank-v3.5",
+		"query":     "capital of France",
+		"documents": []any{"Berlin is in Germany", "Madrid is in Spain", "Paris is the capital of France"},
+		"top_n":     float64(2),
+	}, gotBody, "fields Cohere's v2 API does not know are not sent")
+	assert.JSONEq(t, `{
+		"id": "rr-1",
+		"model": "rerank-v3.5",
+		"results": [
+			{"index": 2, "relevance_score": 0.91, "document": {"text": "Paris is the capital of France"}},
+			{"index": 0, "relevance_score": 0.12, "document": {"text": "Berlin is in Germany"}}
+		],
+		"usage": {"search_units": 1}
+	}`, w.Body.String())
+}
+
+func TestRerankHandler_RelaysCompatibleProviders(t *testing.T) {
+	const upstreamResponse = `{"model":"bge-reranker-v2-m3","results":[{"index":1,"relevance_score":0.98}],"usage":{"total_tokens":42}}`
+	var gotPath string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(upstreamResponse))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	telemetry := mocks.NewMockOpenTelemetry(ctrl)
+	telemetry.EXPECT().RecordRerankUsage(gomock.Any(), otel.SourceGateway, otel.TeamUnknown, "llamacpp", "bge-reranker-v2-m3", int64(42), int64(0))
+
+	r := newRerankTestRouter(t, server.URL, telemetry)
+	w := postRerank(t, r, `{"model":"llamacpp/bge-reranker-v2-m3","query":"capital of France","documents":["Berlin","Paris"],"truncate_prompt_tokens":512}`)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "/rerank", gotPath)
+	assert.Equal(t, "bge-reranker-v2-m3", gotBody["model"])
+	assert.Equal(t, float64(512), gotBody["truncate_prompt_tokens"], "the body is relayed as-is")
+	assert.JSONEq(t, upstreamResponse, w.Body.String())
+}
+
+func TestRerankHandler_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"model 'rerank-v9' not found"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		error  string
+	}{
+		{name: "missing query", body: `{"model":"cohere/rerank-v3.5","documents":["a"]}`, status: http.StatusBadRequest, error: `{"error":{"message":"The 'query' field is required.","type":"invalid_request_error","code":"invalid_request","param":null}}`},
+		{name: "missing documents", body: `{"model":"cohere/rerank-v3.5","query":"q"}`, status: http.StatusBadRequest, error: `{"error":{"message":"The 'documents' field is required.","type":"invalid_request_error","code":"invalid_request","param":null}}`},
+		{name: "provider without rerank support", body: `{"model":"openai/gpt-4o","query":"q","documents":["a"]}`, status: http.StatusBadRequest, error: `{"error":{"message":"The Rerank API is not supported by this provider yet.","type":"invalid_request_error","code":"invalid_request","param":null}}`},
+		{name: "upstream error", body: `{"model":"cohere/rerank-v9","query":"q","documents":["a"]}`, status: http.StatusUnprocessableEntity, error: `{"message":"model 'rerank-v9' not found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postRerank(t, newRerankTestRouter(t, server.URL, nil), tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, tt.error, w.Body.String())
+		})
+	}
+}
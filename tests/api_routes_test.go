@@ -180,7 +180,7 @@ func TestListModelsHandler_AllowedModelsFiltering(t *testing.T) {
 				Providers: providerCfg,
 			}
 
-			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -278,7 +278,7 @@ func TestListModelsHandler_ErrorCases(t *testing.T) {
 				},
 			}
 
-			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -356,7 +356,7 @@ func TestListModelsHandler_Include(t *testing.T) {
 		},
 		Providers: providerCfg,
 	}
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -602,7 +602,7 @@ func TestChatCompletionsHandler_ModelValidation(t *testing.T) {
 				Providers: providerCfg,
 			}
 
-			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -637,6 +637,11 @@ func TestChatCompletionsHandler_ModelValidation(t *testing.T) {
 			if tt.expectedError != "" {
 				errorMsg, exists := response["error"]
 				assert.True(t, exists, "Response should contain error field")
+				// Denied models are rejected in the OpenAI error envelope
+				if envelope, ok := errorMsg.(map[string]any); ok {
+					assert.Equal(t, "invalid_request", envelope["code"])
+					errorMsg = envelope["message"]
+				}
 				assert.Contains(t, errorMsg.(string), tt.expectedError, "Error message should contain expected text")
 			} else {
 				assert.Equal(t, "chat.completion", response["object"])
@@ -659,7 +664,7 @@ func TestChatCompletionsHandler_RejectsOversizedBody(t *testing.T) {
 	}
 	reg := registry.NewProviderRegistry(providerCfg, log)
 	cfg := config.Config{Server: &config.ServerConfig{ReadTimeout: 5 * time.Second}, Providers: providerCfg}
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -808,7 +813,7 @@ func TestListModelsHandler_DisallowedModelsFiltering(t *testing.T) {
 				Providers: providerCfg,
 			}
 
-			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -984,7 +989,7 @@ func TestChatCompletionsHandler_DisallowedModelValidation(t *testing.T) {
 				Providers: providerCfg,
 			}
 
-			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -1019,6 +1024,11 @@ func TestChatCompletionsHandler_DisallowedModelValidation(t *testing.T) {
 			if tt.expectedError != "" {
 				errorMsg, exists := response["error"]
 				assert.True(t, exists, "Response should contain error field")
+				// Denied models are rejected in the OpenAI error envelope
+				if envelope, ok := errorMsg.(map[string]any); ok {
+					assert.Equal(t, "invalid_request", envelope["code"])
+					errorMsg = envelope["message"]
+				}
 				assert.Contains(t, errorMsg.(string), tt.expectedError, "Error message should contain expected text")
 			} else {
 				assert.Equal(t, "chat.completion", response["object"])
@@ -1141,7 +1151,7 @@ func TestChatCompletionsHandler_AllowedModelsTakesPrecedence(t *testing.T) {
 				Providers: providerCfg,
 			}
 
-			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, registry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -1176,6 +1186,11 @@ func TestChatCompletionsHandler_AllowedModelsTakesPrecedence(t *testing.T) {
 			if tt.expectedError != "" {
 				errorMsg, exists := response["error"]
 				assert.True(t, exists, "Response should contain error field")
+				// Denied models are rejected in the OpenAI error envelope
+				if envelope, ok := errorMsg.(map[string]any); ok {
+					assert.Equal(t, "invalid_request", envelope["code"])
+					errorMsg = envelope["message"]
+				}
 				assert.Contains(t, errorMsg.(string), tt.expectedError, "Error message should contain expected text")
 			} else {
 				assert.Equal(t, "chat.completion", response["object"])
@@ -1191,20 +1206,26 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 		providerError      error
 		expectedStatusCode int
 		expectedError      string
+		expectedCode       core.ErrorCode
+		expectedType       string
 		description        string
 	}{
 		{
-			name:               "Generic streaming error returns 400 with full error message",
+			name:               "Generic streaming error returns 502 with a generic message",
 			providerError:      assert.AnError,
-			expectedStatusCode: http.StatusBadRequest,
-			expectedError:      assert.AnError.Error(),
-			description:        "Generic errors should return 400 with the full error message",
+			expectedStatusCode: http.StatusBadGateway,
+			expectedError:      "Upstream request failed",
+			expectedCode:       core.ErrorCodeUpstream,
+			expectedType:       "server_error",
+			description:        "Generic errors should return 502 without exposing the error",
 		},
 		{
 			name:               "HTTP 401 error is returned with correct status code",
 			providerError:      &core.HTTPError{StatusCode: http.StatusUnauthorized, Message: `{"error":{"message":"authentication failed"}}`},
 			expectedStatusCode: http.StatusUnauthorized,
 			expectedError:      "authentication failed",
+			expectedCode:       core.ErrorCodeAuthentication,
+			expectedType:       "authentication_error",
 			description:        "HTTP 401 errors should return with correct status code",
 		},
 		{
@@ -1212,6 +1233,8 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 			providerError:      &core.HTTPError{StatusCode: http.StatusForbidden, Message: `{"error":{"message":"forbidden access"}}`},
 			expectedStatusCode: http.StatusForbidden,
 			expectedError:      "forbidden access",
+			expectedCode:       core.ErrorCodeAuthentication,
+			expectedType:       "authentication_error",
 			description:        "HTTP 403 errors should return with correct status code",
 		},
 		{
@@ -1219,6 +1242,8 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 			providerError:      &core.HTTPError{StatusCode: http.StatusNotFound, Message: `{"error":{"message":"model not found"}}`},
 			expectedStatusCode: http.StatusNotFound,
 			expectedError:      "model not found",
+			expectedCode:       core.ErrorCodeInvalidRequest,
+			expectedType:       "invalid_request_error",
 			description:        "HTTP 404 errors should return with correct status code",
 		},
 		{
@@ -1226,6 +1251,8 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 			providerError:      &core.HTTPError{StatusCode: http.StatusTooManyRequests, Message: `{"error":{"message":"rate limit exceeded"}}`},
 			expectedStatusCode: http.StatusTooManyRequests,
 			expectedError:      "rate limit exceeded",
+			expectedCode:       core.ErrorCodeRateLimit,
+			expectedType:       "rate_limit_error",
 			description:        "HTTP 429 errors should return with correct status code",
 		},
 		{
@@ -1233,6 +1260,8 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 			providerError:      &core.HTTPError{StatusCode: http.StatusInternalServerError, Message: `{"error":{"message":"internal server error"}}`},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedError:      "internal server error",
+			expectedCode:       core.ErrorCodeUpstream,
+			expectedType:       "server_error",
 			description:        "HTTP 500 errors should return with correct status code",
 		},
 		{
@@ -1240,6 +1269,8 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 			providerError:      &core.HTTPError{StatusCode: http.StatusBadGateway, Message: `{"error":{"message":"bad gateway"}}`},
 			expectedStatusCode: http.StatusBadGateway,
 			expectedError:      "bad gateway",
+			expectedCode:       core.ErrorCodeUpstream,
+			expectedType:       "server_error",
 			description:        "HTTP 502 errors should return with correct status code",
 		},
 		{
@@ -1247,6 +1278,8 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 			providerError:      &core.HTTPError{StatusCode: http.StatusServiceUnavailable, Message: `{"error":{"message":"service unavailable"}}`},
 			expectedStatusCode: http.StatusServiceUnavailable,
 			expectedError:      "service unavailable",
+			expectedCode:       core.ErrorCodeOverloaded,
+			expectedType:       "server_error",
 			description:        "HTTP 503 errors should return with correct status code",
 		},
 	}
@@ -1291,7 +1324,7 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 				BuildProvider(constants.OpenaiID, mockClient).
 				Return(mockProvider, nil)
 
-			router := api.NewRouter(cfg, log, mockRegistry, mockClient, nil, nil, nil)
+			router := api.NewRouter(cfg, log, mockRegistry, mockClient, nil, nil, nil, nil)
 
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
@@ -1317,13 +1350,13 @@ func TestChatCompletionsHandler_StreamingErrorHandling(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code, "Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
 
-			var response map[string]any
+			var response core.OpenAIErrorBody
 			err = json.Unmarshal(w.Body.Bytes(), &response)
 			require.NoError(t, err)
 
-			errorMsg, exists := response["error"]
-			assert.True(t, exists, "Response should contain error field")
-			assert.Contains(t, errorMsg.(string), tt.expectedError, "Error message should contain expected text")
+			assert.Equal(t, tt.expectedError, response.Error.Message, "Error message should be the upstream message")
+			assert.Equal(t, string(tt.expectedCode), response.Error.Code)
+			assert.Equal(t, tt.expectedType, response.Error.Type)
 		})
 	}
 }
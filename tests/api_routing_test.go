@@ -17,6 +17,7 @@ import (
 	config "github.com/inference-gateway/inference-gateway/config"
 	logger "github.com/inference-gateway/inference-gateway/logger"
 	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	registry "github.com/inference-gateway/inference-gateway/providers/registry"
 	routing "github.com/inference-gateway/inference-gateway/providers/routing"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
@@ -89,7 +90,7 @@ func TestChatCompletionsRouting_RoundRobinRotation(t *testing.T) {
 		routing.Deployment{Provider: "openai", Model: "model-a"},
 		routing.Deployment{Provider: "groq", Model: "model-b"},
 	)
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel, nil)
 	r := gin.New()
 	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
 
@@ -115,10 +116,10 @@ func TestChatCompletionsRouting_StreamingPassthrough(t *testing.T) {
 	reg := providersmocks.NewMockProviderRegistry(ctrl)
 
 	prov.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ any, req types.CreateChatCompletionRequest) (<-chan []byte, error) {
+		func(_ any, req types.CreateChatCompletionRequest) (<-chan core.StreamEvent, error) {
 			assert.Equal(t, "stream-model", req.Model)
-			ch := make(chan []byte, 1)
-			ch <- []byte("data: {}\n\n")
+			ch := make(chan core.StreamEvent, 1)
+			ch <- streamChunk(`{}`)
 			close(ch)
 			return ch, nil
 		})
@@ -128,7 +129,7 @@ func TestChatCompletionsRouting_StreamingPassthrough(t *testing.T) {
 		routing.Deployment{Provider: "openai", Model: "stream-model"},
 		routing.Deployment{Provider: "groq", Model: "stream-model-b"},
 	)
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel, nil)
 	r := gin.New()
 	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
 
@@ -155,7 +156,7 @@ func TestChatCompletionsRouting_DisabledPassthrough(t *testing.T) {
 	mockClient := providersmocks.NewMockClient(ctrl)
 	reg := providersmocks.NewMockProviderRegistry(ctrl)
 
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
 	r := gin.New()
 	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
 
@@ -188,7 +189,7 @@ func TestChatCompletionsRouting_ExplicitProviderWins(t *testing.T) {
 		routing.Deployment{Provider: "openai", Model: "model-a"},
 		routing.Deployment{Provider: "ollama", Model: "model-b"},
 	)
-	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel, nil)
 	r := gin.New()
 	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
 
@@ -234,7 +235,7 @@ func TestChatCompletionsRouting_AllowedModelsFiltersAlias(t *testing.T) {
 				routing.Deployment{Provider: "openai", Model: "model-a"},
 				routing.Deployment{Provider: "groq", Model: "model-b"},
 			)
-			router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel)
+			router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel, nil)
 			r := gin.New()
 			r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
 
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
+	shaping "github.com/inference-gateway/inference-gateway/internal/shaping"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	routing "github.com/inference-gateway/inference-gateway/providers/routing"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+	mcpmocks "github.com/inference-gateway/inference-gateway/tests/mocks/mcp"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+)
+
+// Shaping rules match the routing alias as well as the resolved provider/model
+// and rewrite the request before it reaches the provider.
+func TestChatCompletionsHandler_Shaping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log, cfg := routingTestSetup(t)
+
+	shaper, err := shaping.NewShaper(&shaping.Config{Rules: []shaping.Rule{
+		{Model: "code", Defaults: map[string]any{"temperature": 0.2}},
+		{Model: "groq/*", Clamp: map[string]shaping.Range{"max_tokens": {Max: ptr(4096.0)}}, Strip: []string{"logit_bias"}},
+		{Model: "openai/*", Force: map[string]any{"seed": 7}},
+	}})
+	require.NoError(t, err)
+
+	mockClient := providersmocks.NewMockClient(ctrl)
+	provider := providersmocks.NewMockIProvider(ctrl)
+	reg := providersmocks.NewMockProviderRegistry(ctrl)
+	reg.EXPECT().BuildProvider(constants.GroqID, mockClient).Return(provider, nil)
+	provider.EXPECT().ChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, req types.CreateChatCompletionRequest) (types.CreateChatCompletionResponse, error) {
+			assert.Equal(t, "llama-3.3-70b-versatile", req.Model)
+			require.NotNil(t, req.Temperature)
+			assert.InDelta(t, 0.2, *req.Temperature, 0.0001)
+			require.NotNil(t, req.MaxTokens)
+			assert.Equal(t, 4096, *req.MaxTokens)
+			assert.Nil(t, req.LogitBias)
+			assert.Nil(t, req.Seed, "rules for other providers do not apply")
+			require.Len(t, req.Messages, 1)
+			return types.CreateChatCompletionResponse{ID: "ok", Model: req.Model}, nil
+		})
+
+	sel := routingSelector(t, "code",
+		routing.Deployment{Provider: "groq", Model: "llama-3.3-70b-versatile"},
+		routing.Deployment{Provider: "groq", Model: "llama-3.3-70b-versatile"},
+	)
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, sel, shaper)
+	r := gin.New()
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+	maxTokens := 8192
+	w := postJSON(t, r, "/v1/chat/completions", types.CreateChatCompletionRequest{
+		Model:     "code",
+		Messages:  []types.Message{types.NewTextMessage(t, types.User, "hi")},
+		MaxTokens: &maxTokens,
+		LogitBias: &map[string]int{"50256": -100},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+// Streaming requests answered by the MCP agent are shaped by the admission
+// middleware before MCP takes them over.
+func TestChatCompletionsAdmission_ShapesMCPStreamingRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log, cfg := routingTestSetup(t)
+	cfg.MCP = &config.MCPConfig{ToolMode: mcp.ToolModeDirect}
+
+	shaper, err := shaping.NewShaper(&shaping.Config{Rules: []shaping.Rule{
+		{Model: "openai/*", Force: map[string]any{"seed": 7}, Strip: []string{"logit_bias"}},
+	}})
+	require.NoError(t, err)
+
+	mockClient := providersmocks.NewMockClient(ctrl)
+	provider := providersmocks.NewMockIProvider(ctrl)
+	reg := providersmocks.NewMockProviderRegistry(ctrl)
+	reg.EXPECT().BuildProvider(constants.OpenaiID, mockClient).Return(provider, nil).AnyTimes()
+	provider.EXPECT().GetName().Return(constants.OpenaiDisplayName).AnyTimes()
+	provider.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, req types.CreateChatCompletionRequest) (<-chan core.StreamEvent, error) {
+			require.NotNil(t, req.Seed)
+			assert.Equal(t, 7, *req.Seed)
+			assert.Nil(t, req.LogitBias)
+			require.NotNil(t, req.Tools, "MCP still injects its tools")
+			assert.Len(t, *req.Tools, 1)
+
+			data := `{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"Hello"},"finish_reason":"stop"}]}`
+			var chunk types.CreateChatCompletionStreamResponse
+			require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+			ch := make(chan core.StreamEvent, 1)
+			ch <- core.StreamEvent{Chunk: &chunk, Data: []byte(data)}
+			close(ch)
+			return ch, nil
+		})
+
+	mcpClient := mcpmocks.NewMockMCPClientInterface(ctrl)
+	mcpClient.EXPECT().IsInitialized().Return(true).AnyTimes()
+	mcpClient.EXPECT().GetAllServerStatuses().Return(map[string]mcp.ServerStatus{"server1": mcp.ServerStatusAvailable}).AnyTimes()
+	mcpClient.EXPECT().GetAllChatCompletionTools().Return([]types.ChatCompletionTool{{
+		Type:     types.Function,
+		Function: types.FunctionObject{Name: "search"},
+	}}).AnyTimes()
+	mcpMiddleware, err := middlewares.NewMCPMiddleware(reg, mockClient, mcpClient, mcp.NewAgent(log, mcpClient), log, cfg)
+	require.NoError(t, err)
+
+	router := api.NewRouter(cfg, log, reg, mockClient, mcpClient, nil, nil, shaper)
+	r := gin.New()
+	r.Use(middlewares.NewRequestBodyMiddleware(cfg).Middleware())
+	r.Use(router.ChatCompletionsAdmission())
+	r.Use(mcpMiddleware.Middleware())
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+	gateway := httptest.NewServer(r)
+	defer gateway.Close()
+
+	resp, err := http.Post(gateway.URL+"/v1/chat/completions", "application/json", strings.NewReader(
+		`{"model":"openai/gpt-4o","stream":true,"logit_bias":{"50256":-100},"messages":[{"role":"user","content":"hi"}]}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Contains(t, string(body), "Hello")
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+)
+
+func postJSON(t *testing.T, r http.Handler, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return postJSONWithHeader(t, r, path, "", "", body)
+}
+
+// postJSONWithHeader posts body with an extra request header, skipped when
+// value is empty
+func postJSONWithHeader(t *testing.T, r http.Handler, path, header, value string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(string(raw)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if value != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestTokenizeHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log, cfg := routingTestSetup(t)
+	mockClient := providersmocks.NewMockClient(ctrl)
+	reg := providersmocks.NewMockProviderRegistry(ctrl)
+	// No Ollama server is configured to report the runtime window
+	reg.EXPECT().BuildProvider(constants.OllamaID, mockClient).Return(nil, errors.New("provider not configured")).AnyTimes()
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
+	r := gin.New()
+	r.POST("/v1/tokenize", router.TokenizeHandler)
+
+	w := postJSON(t, r, "/v1/tokenize", map[string]any{
+		"model":    "openai/gpt-4o",
+		"messages": []types.Message{types.NewTextMessage(t, types.User, "hello world")},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.TokenizeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "openai/gpt-4o", resp.Model)
+	assert.Equal(t, 9, resp.Tokens)
+	assert.Equal(t, "tiktoken", resp.Method)
+	require.NotNil(t, resp.Encoding)
+	assert.Equal(t, "o200k_base", *resp.Encoding)
+	require.NotNil(t, resp.ContextWindow)
+	assert.Equal(t, 128000, *resp.ContextWindow)
+
+	w = postJSON(t, r, "/v1/tokenize?provider=ollama", map[string]any{"model": "llama3.2", "input": "hello world"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = types.TokenizeResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Tokens)
+	assert.Equal(t, "heuristic", resp.Method)
+	assert.Nil(t, resp.Encoding)
+	assert.Nil(t, resp.ContextWindow, "local models have no community window and the runtime is unreachable")
+
+	for name, body := range map[string]map[string]any{
+		"MissingModel":    {"input": "hello"},
+		"NoContent":       {"model": "openai/gpt-4o"},
+		"BothContents":    {"model": "openai/gpt-4o", "input": "hello", "messages": []types.Message{}},
+		"UnknownProvider": {"model": "gpt-4o", "input": "hello"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := postJSON(t, r, "/v1/tokenize", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestChatCompletionsHandler_EnforceContextWindow(t *testing.T) {
+	// openai/gpt-4 has an 8192 token window in the community table
+	longPrompt := strings.Repeat("hello ", 9000)
+
+	tests := []struct {
+		name        string
+		enforce     bool
+		prompt      string
+		maxTokens   *int
+		wantStatus  int
+		wantMessage string
+	}{
+		{"FitsTheWindow", true, "hello world", nil, http.StatusOK, ""},
+		{"PromptTooLong", true, longPrompt, nil, http.StatusBadRequest, "maximum context length is 8192 tokens"},
+		{"BudgetTooLarge", true, strings.Repeat("hello ", 4000), new(5000), http.StatusBadRequest, "(4008 in the messages, 5000 in the completion)"},
+		{"EnforcementDisabled", false, longPrompt, nil, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			log, cfg := routingTestSetup(t)
+			cfg.EnforceContextWindow = tt.enforce
+
+			mockClient := providersmocks.NewMockClient(ctrl)
+			provider := providersmocks.NewMockIProvider(ctrl)
+			reg := providersmocks.NewMockProviderRegistry(ctrl)
+			reg.EXPECT().BuildProvider(constants.OpenaiID, mockClient).Return(provider, nil).AnyTimes()
+			if tt.wantStatus == http.StatusOK {
+				provider.EXPECT().ChatCompletions(gomock.Any(), gomock.Any()).
+					Return(types.CreateChatCompletionResponse{ID: "ok", Model: "gpt-4"}, nil)
+			}
+
+			router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
+			r := gin.New()
+			r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+			w := postJSON(t, r, "/v1/chat/completions", types.CreateChatCompletionRequest{
+				Model:     "openai/gpt-4",
+				Messages:  []types.Message{types.NewTextMessage(t, types.User, tt.prompt)},
+				MaxTokens: tt.maxTokens,
+			})
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.wantMessage)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	config "github.com/inference-gateway/inference-gateway/config"
+	truncation "github.com/inference-gateway/inference-gateway/internal/truncation"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+)
+
+// longConversation exceeds the 8192 token window of openai/gpt-4 by about one
+// message
+func longConversation(t *testing.T) []types.Message {
+	t.Helper()
+	long := strings.Repeat("hello ", 3000)
+	return []types.Message{
+		types.NewTextMessage(t, types.System, "You are a helpful assistant."),
+		types.NewTextMessage(t, types.User, long),
+		types.NewTextMessage(t, types.Assistant, long),
+		types.NewTextMessage(t, types.User, long),
+		types.NewTextMessage(t, types.User, "What next?"),
+	}
+}
+
+func TestChatCompletionsHandler_Truncation(t *testing.T) {
+	tests := []struct {
+		name         string
+		settings     config.TruncationConfig
+		header       string
+		summary      string
+		wantStatus   int
+		wantMessages int
+		wantStrategy string
+		wantRemoved  string
+	}{
+		{
+			name:         "ConfiguredModel",
+			settings:     config.TruncationConfig{Enabled: true, Models: "openai/gpt-4", Strategy: truncation.StrategyDropOldest},
+			wantStatus:   http.StatusOK,
+			wantMessages: 4,
+			wantStrategy: truncation.StrategyDropOldest,
+			wantRemoved:  "1",
+		},
+		{
+			name:         "RequestHeader",
+			settings:     config.TruncationConfig{Enabled: true, Strategy: truncation.StrategyDropOldest, KeepFirst: 1},
+			header:       truncation.StrategyKeepFirstLast,
+			wantStatus:   http.StatusOK,
+			wantMessages: 4,
+			wantStrategy: truncation.StrategyKeepFirstLast,
+			wantRemoved:  "2",
+		},
+		{
+			name:         "SummarizeMiddle",
+			settings:     config.TruncationConfig{Enabled: true, KeepFirst: 1, SummaryModel: "groq/small-model", SummaryMaxTokens: 256},
+			header:       truncation.StrategySummarizeMiddle,
+			summary:      "The user said hello many times.",
+			wantStatus:   http.StatusOK,
+			wantMessages: 5,
+			wantStrategy: truncation.StrategySummarizeMiddle,
+			wantRemoved:  "2",
+		},
+		{
+			name:       "HeaderOptsOut",
+			settings:   config.TruncationConfig{Enabled: true, Models: "openai/gpt-4", Strategy: truncation.StrategyDropOldest},
+			header:     truncation.StrategyNone,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ModelNotConfigured",
+			settings:   config.TruncationConfig{Enabled: true, Models: "openai/gpt-4o", Strategy: truncation.StrategyDropOldest},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Disabled",
+			settings:   config.TruncationConfig{Enabled: false, Models: "openai/gpt-4", Strategy: truncation.StrategyDropOldest},
+			header:     truncation.StrategyDropOldest,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "InvalidHeader",
+			settings:   config.TruncationConfig{Enabled: true, Strategy: truncation.StrategyDropOldest},
+			header:     "drop_newest",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			log, cfg := routingTestSetup(t)
+			cfg.EnforceContextWindow = true
+			cfg.Truncation = &tt.settings
+			cfg.Providers = map[types.Provider]*registry.ProviderConfig{constants.OpenaiID: {}, constants.GroqID: {}}
+
+			mockClient := providersmocks.NewMockClient(ctrl)
+			provider := providersmocks.NewMockIProvider(ctrl)
+			summarizer := providersmocks.NewMockIProvider(ctrl)
+			reg := providersmocks.NewMockProviderRegistry(ctrl)
+			reg.EXPECT().BuildProvider(constants.OpenaiID, mockClient).Return(provider, nil).AnyTimes()
+			reg.EXPECT().BuildProvider(constants.GroqID, mockClient).Return(summarizer, nil).AnyTimes()
+
+			var sent types.CreateChatCompletionRequest
+			if tt.wantStatus == http.StatusOK {
+				provider.EXPECT().ChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, req types.CreateChatCompletionRequest) (types.CreateChatCompletionResponse, error) {
+						sent = req
+						return types.CreateChatCompletionResponse{ID: "ok", Model: req.Model}, nil
+					})
+			}
+			if tt.summary != "" {
+				summarizer.EXPECT().ChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, req types.CreateChatCompletionRequest) (types.CreateChatCompletionResponse, error) {
+						assert.Equal(t, "small-model", req.Model)
+						require.NotNil(t, req.MaxTokens)
+						assert.Equal(t, 256, *req.MaxTokens)
+						return types.CreateChatCompletionResponse{
+							Choices: []types.ChatCompletionChoice{{Message: types.NewTextMessage(t, types.Assistant, tt.summary)}},
+						}, nil
+					})
+			}
+
+			router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
+			r := gin.New()
+			r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+
+			w := postJSONWithHeader(t, r, "/v1/chat/completions", api.TruncationStrategyHeader, tt.header, types.CreateChatCompletionRequest{
+				Model:    "openai/gpt-4",
+				Messages: longConversation(t),
+			})
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.wantStrategy, w.Header().Get(api.TruncationStrategyHeader))
+			assert.Equal(t, tt.wantRemoved, w.Header().Get(api.TruncatedMessagesHeader))
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			require.Len(t, sent.Messages, tt.wantMessages)
+			assert.Equal(t, types.System, sent.Messages[0].Role)
+			assert.Equal(t, "What next?", sent.Messages[len(sent.Messages)-1].TextContent())
+			assert.NotEmpty(t, w.Header().Get(api.TruncatedTokensHeader))
+			if tt.summary != "" {
+				assert.Equal(t, truncation.SummaryPrefix+tt.summary, sent.Messages[2].TextContent())
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	gin "github.com/gin-gonic/gin"
+	websocket "github.com/gorilla/websocket"
+
+	api "github.com/inference-gateway/inference-gateway/api"
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// wsMessage is a message of the /v1/ws protocol, as seen by a client
+type wsMessage struct {
+	Type       string                             `json:"type"`
+	Session    string                             `json:"session,omitempty"`
+	Request    *types.CreateChatCompletionRequest `json:"request,omitempty"`
+	Message    *types.Message                     `json:"message,omitempty"`
+	Chunk      json.RawMessage                    `json:"chunk,omitempty"`
+	Error      *struct{ Code, Message string }    `json:"error,omitempty"`
+	ToolCallID string                             `json:"tool_call_id,omitempty"`
+}
+
+// sseUpstream streams a chat completion answering "Hello", recording the
+// messages of every request
+func sseUpstream(t *testing.T) (*httptest.Server, func() [][]types.Message) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests [][]types.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req types.CreateChatCompletionRequest
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+		mu.Lock()
+		requests = append(requests, req.Messages)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, content := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":%q},\"finish_reason\":null}]}\n\n", content)
+		}
+		fmt.Fprint(w, "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server, func() [][]types.Message {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func newWebSocketTestServer(t *testing.T, router api.Router) string {
+	t.Helper()
+	r := gin.New()
+	r.GET("/v1/ws", router.WebSocketHandler)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/ws"
+}
+
+func dialWebSocket(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Sec-WebSocket-Protocol": {api.WebSocketProtocol}})
+	require.NoError(t, err)
+	assert.Equal(t, api.WebSocketProtocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readUntil reads the messages of a session up to one of the given type
+func readUntil(t *testing.T, conn *websocket.Conn, msgType string) []wsMessage {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var messages []wsMessage
+	for {
+		var msg wsMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		messages = append(messages, msg)
+		if msg.Type == msgType {
+			return messages
+		}
+	}
+}
+
+func chunkContent(t *testing.T, messages []wsMessage) string {
+	t.Helper()
+	var content strings.Builder
+	for _, msg := range messages {
+		if msg.Type != api.WSChunk {
+			continue
+		}
+		var chunk types.CreateChatCompletionStreamResponse
+		require.NoError(t, json.Unmarshal(msg.Chunk, &chunk))
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	return content.String()
+}
+
+func TestWebSocketHandler_SessionTurns(t *testing.T) {
+	upstream, requests := sseUpstream(t)
+	conn := dialWebSocket(t, newWebSocketTestServer(t, newMessagesTestRouter(t, upstream.URL)))
+
+	require.NoError(t, conn.WriteJSON(wsMessage{
+		Type:    api.WSSessionCreate,
+		Session: "s1",
+		Request: &types.CreateChatCompletionRequest{
+			Model:    "openai/gpt-4o",
+			Messages: []types.Message{types.NewTextMessage(t, types.User, "Hi")},
+		},
+	}))
+	messages := readUntil(t, conn, api.WSResponseDone)
+	assert.Equal(t, api.WSSessionCreated, messages[0].Type)
+	assert.Equal(t, "Hello", chunkContent(t, messages))
+	for _, msg := range messages {
+		assert.Equal(t, "s1", msg.Session)
+	}
+
+	next := types.NewTextMessage(t, types.User, "And again")
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionMessage, Session: "s1", Message: &next}))
+	messages = readUntil(t, conn, api.WSResponseDone)
+	assert.Equal(t, "Hello", chunkContent(t, messages))
+
+	got := requests()
+	require.Len(t, got, 2)
+	require.Len(t, got[1], 3, "the second turn carries the history of the session")
+	assert.Equal(t, types.User, got[1][0].Role)
+	assert.Equal(t, types.Assistant, got[1][1].Role)
+	content, err := got[1][1].Content.AsMessageContent0()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", content)
+	assert.Equal(t, types.User, got[1][2].Role)
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionClose, Session: "s1"}))
+	readUntil(t, conn, api.WSSessionClosed)
+}
+
+func TestWebSocketHandler_CancelGeneration(t *testing.T) {
+	started := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		close(started)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(upstream.Close)
+
+	conn := dialWebSocket(t, newWebSocketTestServer(t, newMessagesTestRouter(t, upstream.URL)))
+	require.NoError(t, conn.WriteJSON(wsMessage{
+		Type:    api.WSSessionCreate,
+		Session: "s1",
+		Request: &types.CreateChatCompletionRequest{
+			Model:    "openai/gpt-4o",
+			Messages: []types.Message{types.NewTextMessage(t, types.User, "Hi")},
+		},
+	}))
+	readUntil(t, conn, api.WSSessionCreated)
+	<-started
+
+	next := types.NewTextMessage(t, types.User, "Hi again")
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionMessage, Session: "s1", Message: &next}))
+	messages := readUntil(t, conn, api.WSError)
+	assert.Equal(t, "invalid_request", messages[len(messages)-1].Error.Code, "a busy session rejects new turns")
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionCancel, Session: "s1"}))
+	readUntil(t, conn, api.WSResponseCancelled)
+}
+
+func TestWebSocketHandler_InvalidMessages(t *testing.T) {
+	upstream, _ := sseUpstream(t)
+	conn := dialWebSocket(t, newWebSocketTestServer(t, newMessagesTestRouter(t, upstream.URL)))
+
+	tests := []struct {
+		name    string
+		message string
+		error   string
+	}{
+		{name: "malformed JSON", message: `{"type":`, error: "Failed to decode message"},
+		{name: "unknown session", message: `{"type":"session.cancel","session":"missing"}`, error: `Unknown session "missing"`},
+		{name: "no model", message: `{"type":"session.create","session":"s1","request":{"messages":[]}}`, error: "session.create requires a request with a model"},
+		{name: "session without messages", message: `{"type":"session.create","session":"s2","request":{"model":"openai/gpt-4o"}}`, error: ""},
+		{name: "unexpected approval", message: `{"type":"tool.approval","session":"s2","tool_call_id":"call_1","approved":true}`, error: `No tool approval pending for "call_1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(tt.message)))
+			if tt.error == "" {
+				readUntil(t, conn, api.WSSessionCreated)
+				return
+			}
+			messages := readUntil(t, conn, api.WSError)
+			msg := messages[len(messages)-1]
+			assert.Equal(t, "invalid_request", msg.Error.Code)
+			assert.Equal(t, tt.error, msg.Error.Message)
+		})
+	}
+}
+
+func TestWebSocketHandler_CheckOrigin(t *testing.T) {
+	upstream, _ := sseUpstream(t)
+
+	tests := []struct {
+		name    string
+		allowed string
+		origin  string
+		accept  bool
+	}{
+		{name: "no origin", origin: "", accept: true},
+		{name: "cross origin", origin: "https://tools.example.com", accept: false},
+		{name: "allowed origin", allowed: "https://other.example.com, https://tools.example.com", origin: "https://tools.example.com", accept: true},
+		{name: "any origin", allowed: "*", origin: "https://tools.example.com", accept: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := newWebSocketTestServer(t, newMessagesTestRouter(t, upstream.URL, func(cfg *config.Config) {
+				cfg.Server.WebsocketAllowedOrigins = tt.allowed
+			}))
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+			if tt.accept {
+				require.NoError(t, err)
+				conn.Close()
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
+
+// blockForbiddenTopics sets guardrails on router blocking the requests that
+// mention "forbidden"
+func blockForbiddenTopics(t *testing.T, router api.Router) {
+	t.Helper()
+	policy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input guardrails.Input
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		w.Header().Set("Content-Type", "application/json")
+		if input.Phase == guardrails.PhasePreCall && strings.Contains(input.Request.Body, "forbidden") {
+			_, _ = w.Write([]byte(`{"action":"block","message":"request mentions a forbidden topic"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"action":"allow"}`))
+	}))
+	t.Cleanup(policy.Close)
+
+	evaluator, err := guardrails.NewEvaluator(context.Background(), "")
+	require.NoError(t, err)
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+	router.SetGuardrails(middlewares.NewGuardrailsMiddleware(evaluator, guardrails.NewExternalClient(policy.URL, 5*time.Second), nil, nil, log, nil, config.Config{
+		Guardrails: &config.GuardrailsConfig{Enabled: true, FailMode: guardrails.FailModeClosed},
+	}))
+}
+
+func TestWebSocketHandler_GuardrailsBlockSessionMessage(t *testing.T) {
+	upstream, requests := sseUpstream(t)
+	router := newMessagesTestRouter(t, upstream.URL)
+	blockForbiddenTopics(t, router)
+	conn := dialWebSocket(t, newWebSocketTestServer(t, router))
+
+	require.NoError(t, conn.WriteJSON(wsMessage{
+		Type:    api.WSSessionCreate,
+		Session: "s1",
+		Request: &types.CreateChatCompletionRequest{Model: "openai/gpt-4o"},
+	}))
+	readUntil(t, conn, api.WSSessionCreated)
+
+	blocked := types.NewTextMessage(t, types.User, "Tell me about the forbidden topic")
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionMessage, Session: "s1", Message: &blocked}))
+	messages := readUntil(t, conn, api.WSError)
+	errMsg := messages[len(messages)-1]
+	require.NotNil(t, errMsg.Error)
+	assert.Equal(t, string(core.ErrorCodeContentFilter), errMsg.Error.Code)
+	assert.Contains(t, errMsg.Error.Message, "request mentions a forbidden topic")
+	assert.Empty(t, requests(), "a blocked turn never reaches the provider")
+
+	allowed := types.NewTextMessage(t, types.User, "Hi")
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionMessage, Session: "s1", Message: &allowed}))
+	messages = readUntil(t, conn, api.WSResponseDone)
+	assert.Equal(t, "Hello", chunkContent(t, messages))
+
+	got := requests()
+	require.Len(t, got, 1)
+	require.Len(t, got[0], 1, "the blocked message is not kept in the session history")
+}
+
+func TestWebSocketHandler_GuardrailsBlockSessionCreate(t *testing.T) {
+	upstream, requests := sseUpstream(t)
+	router := newMessagesTestRouter(t, upstream.URL)
+	blockForbiddenTopics(t, router)
+	conn := dialWebSocket(t, newWebSocketTestServer(t, router))
+
+	require.NoError(t, conn.WriteJSON(wsMessage{
+		Type:    api.WSSessionCreate,
+		Session: "s1",
+		Request: &types.CreateChatCompletionRequest{
+			Model:    "openai/gpt-4o",
+			Messages: []types.Message{types.NewTextMessage(t, types.User, "Tell me about the forbidden topic")},
+		},
+	}))
+	messages := readUntil(t, conn, api.WSError)
+	assert.Equal(t, api.WSSessionCreated, messages[0].Type)
+	errMsg := messages[len(messages)-1]
+	require.NotNil(t, errMsg.Error)
+	assert.Equal(t, string(core.ErrorCodeContentFilter), errMsg.Error.Code)
+
+	allowed := types.NewTextMessage(t, types.User, "Hi")
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: api.WSSessionMessage, Session: "s1", Message: &allowed}))
+	messages = readUntil(t, conn, api.WSResponseDone)
+	assert.Equal(t, "Hello", chunkContent(t, messages))
+
+	got := requests()
+	require.Len(t, got, 1)
+	require.Len(t, got[0], 1, "the blocked first turn is not kept in the session history")
+}
@@ -23,6 +23,7 @@ import (
 	config "github.com/inference-gateway/inference-gateway/config"
 	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
 	logger "github.com/inference-gateway/inference-gateway/logger"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
@@ -30,6 +31,15 @@ func init() {
 	gin.SetMode(gin.TestMode)
 }
 
+// streamChunk is the stream event a provider emits for a chunk
+func streamChunk(data string) core.StreamEvent {
+	var chunk types.CreateChatCompletionStreamResponse
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		return core.StreamEvent{Err: err}
+	}
+	return core.StreamEvent{Chunk: &chunk, Data: []byte(data), Usage: chunk.Usage}
+}
+
 func TestNewAgent(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -513,6 +523,45 @@ func TestAgent_ExecuteTools(t *testing.T) {
 	}
 }
 
+func TestAgent_ExecuteToolsAsksToolApprover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMCPClient := mcpmocks.NewMockMCPClientInterface(ctrl)
+	mockMCPClient.EXPECT().GetServerForTool("approved_tool").Return("http://test-server:8080/mcp", nil)
+	mockMCPClient.EXPECT().ExecuteTool(gomock.Any(), gomock.Any(), "http://test-server:8080/mcp").Return(&mcp.CallToolResult{
+		Content: []mcp.ContentBlock{mcp.TextContent{Type: "text", Text: "done"}},
+	}, nil)
+
+	agentInstance := mcp.NewAgent(logger.NewNoopLogger(), mockMCPClient)
+	var asked []string
+	agentInstance.SetToolApprover(func(ctx context.Context, toolCall types.ChatCompletionMessageToolCall) (bool, error) {
+		asked = append(asked, toolCall.ID)
+		return toolCall.Function.Name == "mcp_approved_tool", nil
+	})
+
+	results, err := agentInstance.ExecuteTools(context.Background(), []types.ChatCompletionMessageToolCall{
+		{ID: "call_1", Type: types.Function, Function: types.ChatCompletionMessageToolCallFunction{Name: "mcp_denied_tool", Arguments: "{}"}},
+		{ID: "call_2", Type: types.Function, Function: types.ChatCompletionMessageToolCallFunction{Name: "mcp_approved_tool", Arguments: "{}"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"call_1", "call_2"}, asked)
+	require.Len(t, results, 2)
+	denied, _ := results[0].Content.AsMessageContent0()
+	assert.Equal(t, "Error: the tool call was denied by the user", denied)
+	approved, _ := results[1].Content.AsMessageContent0()
+	assert.Contains(t, approved, "done")
+
+	// An approver failing, e.g. once the client is gone, stops the agent
+	agentInstance.SetToolApprover(func(ctx context.Context, toolCall types.ChatCompletionMessageToolCall) (bool, error) {
+		return false, context.Canceled
+	})
+	_, err = agentInstance.ExecuteTools(context.Background(), []types.ChatCompletionMessageToolCall{
+		{ID: "call_3", Type: types.Function, Function: types.ChatCompletionMessageToolCallFunction{Name: "mcp_denied_tool", Arguments: "{}"}},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestAgent_RunWithStream(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -530,17 +579,17 @@ func TestAgent_RunWithStream(t *testing.T) {
 				mockProvider.EXPECT().GetName().Return("test-provider").Times(1)
 				mockLogger.EXPECT().Debug("provider set for agent", "provider", "test-provider").Times(1)
 				mockLogger.EXPECT().Debug("model set for agent", "model", "test-model").Times(1)
-				streamCh := make(chan []byte, 10)
+				streamCh := make(chan core.StreamEvent, 10)
 				go func() {
-					streamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"Hello"},"finish_reason":null}]}`)
+					streamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"Hello"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					streamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":" ther"},"finish_reason":null}]}`)
+					streamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":" ther"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					streamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"e!"},"finish_reason":null}]}`)
+					streamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"e!"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					streamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":3,"total_tokens":13}}`)
+					streamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":3,"total_tokens":13}}`)
 					time.Sleep(10 * time.Millisecond)
-					streamCh <- []byte(`data: [DONE]`)
+					streamCh <- streamChunk(`[DONE]`)
 					defer close(streamCh)
 				}()
 
@@ -637,7 +686,7 @@ func TestAgent_RunWithStream(t *testing.T) {
 				mockProvider.EXPECT().GetName().Return("test-provider").Times(1)
 				mockLogger.EXPECT().Debug("provider set for agent", "provider", "test-provider").Times(1)
 				mockLogger.EXPECT().Debug("model set for agent", "model", "test-model").Times(1)
-				streamCh := make(chan []byte)
+				streamCh := make(chan core.StreamEvent)
 
 				mockLogger.EXPECT().Debug("starting agent streaming", "model", "test-model", "max_iterations", 10).Times(1)
 				mockLogger.EXPECT().Debug("streaming iteration", "iteration", 1, "max_iterations", 10).Times(1)
@@ -673,72 +722,70 @@ func TestAgent_RunWithStream(t *testing.T) {
 				mockProvider.EXPECT().GetName().Return("test-provider").Times(1)
 				mockLogger.EXPECT().Debug("provider set for agent", "provider", "test-provider").Times(1)
 				mockLogger.EXPECT().Debug("model set for agent", "model", "test-model").Times(1)
-				firstStreamCh := make(chan []byte, 15)
+				firstStreamCh := make(chan core.StreamEvent, 15)
 				go func() {
 					time.Sleep(10 * time.Millisecond)
 
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"I'll "},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"I'll "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"use "},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"use "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"both "},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"both "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"tools"},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"tools"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":" to "},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":" to "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"help "},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"help "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"content":"you."},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"content":"you."},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
 
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_123","type":"function","function":{"name":"mcp_test_tool","arguments":"{\"param\":"}}]},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_123","type":"function","function":{"name":"mcp_test_tool","arguments":"{\"param\":"}}]},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"value\"}"}}]},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"value\"}"}}]},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_456","type":"function","function":{"name":"mcp_other_tool","arguments":"{\"action\":"}}]},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_456","type":"function","function":{"name":"mcp_other_tool","arguments":"{\"action\":"}}]},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":"\"execute\"}"}}]},"finish_reason":null}]}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":"\"execute\"}"}}]},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: {"id":"test","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":15,"completion_tokens":8,"total_tokens":23}}`)
+					firstStreamCh <- streamChunk(`{"id":"test","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":15,"completion_tokens":8,"total_tokens":23}}`)
 					time.Sleep(10 * time.Millisecond)
-					firstStreamCh <- []byte(`data: [DONE]`)
 					close(firstStreamCh)
 				}()
 
-				secondStreamCh := make(chan []byte, 15)
+				secondStreamCh := make(chan core.StreamEvent, 15)
 				go func() {
 					time.Sleep(50 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"Based"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"Based"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":" on "},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":" on "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"the "},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"the "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"tool "},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"tool "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"resul"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"resul"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"ts, "},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"ts, "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"both "},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"both "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"tools"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"tools"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":" exec"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":" exec"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"uted "},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"uted "},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"succe"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"succe"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"ssful"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"ssful"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{"content":"ly!"},"finish_reason":null}]}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{"content":"ly!"},"finish_reason":null}]}`)
 					time.Sleep(10 * time.Millisecond)
 
-					secondStreamCh <- []byte(`data: {"id":"test2","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":25,"completion_tokens":12,"total_tokens":37}}`)
+					secondStreamCh <- streamChunk(`{"id":"test2","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":25,"completion_tokens":12,"total_tokens":37}}`)
 					time.Sleep(10 * time.Millisecond)
-					secondStreamCh <- []byte(`data: [DONE]`)
 					close(secondStreamCh)
 				}()
 
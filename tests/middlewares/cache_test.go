@@ -0,0 +1,358 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+
+	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+)
+
+const cachedCompletion = `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4o","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`
+
+const streamedCompletion = "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hel\"}}]}\n\n" +
+	"data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}]}\n\n" +
+	"data: [DONE]\n\n"
+
+func cacheConfig(deterministicOnly bool) config.Config {
+	return config.Config{
+		Server: &config.ServerConfig{},
+		Cache: &config.CacheConfig{
+			Enabled:           true,
+			Backend:           "memory",
+			Ttl:               time.Minute,
+			MaxEntries:        10,
+			DeterministicOnly: deterministicOnly,
+		},
+	}
+}
+
+// newCacheRouter returns a router whose chat completions handler counts its
+// calls and answers like an upstream provider would.
+func newCacheRouter(t *testing.T, cfg config.Config, telemetry otel.OpenTelemetry, status int) (*gin.Engine, *int) {
+	t.Helper()
+	m, err := middlewares.NewResponseCacheMiddleware(cfg, nil, nil, telemetry, logger.NewNoopLogger())
+	require.NoError(t, err)
+	return cacheRouter(t, m, cfg, status)
+}
+
+func cacheRouter(t *testing.T, m middlewares.ResponseCacheMiddleware, cfg config.Config, status int) (*gin.Engine, *int) {
+	t.Helper()
+
+	calls := 0
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.POST("/v1/chat/completions", func(c *gin.Context) {
+		calls++
+		req, err := middlewares.GetRequestBody(c, cfg).ChatCompletionRequest()
+		require.NoError(t, err)
+		if req.Stream != nil && *req.Stream {
+			middlewares.SetSSEHeaders(c)
+			c.String(status, streamedCompletion)
+			return
+		}
+		c.Data(status, "application/json", []byte(cachedCompletion))
+	})
+	return r, &calls
+}
+
+func postChat(r http.Handler, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCacheMiddleware_Disabled(t *testing.T) {
+	m, err := middlewares.NewResponseCacheMiddleware(config.Config{Cache: &config.CacheConfig{}}, nil, nil, nil, logger.NewNoopLogger())
+	require.NoError(t, err)
+	_, ok := m.(*middlewares.NoopResponseCacheMiddlewareImpl)
+	assert.True(t, ok)
+
+	_, err = middlewares.NewResponseCacheMiddleware(config.Config{Cache: &config.CacheConfig{Enabled: true, Backend: "redis"}}, nil, nil, nil, logger.NewNoopLogger())
+	assert.Error(t, err)
+}
+
+func TestResponseCacheMiddleware_HitAfterMiss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetry := mocks.NewMockOpenTelemetry(ctrl)
+	gomock.InOrder(
+		telemetry.EXPECT().RecordCacheLookup(gomock.Any(), otel.SourceGateway, "openai", "openai/gpt-4o", "miss"),
+		telemetry.EXPECT().RecordCacheLookup(gomock.Any(), otel.SourceGateway, "openai", "openai/gpt-4o", "hit"),
+	)
+
+	r, calls := newCacheRouter(t, cacheConfig(true), telemetry, http.StatusOK)
+	body := `{"model":"openai/gpt-4o","temperature":0,"messages":[{"role":"user","content":"hi"}]}`
+
+	first := postChat(r, body)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, middlewares.CacheStatusMiss, first.Header().Get(middlewares.CacheStatusHeader))
+
+	second := postChat(r, body)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, middlewares.CacheStatusHit, second.Header().Get(middlewares.CacheStatusHeader))
+	assert.JSONEq(t, cachedCompletion, second.Body.String())
+	assert.Equal(t, 1, *calls, "the second request must be served from the cache")
+}
+
+func TestResponseCacheMiddleware_Streaming(t *testing.T) {
+	r, calls := newCacheRouter(t, cacheConfig(true), nil, http.StatusOK)
+
+	// A non-streamed completion is replayed as SSE to a streaming client
+	postChat(r, `{"model":"openai/gpt-4o","temperature":0,"messages":[{"role":"user","content":"hi"}]}`)
+	w := postChat(r, `{"model":"openai/gpt-4o","temperature":0,"stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, middlewares.CacheStatusHit, w.Header().Get(middlewares.CacheStatusHeader))
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"content":"Hello"`)
+	assert.Contains(t, w.Body.String(), `"total_tokens":4`)
+	assert.True(t, strings.HasSuffix(w.Body.String(), "data: [DONE]\n\n"))
+
+	// A streamed completion is aggregated and served to non-streaming clients
+	postChat(r, `{"model":"openai/gpt-4o","temperature":0,"stream":true,"messages":[{"role":"user","content":"again"}]}`)
+	w = postChat(r, `{"model":"openai/gpt-4o","temperature":0,"messages":[{"role":"user","content":"again"}]}`)
+	assert.Equal(t, 2, *calls)
+	assert.Equal(t, middlewares.CacheStatusHit, w.Header().Get(middlewares.CacheStatusHeader))
+	assert.Contains(t, w.Body.String(), `"content":"Hello"`)
+}
+
+func TestResponseCacheMiddleware_Bypass(t *testing.T) {
+	body := `{"model":"openai/gpt-4o","temperature":0,"messages":[{"role":"user","content":"hi"}]}`
+
+	tests := []struct {
+		name              string
+		cfg               config.Config
+		status            int
+		body              string
+		headers           []string
+		expectedCalls     int
+		expectedSecondHit string
+	}{
+		{
+			name:              "NoCacheRefreshesEntry",
+			cfg:               cacheConfig(true),
+			status:            http.StatusOK,
+			body:              body,
+			headers:           []string{"Cache-Control", "no-cache"},
+			expectedCalls:     2,
+			expectedSecondHit: middlewares.CacheStatusBypass,
+		},
+		{
+			name:              "NoStoreSkipsStoring",
+			cfg:               cacheConfig(true),
+			status:            http.StatusOK,
+			body:              body,
+			headers:           []string{"Cache-Control", "no-store"},
+			expectedCalls:     2,
+			expectedSecondHit: middlewares.CacheStatusMiss,
+		},
+		{
+			name:          "NonDeterministicRequest",
+			cfg:           cacheConfig(true),
+			status:        http.StatusOK,
+			body:          `{"model":"openai/gpt-4o","temperature":0.7,"messages":[{"role":"user","content":"hi"}]}`,
+			expectedCalls: 2,
+		},
+		{
+			name:              "NonDeterministicRequestCachedWhenAllowed",
+			cfg:               cacheConfig(false),
+			status:            http.StatusOK,
+			body:              `{"model":"openai/gpt-4o","temperature":0.7,"messages":[{"role":"user","content":"hi"}]}`,
+			expectedCalls:     1,
+			expectedSecondHit: middlewares.CacheStatusHit,
+		},
+		{
+			name:              "ErrorsAreNotCached",
+			cfg:               cacheConfig(true),
+			status:            http.StatusTooManyRequests,
+			body:              body,
+			expectedCalls:     2,
+			expectedSecondHit: middlewares.CacheStatusMiss,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, calls := newCacheRouter(t, tt.cfg, nil, tt.status)
+			postChat(r, tt.body, tt.headers...)
+			w := postChat(r, tt.body, tt.headers...)
+			assert.Equal(t, tt.expectedCalls, *calls)
+			assert.Equal(t, tt.expectedSecondHit, w.Header().Get(middlewares.CacheStatusHeader))
+		})
+	}
+}
+
+// newSemanticCacheRouter returns a cache router whose embeddings come from a
+// fake Ollama server mapping each prompt to a fixed vector.
+func newSemanticCacheRouter(t *testing.T, telemetry otel.OpenTelemetry, vectors map[string][]float32) (*gin.Engine, *int, *int) {
+	t.Helper()
+	return newSemanticCacheRouterWithConfig(t, cacheConfig(false), telemetry, vectors)
+}
+
+func newSemanticCacheRouterWithConfig(t *testing.T, cfg config.Config, telemetry otel.OpenTelemetry, vectors map[string][]float32) (*gin.Engine, *int, *int) {
+	t.Helper()
+
+	embeddings := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		embeddings++
+		var req struct {
+			Model string `json:"model"`
+			Input string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "nomic-embed-text", req.Model)
+		vector, ok := vectors[req.Input]
+		if !ok {
+			http.Error(w, "unknown input", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"embedding": vector}}})
+	}))
+	t.Cleanup(server.Close)
+
+	ctrl := gomock.NewController(t)
+	httpClient := providersmocks.NewMockClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(http.DefaultClient.Do).AnyTimes()
+
+	providerCfg := map[types.Provider]*registry.ProviderConfig{
+		constants.OllamaID: {
+			ID:       constants.OllamaID,
+			Name:     "Ollama",
+			URL:      server.URL + "/v1",
+			AuthType: constants.AuthTypeNone,
+		},
+	}
+	cfg.Cache.SemanticEnabled = true
+	cfg.Cache.SemanticProvider = string(constants.OllamaID)
+	cfg.Cache.SemanticModel = "nomic-embed-text"
+	cfg.Cache.SemanticThreshold = 0.95
+
+	m, err := middlewares.NewResponseCacheMiddleware(cfg, registry.NewProviderRegistry(providerCfg, logger.NewNoopLogger()), httpClient, telemetry, logger.NewNoopLogger())
+	require.NoError(t, err)
+	r, calls := cacheRouter(t, m, cfg, http.StatusOK)
+	return r, calls, &embeddings
+}
+
+func TestResponseCacheMiddleware_Semantic(t *testing.T) {
+	vectors := map[string][]float32{
+		"What is the capital of France?":    {0.9, 0.1, 0},
+		"what's the capital city of France": {0.88, 0.12, 0.01},
+		"France's capital?":                 {0.91, 0.09, 0.02},
+		"Tell me a joke":                    {0, 0.2, 0.9},
+	}
+	chat := func(prompt string, extra string) string {
+		return `{"model":"openai/gpt-4o",` + extra + `"messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"` + prompt + `"}]}`
+	}
+
+	t.Run("SimilarPromptIsServedFromCache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		telemetry := mocks.NewMockOpenTelemetry(ctrl)
+		gomock.InOrder(
+			telemetry.EXPECT().RecordCacheLookup(gomock.Any(), otel.SourceGateway, "openai", "openai/gpt-4o", "miss"),
+			telemetry.EXPECT().RecordCacheLookup(gomock.Any(), otel.SourceGateway, "openai", "openai/gpt-4o", "semantic_hit"),
+			telemetry.EXPECT().RecordCacheLookup(gomock.Any(), otel.SourceGateway, "openai", "openai/gpt-4o", "semantic_hit"),
+		)
+		r, calls, embeddings := newSemanticCacheRouter(t, telemetry, vectors)
+
+		first := postChat(r, chat("What is the capital of France?", ""))
+		assert.Equal(t, middlewares.CacheStatusMiss, first.Header().Get(middlewares.CacheStatusHeader))
+
+		second := postChat(r, chat("what's the capital city of France", ""))
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, middlewares.CacheStatusSemanticHit, second.Header().Get(middlewares.CacheStatusHeader))
+		assert.NotEmpty(t, second.Header().Get(middlewares.CacheSimilarityHeader))
+		assert.JSONEq(t, cachedCompletion, second.Body.String())
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, 2, *embeddings, "the stored request must be embedded once")
+
+		stream := postChat(r, chat("what's the capital city of France", `"stream":true,`))
+		assert.Equal(t, middlewares.CacheStatusSemanticHit, stream.Header().Get(middlewares.CacheStatusHeader))
+		assert.True(t, strings.HasSuffix(stream.Body.String(), "data: [DONE]\n\n"))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("DissimilarPromptGoesUpstream", func(t *testing.T) {
+		r, calls, _ := newSemanticCacheRouter(t, nil, vectors)
+		postChat(r, chat("What is the capital of France?", ""))
+		w := postChat(r, chat("Tell me a joke", ""))
+		assert.Equal(t, middlewares.CacheStatusMiss, w.Header().Get(middlewares.CacheStatusHeader))
+		assert.Empty(t, w.Header().Get(middlewares.CacheSimilarityHeader))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("ScopedPerIdentity", func(t *testing.T) {
+		r, calls, _ := newSemanticCacheRouter(t, nil, vectors)
+		withSubject := func(subject string) gin.HandlerFunc {
+			return func(c *gin.Context) {
+				claims := map[string]any{"sub": subject}
+				c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), types.ClaimsContextKey, claims))
+			}
+		}
+		alice := gin.New()
+		alice.Use(withSubject("alice"))
+		alice.Any("/*path", func(c *gin.Context) { r.HandleContext(c) })
+		bob := gin.New()
+		bob.Use(withSubject("bob"))
+		bob.Any("/*path", func(c *gin.Context) { r.HandleContext(c) })
+
+		postChat(alice, chat("What is the capital of France?", ""))
+		w := postChat(bob, chat("what's the capital city of France", ""))
+		assert.Equal(t, middlewares.CacheStatusMiss, w.Header().Get(middlewares.CacheStatusHeader))
+		w = postChat(alice, chat("France's capital?", ""))
+		assert.Equal(t, middlewares.CacheStatusSemanticHit, w.Header().Get(middlewares.CacheStatusHeader))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("NonDeterministicRequestsUseSemanticCacheByDefault", func(t *testing.T) {
+		r, calls, _ := newSemanticCacheRouterWithConfig(t, cacheConfig(true), nil, vectors)
+		postChat(r, chat("What is the capital of France?", `"temperature":0.7,`))
+		w := postChat(r, chat("What is the capital of France?", `"temperature":0.7,`))
+		assert.Equal(t, middlewares.CacheStatusSemanticHit, w.Header().Get(middlewares.CacheStatusHeader), "CACHE_DETERMINISTIC_ONLY only applies to exact matches")
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("SemanticDeterministicOnly", func(t *testing.T) {
+		cfg := cacheConfig(true)
+		cfg.Cache.SemanticDeterministicOnly = true
+		r, calls, embeddings := newSemanticCacheRouterWithConfig(t, cfg, nil, vectors)
+		postChat(r, chat("What is the capital of France?", `"temperature":0.7,`))
+		w := postChat(r, chat("what's the capital city of France", `"temperature":0.7,`))
+		assert.Empty(t, w.Header().Get(middlewares.CacheStatusHeader))
+		assert.Equal(t, 2, *calls)
+		assert.Equal(t, 0, *embeddings)
+	})
+
+	t.Run("EmbeddingFailureFallsThrough", func(t *testing.T) {
+		r, calls, _ := newSemanticCacheRouter(t, nil, map[string][]float32{})
+		postChat(r, chat("What is the capital of France?", ""))
+		w := postChat(r, chat("What is the capital of France?", ""))
+		assert.Equal(t, middlewares.CacheStatusHit, w.Header().Get(middlewares.CacheStatusHeader), "the exact cache must keep working")
+		w = postChat(r, chat("Tell me a joke", ""))
+		assert.Equal(t, middlewares.CacheStatusMiss, w.Header().Get(middlewares.CacheStatusHeader))
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -2,8 +2,10 @@ package middleware_test
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -16,9 +18,12 @@ import (
 	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
 	config "github.com/inference-gateway/inference-gateway/config"
 	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	registry "github.com/inference-gateway/inference-gateway/providers/registry"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 
 	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
 )
 
 func TestNewGuardrailsMiddleware(t *testing.T) {
@@ -55,7 +60,7 @@ func TestNewGuardrailsMiddleware(t *testing.T) {
 				mockLogger.EXPECT().Info("guardrails disabled, using no-op middleware")
 			}
 
-			mw := middlewares.NewGuardrailsMiddleware(nil, nil, nil, mockLogger, nil, cfg)
+			mw := middlewares.NewGuardrailsMiddleware(nil, nil, nil, nil, mockLogger, nil, cfg)
 			assert.NotNil(t, mw)
 			handler := mw.Middleware()
 			assert.NotNil(t, handler)
@@ -76,7 +81,7 @@ func TestGuardrailsMiddleware_Noop(t *testing.T) {
 		},
 	}
 
-	mw := middlewares.NewGuardrailsMiddleware(nil, nil, nil, mockLogger, nil, cfg)
+	mw := middlewares.NewGuardrailsMiddleware(nil, nil, nil, nil, mockLogger, nil, cfg)
 
 	router := gin.New()
 	router.Use(mw.Middleware())
@@ -120,7 +125,7 @@ func TestGuardrailsMiddleware_BlockRequest(t *testing.T) {
 	evaluator, err := guardrails.NewEvaluator(context.Background(), "")
 	assert.NoError(t, err)
 
-	mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, mockLogger, nil, cfg)
+	mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, nil, mockLogger, nil, cfg)
 
 	router := gin.New()
 	router.Use(mw.Middleware())
@@ -254,7 +259,7 @@ func TestGuardrailsMiddleware_NonStreamingPostCall(t *testing.T) {
 	evaluator, err := guardrails.NewEvaluator(context.Background(), "")
 	assert.NoError(t, err)
 
-	mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, mockLogger, nil, cfg)
+	mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, nil, mockLogger, nil, cfg)
 
 	router := gin.New()
 	router.Use(mw.Middleware())
@@ -295,3 +300,308 @@ func TestGuardrailsMiddleware_NonStreamingPostCall(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Hello! How can I help you?", content)
 }
+
+// moderationUpstream is a fake Moderations API flagging inputs that mention
+// "attack" as violence and recording what it was asked to classify
+func moderationUpstream(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var inputs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderations", r.URL.Path)
+		assert.Equal(t, "Bearer test-openai-key", r.Header.Get("Authorization"))
+		var req struct{ Model, Input string }
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "omni-moderation-latest", req.Model)
+		inputs = append(inputs, req.Input)
+		violence := strings.Contains(req.Input, "attack")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":    "modr-1",
+			"model": "omni-moderation-latest",
+			"results": []map[string]any{{
+				"flagged":    violence,
+				"categories": map[string]bool{"hate": false, "violence": violence, "self-harm": false},
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &inputs
+}
+
+// newModerationClassifier classifies with omni-moderation-latest on an openai
+// provider served by upstreamURL
+func newModerationClassifier(t *testing.T, upstreamURL string, log *mocks.MockLogger) *guardrails.ModerationClassifier {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	httpClient := providersmocks.NewMockClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(http.DefaultClient.Do).AnyTimes()
+
+	providers := map[types.Provider]*registry.ProviderConfig{
+		constants.OpenaiID: {
+			ID:        constants.OpenaiID,
+			URL:       upstreamURL,
+			Token:     "test-openai-key",
+			AuthType:  constants.AuthTypeBearer,
+			Endpoints: registry.Registry[constants.OpenaiID].Endpoints,
+		},
+	}
+	return guardrails.NewModerationClassifier(registry.NewProviderRegistry(providers, log), httpClient, constants.OpenaiID, "omni-moderation-latest")
+}
+
+func TestGuardrailsMiddleware_Moderation(t *testing.T) {
+	upstream, inputs := moderationUpstream(t)
+
+	ctrl := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	classifier := newModerationClassifier(t, upstream.URL, mockLogger)
+
+	tests := []struct {
+		name       string
+		categories string
+		phases     string
+		path       string
+		request    string
+		reply      string
+		status     int
+		message    string
+	}{
+		{
+			name:    "flagged request",
+			phases:  "pre_call,post_call",
+			path:    "/v1/chat/completions",
+			request: `{"model":"openai/gpt-4o","messages":[{"role":"user","content":[{"type":"text","text":"plan an attack"}]}]}`,
+			status:  http.StatusForbidden,
+			message: "content flagged by moderation: violence",
+		},
+		{
+			name:    "flagged response",
+			phases:  "pre_call,post_call",
+			path:    "/v1/chat/completions",
+			request: `{"model":"openai/gpt-4o","messages":[{"role":"user","content":"Tell me a story"}]}`,
+			reply:   "The knights attack at dawn.",
+			status:  http.StatusForbidden,
+			message: "content flagged by moderation: violence",
+		},
+		{
+			name:    "response moderation disabled",
+			phases:  "pre_call",
+			path:    "/v1/chat/completions",
+			request: `{"model":"openai/gpt-4o","messages":[{"role":"user","content":"Tell me a story"}]}`,
+			reply:   "The knights attack at dawn.",
+			status:  http.StatusOK,
+		},
+		{
+			name:       "category not blocked",
+			categories: "hate,self-harm",
+			phases:     "pre_call,post_call",
+			path:       "/v1/chat/completions",
+			request:    `{"model":"openai/gpt-4o","messages":[{"role":"user","content":"plan an attack"}]}`,
+			status:     http.StatusOK,
+		},
+		{
+			name:    "moderations api is not moderated",
+			phases:  "pre_call,post_call",
+			path:    "/v1/moderations",
+			request: `{"model":"openai/omni-moderation-latest","input":"plan an attack"}`,
+			status:  http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*inputs = nil
+			moderation, err := guardrails.NewModeration(classifier, tt.categories, tt.phases)
+			assert.NoError(t, err)
+			evaluator, err := guardrails.NewEvaluator(context.Background(), "")
+			assert.NoError(t, err)
+
+			cfg := config.Config{
+				Guardrails: &config.GuardrailsConfig{Enabled: true, FailMode: guardrails.FailModeClosed},
+				Server:     &config.ServerConfig{MaxRequestBodySize: 10485760},
+			}
+			mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, moderation, mockLogger, nil, cfg)
+
+			router := gin.New()
+			router.Use(mw.Middleware())
+			router.POST(tt.path, func(c *gin.Context) {
+				c.JSON(http.StatusOK, types.CreateChatCompletionResponse{
+					ID:    "chatcmpl-1",
+					Model: "gpt-4o",
+					Choices: []types.ChatCompletionChoice{{
+						Message:      types.NewTextMessage(t, types.Assistant, cmp.Or(tt.reply, "Once upon a time")),
+						FinishReason: types.Stop,
+					}},
+				})
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.request))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.message != "" {
+				var body map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, tt.message, body["message"])
+			}
+			for _, input := range *inputs {
+				assert.NotContains(t, input, "gpt-4o", "only the content is classified")
+			}
+		})
+	}
+}
+
+func TestGuardrailsMiddleware_ModerationMultipart(t *testing.T) {
+	upstream, inputs := moderationUpstream(t)
+
+	ctrl := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	moderation, err := guardrails.NewModeration(newModerationClassifier(t, upstream.URL, mockLogger), "", "pre_call")
+	assert.NoError(t, err)
+	evaluator, err := guardrails.NewEvaluator(context.Background(), "")
+	assert.NoError(t, err)
+	cfg := config.Config{
+		Guardrails: &config.GuardrailsConfig{Enabled: true, FailMode: guardrails.FailModeClosed},
+		Server:     &config.ServerConfig{MaxRequestBodySize: 10485760},
+	}
+	mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, moderation, mockLogger, nil, cfg)
+
+	router := gin.New()
+	router.Use(mw.Middleware())
+	router.POST("/v1/audio/transcriptions", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": "Hello"})
+	})
+
+	tests := []struct {
+		name   string
+		prompt string
+		status int
+	}{
+		{name: "flagged prompt", prompt: "transcribe the attack plan", status: http.StatusForbidden},
+		{name: "file is not classified", prompt: "a meeting recording", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*inputs = nil
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			assert.NoError(t, form.WriteField("model", "openai/whisper-1"))
+			assert.NoError(t, form.WriteField("prompt", tt.prompt))
+			file, err := form.CreateFormFile("file", "audio.mp3")
+			assert.NoError(t, err)
+			_, err = file.Write([]byte("ID3\x00\x01attack\xff\xfe"))
+			assert.NoError(t, err)
+			assert.NoError(t, form.Close())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/v1/audio/transcriptions", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			assert.Equal(t, []string{tt.prompt}, *inputs, "only the text fields of the form are classified")
+		})
+	}
+}
+
+func TestModeration_PostCallBodies(t *testing.T) {
+	upstream, inputs := moderationUpstream(t)
+
+	ctrl := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	moderation, err := guardrails.NewModeration(newModerationClassifier(t, upstream.URL, mockLogger), "", "post_call")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		action      string
+		classified  []string
+	}{
+		{
+			name:        "response_format=text transcription",
+			contentType: "text/plain; charset=utf-8",
+			body:        "The knights attack at dawn.\n",
+			action:      guardrails.ActionBlock,
+			classified:  []string{"The knights attack at dawn."},
+		},
+		{
+			name:        "response_format=vtt transcription",
+			contentType: "text/vtt",
+			body:        "WEBVTT\n\n00:00:00.000 --> 00:00:02.000\nThe knights attack at dawn.",
+			action:      guardrails.ActionBlock,
+			classified:  []string{"WEBVTT\n\n00:00:00.000 --> 00:00:02.000\nThe knights attack at dawn."},
+		},
+		{
+			name:        "speech audio",
+			contentType: "audio/mpeg",
+			body:        "ID3\x00attack",
+			action:      guardrails.ActionAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*inputs = nil
+			dec, err := moderation.Check(context.Background(), guardrails.PhasePostCall, tt.contentType, tt.body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.action, dec.Action)
+			assert.Equal(t, tt.classified, *inputs)
+		})
+	}
+}
+
+func TestGuardrailsMiddleware_ModerationFailMode(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	classifier := newModerationClassifier(t, upstream.URL, mockLogger)
+	moderation, err := guardrails.NewModeration(classifier, "", "pre_call")
+	assert.NoError(t, err)
+	evaluator, err := guardrails.NewEvaluator(context.Background(), "")
+	assert.NoError(t, err)
+
+	for failMode, status := range map[string]int{guardrails.FailModeClosed: http.StatusForbidden, guardrails.FailModeOpen: http.StatusOK} {
+		t.Run(failMode, func(t *testing.T) {
+			cfg := config.Config{
+				Guardrails: &config.GuardrailsConfig{Enabled: true, FailMode: failMode},
+				Server:     &config.ServerConfig{MaxRequestBodySize: 10485760},
+			}
+			mw := middlewares.NewGuardrailsMiddleware(evaluator, nil, nil, moderation, mockLogger, nil, cfg)
+
+			router := gin.New()
+			router.Use(mw.Middleware())
+			router.POST("/v1/responses", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "completed"})
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/v1/responses", strings.NewReader(`{"model":"openai/gpt-4o","input":"Hello"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, status, w.Code)
+		})
+	}
+}
+
+func TestNewModeration_RejectsUnknownPhases(t *testing.T) {
+	_, err := guardrails.NewModeration(nil, "", "pre_call,tool_args")
+	assert.EqualError(t, err, `guardrails: unsupported moderation phase "tool_args": expected pre_call or post_call`)
+}
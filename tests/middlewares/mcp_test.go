@@ -18,6 +18,7 @@ import (
 	config "github.com/inference-gateway/inference-gateway/config"
 	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
 	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 
 	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
@@ -29,6 +30,15 @@ func init() {
 	gin.SetMode(gin.TestMode)
 }
 
+// streamChunk is the stream event a provider emits for a chunk
+func streamChunk(data string) core.StreamEvent {
+	var chunk types.CreateChatCompletionStreamResponse
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		return core.StreamEvent{Err: err}
+	}
+	return core.StreamEvent{Chunk: &chunk, Data: []byte(data), Usage: chunk.Usage}
+}
+
 // Test helper to create mock dependencies for each test case
 func createMockDependencies(t *testing.T) (*gomock.Controller, *providersmocks.MockProviderRegistry, *providersmocks.MockClient, *mcpmocks.MockMCPClientInterface, *mocks.MockLogger, *providersmocks.MockIProvider) {
 	ctrl := gomock.NewController(t)
@@ -339,14 +349,12 @@ func TestMCPMiddleware_SelectorModeInjectsMetaTools(t *testing.T) {
 	router := gin.New()
 	router.Use(middleware.Middleware())
 	router.POST("/v1/chat/completions", func(c *gin.Context) {
-		var req types.CreateChatCompletionRequest
-		val, _ := c.Get("X-MCP-Bypass")
-		if r, ok := val.(*types.CreateChatCompletionRequest); ok && r.Tools != nil {
+		r, err := middlewares.GetRequestBody(c, cfg).ChatCompletionRequest()
+		if err == nil && r.Tools != nil {
 			for _, tool := range *r.Tools {
 				injectedToolNames = append(injectedToolNames, tool.Function.Name)
 			}
 		}
-		_ = req
 		c.JSON(http.StatusOK, types.CreateChatCompletionResponse{
 			ID:    "test-id",
 			Model: "gpt-3.5-turbo",
@@ -590,8 +598,8 @@ data: [DONE]`,
 
 				mockLogger.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-				streamCh := make(chan []byte, 1)
-				streamCh <- []byte(`{"id":"chatcmpl-124","object":"chat.completion.chunk","created":1677652289,"model":"gpt-3.5-turbo","choices":[{"index":0,"delta":{"content":"Response after tool execution"},"finish_reason":"stop"}]}`)
+				streamCh := make(chan core.StreamEvent, 1)
+				streamCh <- streamChunk(`{"id":"chatcmpl-124","object":"chat.completion.chunk","created":1677652289,"model":"gpt-3.5-turbo","choices":[{"index":0,"delta":{"content":"Response after tool execution"},"finish_reason":"stop"}]}`)
 				close(streamCh)
 
 				mockProvider.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).Return(streamCh, nil).AnyTimes()
@@ -814,7 +822,7 @@ func TestMCPMiddleware_StreamingWithMultipleToolCallIterations(t *testing.T) {
 		model := "groq/meta-llama/llama-4-scout-17b-instruct"
 		agentImpl.SetModel(&model)
 
-		firstStreamCh := make(chan []byte, 10)
+		firstStreamCh := make(chan core.StreamEvent, 10)
 		go func() {
 			defer close(firstStreamCh)
 			chunks := []string{
@@ -827,12 +835,11 @@ func TestMCPMiddleware_StreamingWithMultipleToolCallIterations(t *testing.T) {
 				`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1748534842,"model":"meta-llama/llama-4-scout-17b-instruct","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
 			}
 			for _, chunk := range chunks {
-				firstStreamCh <- []byte("data: " + chunk)
+				firstStreamCh <- streamChunk(chunk)
 			}
-			firstStreamCh <- []byte("data: [DONE]")
 		}()
 
-		secondStreamCh := make(chan []byte, 10)
+		secondStreamCh := make(chan core.StreamEvent, 10)
 		go func() {
 			defer close(secondStreamCh)
 			chunks := []string{
@@ -845,12 +852,11 @@ func TestMCPMiddleware_StreamingWithMultipleToolCallIterations(t *testing.T) {
 				`{"id":"chatcmpl-2","object":"chat.completion.chunk","created":1748534842,"model":"meta-llama/llama-4-scout-17b-instruct","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
 			}
 			for _, chunk := range chunks {
-				secondStreamCh <- []byte("data: " + chunk)
+				secondStreamCh <- streamChunk(chunk)
 			}
-			secondStreamCh <- []byte("data: [DONE]")
 		}()
 
-		thirdStreamCh := make(chan []byte, 10)
+		thirdStreamCh := make(chan core.StreamEvent, 10)
 		go func() {
 			defer close(thirdStreamCh)
 			chunks := []string{
@@ -867,10 +873,9 @@ func TestMCPMiddleware_StreamingWithMultipleToolCallIterations(t *testing.T) {
 				`{"id":"chatcmpl-3","object":"chat.completion.chunk","created":1748534842,"model":"meta-llama/llama-4-scout-17b-instruct","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
 			}
 			for _, chunk := range chunks {
-				thirdStreamCh <- []byte("data: " + chunk)
+				thirdStreamCh <- streamChunk(chunk)
 			}
 
-			thirdStreamCh <- []byte("data: [DONE]")
 		}()
 
 		call1 := mockProvider.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).Return(firstStreamCh, nil).Times(1)
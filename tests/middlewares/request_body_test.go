@@ -0,0 +1,210 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
+)
+
+// countingReader counts how many times the request body is read to EOF
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err == io.EOF {
+		c.reads++
+	}
+	return n, err
+}
+
+func requestBodyConfig(maxBytes int) config.Config {
+	return config.Config{Server: &config.ServerConfig{MaxRequestBodySize: maxBytes}}
+}
+
+func TestRequestBody_ReadOnceAndShared(t *testing.T) {
+	cfg := requestBodyConfig(1 << 20)
+	body := &countingReader{r: strings.NewReader(`{"model":"openai/gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`)}
+
+	var first, second *types.CreateChatCompletionRequest
+	var downstream []byte
+
+	r := gin.New()
+	r.Use(middlewares.NewRequestBodyMiddleware(cfg).Middleware())
+	r.Use(func(c *gin.Context) {
+		var err error
+		first, err = middlewares.GetRequestBody(c, cfg).ChatCompletionRequest()
+		require.NoError(t, err)
+		// Middlewares such as the OIDC authenticator replace c.Request
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), types.ContextKey("k"), "v"))
+		c.Next()
+	})
+	r.POST("/v1/chat/completions", func(c *gin.Context) {
+		var err error
+		second, err = middlewares.GetRequestBody(c, cfg).ChatCompletionRequest()
+		require.NoError(t, err)
+		downstream, err = io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", io.NopCloser(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, body.reads, "body must be read from the client exactly once")
+	assert.Same(t, first, second, "the decoded request must be shared")
+	assert.Equal(t, "openai/gpt-4o", second.Model)
+	assert.JSONEq(t, `{"model":"openai/gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`, string(downstream),
+		"raw readers downstream must still see the body")
+}
+
+func TestRequestBody_Replace(t *testing.T) {
+	cfg := requestBodyConfig(1 << 20)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"a"}`))
+
+	body := middlewares.GetRequestBody(c, cfg)
+	original, err := body.ChatCompletionRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "a", original.Model)
+
+	body.SetBytes([]byte(`{"model":"b"}`))
+	redacted, err := body.ChatCompletionRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "b", redacted.Model, "SetBytes must discard the previously decoded request")
+	assert.Equal(t, "a", original.Model, "earlier readers keep their view")
+
+	replaced := *redacted
+	replaced.Model = "c"
+	body.SetChatCompletionRequest(&replaced)
+	got, err := middlewares.GetRequestBody(c, cfg).ChatCompletionRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "c", got.Model)
+}
+
+func TestRequestBody_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		maxBytes    int
+		expectedErr error
+	}{
+		{name: "TooLarge", body: `{"model":"openai/gpt-4o"}`, maxBytes: 8, expectedErr: middlewares.ErrRequestBodyTooLarge},
+		{name: "InvalidJSON", body: `{"model":`, maxBytes: 1 << 20},
+		{name: "ExactlyAtLimit", body: `{"model":"x"}`, maxBytes: len(`{"model":"x"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(tt.body))
+			body := middlewares.GetRequestBody(c, requestBodyConfig(tt.maxBytes))
+
+			raw, err := body.Bytes()
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				_, err = body.ChatCompletionRequest()
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(raw))
+		})
+	}
+}
+
+// multimodalRequestBody builds a chat completion request carrying a ~4 MiB
+// inline image, the case where decoding the body once per consumer hurts.
+func multimodalRequestBody(b *testing.B) []byte {
+	b.Helper()
+	image := "data:image/png;base64," + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0x89, 0x50, 0x4e, 0x47}, 3<<18))
+	req := types.CreateChatCompletionRequest{
+		Model: "openai/gpt-4o",
+		Messages: []types.Message{
+			types.NewMultimodalMessage(b, types.User,
+				types.NewTextContentPart(b, "What is in this image?"),
+				types.NewImageContentPart(b, image, nil),
+			),
+		},
+	}
+	raw, err := json.Marshal(req)
+	require.NoError(b, err)
+	return raw
+}
+
+// BenchmarkRequestBodyDecoding compares the request pipeline decoding the body
+// in every consumer (telemetry, guardrails, MCP and the handler, as before the
+// shared request body) against decoding it once through RequestBody.
+func BenchmarkRequestBodyDecoding(b *testing.B) {
+	raw := multimodalRequestBody(b)
+	cfg := requestBodyConfig(64 << 20)
+
+	perConsumer := func(c *gin.Context) {
+		// telemetry
+		bodyBytes, _ := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		var telemetryReq types.CreateChatCompletionRequest
+		_ = json.Unmarshal(bodyBytes, &telemetryReq)
+
+		// guardrails: model and stream flag
+		bodyBytes, _ = io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		var guardrailsReq struct {
+			Model  string `json:"model"`
+			Stream *bool  `json:"stream"`
+		}
+		_ = json.Unmarshal(bodyBytes, &guardrailsReq)
+		_ = json.Unmarshal(bodyBytes, &guardrailsReq)
+
+		// mcp and handler
+		for range 2 {
+			var req types.CreateChatCompletionRequest
+			_ = json.NewDecoder(c.Request.Body).Decode(&req)
+			c.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+	}
+
+	shared := func(c *gin.Context) {
+		body := middlewares.GetRequestBody(c, cfg)
+		for range 4 {
+			req, _ := body.ChatCompletionRequest()
+			_ = *req
+		}
+	}
+
+	for _, bm := range []struct {
+		name    string
+		consume func(c *gin.Context)
+	}{
+		{"PerConsumer", perConsumer},
+		{"Shared", shared},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(raw)))
+			for b.Loop() {
+				c, _ := gin.CreateTestContext(httptest.NewRecorder())
+				c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(raw))
+				bm.consume(c)
+			}
+		})
+	}
+}
@@ -12,8 +12,10 @@ package mcpmocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	guardrails "github.com/inference-gateway/inference-gateway/internal/guardrails"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
 	otel "github.com/inference-gateway/inference-gateway/otel"
 	core "github.com/inference-gateway/inference-gateway/providers/core"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
@@ -266,3 +268,75 @@ func (c *MockAgentSetProviderCall) DoAndReturn(f func(core.IProvider)) *MockAgen
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// SetStreamIdleTimeout mocks base method.
+func (m *MockAgent) SetStreamIdleTimeout(timeout time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetStreamIdleTimeout", timeout)
+}
+
+// SetStreamIdleTimeout indicates an expected call of SetStreamIdleTimeout.
+func (mr *MockAgentMockRecorder) SetStreamIdleTimeout(timeout any) *MockAgentSetStreamIdleTimeoutCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamIdleTimeout", reflect.TypeOf((*MockAgent)(nil).SetStreamIdleTimeout), timeout)
+	return &MockAgentSetStreamIdleTimeoutCall{Call: call}
+}
+
+// MockAgentSetStreamIdleTimeoutCall wrap *gomock.Call
+type MockAgentSetStreamIdleTimeoutCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockAgentSetStreamIdleTimeoutCall) Return() *MockAgentSetStreamIdleTimeoutCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockAgentSetStreamIdleTimeoutCall) Do(f func(time.Duration)) *MockAgentSetStreamIdleTimeoutCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockAgentSetStreamIdleTimeoutCall) DoAndReturn(f func(time.Duration)) *MockAgentSetStreamIdleTimeoutCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetToolApprover mocks base method.
+func (m *MockAgent) SetToolApprover(approver mcp.ToolApprover) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetToolApprover", approver)
+}
+
+// SetToolApprover indicates an expected call of SetToolApprover.
+func (mr *MockAgentMockRecorder) SetToolApprover(approver any) *MockAgentSetToolApproverCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetToolApprover", reflect.TypeOf((*MockAgent)(nil).SetToolApprover), approver)
+	return &MockAgentSetToolApproverCall{Call: call}
+}
+
+// MockAgentSetToolApproverCall wrap *gomock.Call
+type MockAgentSetToolApproverCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockAgentSetToolApproverCall) Return() *MockAgentSetToolApproverCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockAgentSetToolApproverCall) Do(f func(mcp.ToolApprover)) *MockAgentSetToolApproverCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockAgentSetToolApproverCall) DoAndReturn(f func(mcp.ToolApprover)) *MockAgentSetToolApproverCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
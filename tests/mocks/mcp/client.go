@@ -608,3 +608,41 @@ func (c *MockMCPClientInterfaceStopStatusPollingCall) DoAndReturn(f func()) *Moc
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// UpdateServers mocks base method.
+func (m *MockMCPClientInterface) UpdateServers(ctx context.Context, serverURLs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServers", ctx, serverURLs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServers indicates an expected call of UpdateServers.
+func (mr *MockMCPClientInterfaceMockRecorder) UpdateServers(ctx, serverURLs any) *MockMCPClientInterfaceUpdateServersCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServers", reflect.TypeOf((*MockMCPClientInterface)(nil).UpdateServers), ctx, serverURLs)
+	return &MockMCPClientInterfaceUpdateServersCall{Call: call}
+}
+
+// MockMCPClientInterfaceUpdateServersCall wrap *gomock.Call
+type MockMCPClientInterfaceUpdateServersCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMCPClientInterfaceUpdateServersCall) Return(arg0 error) *MockMCPClientInterfaceUpdateServersCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMCPClientInterfaceUpdateServersCall) Do(f func(context.Context, []string) error) *MockMCPClientInterfaceUpdateServersCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMCPClientInterfaceUpdateServersCall) DoAndReturn(f func(context.Context, []string) error) *MockMCPClientInterfaceUpdateServersCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -72,6 +72,18 @@ func (mr *MockOpenTelemetryMockRecorder) Init(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockOpenTelemetry)(nil).Init), arg0, arg1)
 }
 
+// RecordCacheLookup mocks base method.
+func (m *MockOpenTelemetry) RecordCacheLookup(ctx context.Context, source, provider, model, result string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordCacheLookup", ctx, source, provider, model, result)
+}
+
+// RecordCacheLookup indicates an expected call of RecordCacheLookup.
+func (mr *MockOpenTelemetryMockRecorder) RecordCacheLookup(ctx, source, provider, model, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCacheLookup", reflect.TypeOf((*MockOpenTelemetry)(nil).RecordCacheLookup), ctx, source, provider, model, result)
+}
+
 // RecordGuardrail mocks base method.
 func (m *MockOpenTelemetry) RecordGuardrail(ctx context.Context, source, phase, action, path, model string) {
 	m.ctrl.T.Helper()
@@ -84,6 +96,18 @@ func (mr *MockOpenTelemetryMockRecorder) RecordGuardrail(ctx, source, phase, act
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGuardrail", reflect.TypeOf((*MockOpenTelemetry)(nil).RecordGuardrail), ctx, source, phase, action, path, model)
 }
 
+// RecordRealtimeSession mocks base method.
+func (m *MockOpenTelemetry) RecordRealtimeSession(ctx context.Context, source, team, provider, model, errorType string, seconds float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordRealtimeSession", ctx, source, team, provider, model, errorType, seconds)
+}
+
+// RecordRealtimeSession indicates an expected call of RecordRealtimeSession.
+func (mr *MockOpenTelemetryMockRecorder) RecordRealtimeSession(ctx, source, team, provider, model, errorType, seconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRealtimeSession", reflect.TypeOf((*MockOpenTelemetry)(nil).RecordRealtimeSession), ctx, source, team, provider, model, errorType, seconds)
+}
+
 // RecordRequestDuration mocks base method.
 func (m *MockOpenTelemetry) RecordRequestDuration(ctx context.Context, source, team, provider, model, errorType string, seconds float64) {
 	m.ctrl.T.Helper()
@@ -96,6 +120,18 @@ func (mr *MockOpenTelemetryMockRecorder) RecordRequestDuration(ctx, source, team
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRequestDuration", reflect.TypeOf((*MockOpenTelemetry)(nil).RecordRequestDuration), ctx, source, team, provider, model, errorType, seconds)
 }
 
+// RecordRerankUsage mocks base method.
+func (m *MockOpenTelemetry) RecordRerankUsage(ctx context.Context, source, team, provider, model string, tokens, searchUnits int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordRerankUsage", ctx, source, team, provider, model, tokens, searchUnits)
+}
+
+// RecordRerankUsage indicates an expected call of RecordRerankUsage.
+func (mr *MockOpenTelemetryMockRecorder) RecordRerankUsage(ctx, source, team, provider, model, tokens, searchUnits any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRerankUsage", reflect.TypeOf((*MockOpenTelemetry)(nil).RecordRerankUsage), ctx, source, team, provider, model, tokens, searchUnits)
+}
+
 // RecordTokenUsage mocks base method.
 func (m *MockOpenTelemetry) RecordTokenUsage(ctx context.Context, source, team, provider, model string, inputTokens, outputTokens int64) {
 	m.ctrl.T.Helper()
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -170,10 +171,10 @@ func (mr *MockIProviderMockRecorder) ListModels(ctx any) *gomock.Call {
 }
 
 // StreamChatCompletions mocks base method.
-func (m *MockIProvider) StreamChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (<-chan []byte, error) {
+func (m *MockIProvider) StreamChatCompletions(ctx context.Context, clientReq types.CreateChatCompletionRequest) (<-chan core.StreamEvent, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "StreamChatCompletions", ctx, clientReq)
-	ret0, _ := ret[0].(<-chan []byte)
+	ret0, _ := ret[0].(<-chan core.StreamEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -71,3 +71,15 @@ func (mr *MockProviderRegistryMockRecorder) GetProviders() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviders", reflect.TypeOf((*MockProviderRegistry)(nil).GetProviders))
 }
+
+// Reload mocks base method.
+func (m *MockProviderRegistry) Reload(cfg map[types.Provider]*registry.ProviderConfig) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reload", cfg)
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockProviderRegistryMockRecorder) Reload(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockProviderRegistry)(nil).Reload), cfg)
+}
@@ -10,9 +10,13 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gin "github.com/gin-gonic/gin"
+	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	mcp "github.com/inference-gateway/inference-gateway/internal/mcp"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,6 +44,56 @@ func (m *MockRouter) EXPECT() *MockRouterMockRecorder {
 	return m.recorder
 }
 
+// AudioSpeechHandler mocks base method.
+func (m *MockRouter) AudioSpeechHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AudioSpeechHandler", c)
+}
+
+// AudioSpeechHandler indicates an expected call of AudioSpeechHandler.
+func (mr *MockRouterMockRecorder) AudioSpeechHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AudioSpeechHandler", reflect.TypeOf((*MockRouter)(nil).AudioSpeechHandler), c)
+}
+
+// AudioTranscriptionsHandler mocks base method.
+func (m *MockRouter) AudioTranscriptionsHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AudioTranscriptionsHandler", c)
+}
+
+// AudioTranscriptionsHandler indicates an expected call of AudioTranscriptionsHandler.
+func (mr *MockRouterMockRecorder) AudioTranscriptionsHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AudioTranscriptionsHandler", reflect.TypeOf((*MockRouter)(nil).AudioTranscriptionsHandler), c)
+}
+
+// AudioTranslationsHandler mocks base method.
+func (m *MockRouter) AudioTranslationsHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AudioTranslationsHandler", c)
+}
+
+// AudioTranslationsHandler indicates an expected call of AudioTranslationsHandler.
+func (mr *MockRouterMockRecorder) AudioTranslationsHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AudioTranslationsHandler", reflect.TypeOf((*MockRouter)(nil).AudioTranslationsHandler), c)
+}
+
+// ChatCompletionsAdmission mocks base method.
+func (m *MockRouter) ChatCompletionsAdmission() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChatCompletionsAdmission")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// ChatCompletionsAdmission indicates an expected call of ChatCompletionsAdmission.
+func (mr *MockRouterMockRecorder) ChatCompletionsAdmission() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChatCompletionsAdmission", reflect.TypeOf((*MockRouter)(nil).ChatCompletionsAdmission))
+}
+
 // ChatCompletionsHandler mocks base method.
 func (m *MockRouter) ChatCompletionsHandler(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -52,6 +106,18 @@ func (mr *MockRouterMockRecorder) ChatCompletionsHandler(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChatCompletionsHandler", reflect.TypeOf((*MockRouter)(nil).ChatCompletionsHandler), c)
 }
 
+// CompletionsHandler mocks base method.
+func (m *MockRouter) CompletionsHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CompletionsHandler", c)
+}
+
+// CompletionsHandler indicates an expected call of CompletionsHandler.
+func (mr *MockRouterMockRecorder) CompletionsHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompletionsHandler", reflect.TypeOf((*MockRouter)(nil).CompletionsHandler), c)
+}
+
 // HealthcheckHandler mocks base method.
 func (m *MockRouter) HealthcheckHandler(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -124,6 +190,18 @@ func (mr *MockRouterMockRecorder) ListToolsHandler(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsHandler", reflect.TypeOf((*MockRouter)(nil).ListToolsHandler), c)
 }
 
+// MessagesCountTokensHandler mocks base method.
+func (m *MockRouter) MessagesCountTokensHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MessagesCountTokensHandler", c)
+}
+
+// MessagesCountTokensHandler indicates an expected call of MessagesCountTokensHandler.
+func (mr *MockRouterMockRecorder) MessagesCountTokensHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessagesCountTokensHandler", reflect.TypeOf((*MockRouter)(nil).MessagesCountTokensHandler), c)
+}
+
 // MessagesHandler mocks base method.
 func (m *MockRouter) MessagesHandler(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -148,6 +226,18 @@ func (mr *MockRouterMockRecorder) MetricsIngestionHandler(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MetricsIngestionHandler", reflect.TypeOf((*MockRouter)(nil).MetricsIngestionHandler), c)
 }
 
+// ModerationsHandler mocks base method.
+func (m *MockRouter) ModerationsHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ModerationsHandler", c)
+}
+
+// ModerationsHandler indicates an expected call of ModerationsHandler.
+func (mr *MockRouterMockRecorder) ModerationsHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerationsHandler", reflect.TypeOf((*MockRouter)(nil).ModerationsHandler), c)
+}
+
 // NotFoundHandler mocks base method.
 func (m *MockRouter) NotFoundHandler(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -172,6 +262,42 @@ func (mr *MockRouterMockRecorder) ProxyHandler(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyHandler", reflect.TypeOf((*MockRouter)(nil).ProxyHandler), c)
 }
 
+// RealtimeHandler mocks base method.
+func (m *MockRouter) RealtimeHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RealtimeHandler", c)
+}
+
+// RealtimeHandler indicates an expected call of RealtimeHandler.
+func (mr *MockRouterMockRecorder) RealtimeHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RealtimeHandler", reflect.TypeOf((*MockRouter)(nil).RealtimeHandler), c)
+}
+
+// Reload mocks base method.
+func (m *MockRouter) Reload(cfg config.Config) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reload", cfg)
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockRouterMockRecorder) Reload(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockRouter)(nil).Reload), cfg)
+}
+
+// RerankHandler mocks base method.
+func (m *MockRouter) RerankHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RerankHandler", c)
+}
+
+// RerankHandler indicates an expected call of RerankHandler.
+func (mr *MockRouterMockRecorder) RerankHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RerankHandler", reflect.TypeOf((*MockRouter)(nil).RerankHandler), c)
+}
+
 // ResponsesHandler mocks base method.
 func (m *MockRouter) ResponsesHandler(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -183,3 +309,63 @@ func (mr *MockRouterMockRecorder) ResponsesHandler(c any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResponsesHandler", reflect.TypeOf((*MockRouter)(nil).ResponsesHandler), c)
 }
+
+// SetAgentFactory mocks base method.
+func (m *MockRouter) SetAgentFactory(factory func() mcp.Agent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAgentFactory", factory)
+}
+
+// SetAgentFactory indicates an expected call of SetAgentFactory.
+func (mr *MockRouterMockRecorder) SetAgentFactory(factory any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAgentFactory", reflect.TypeOf((*MockRouter)(nil).SetAgentFactory), factory)
+}
+
+// SetGuardrails mocks base method.
+func (m *MockRouter) SetGuardrails(checker middlewares.GuardrailsChecker) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetGuardrails", checker)
+}
+
+// SetGuardrails indicates an expected call of SetGuardrails.
+func (mr *MockRouterMockRecorder) SetGuardrails(checker any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGuardrails", reflect.TypeOf((*MockRouter)(nil).SetGuardrails), checker)
+}
+
+// StartModelRefresh mocks base method.
+func (m *MockRouter) StartModelRefresh(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartModelRefresh", ctx)
+}
+
+// StartModelRefresh indicates an expected call of StartModelRefresh.
+func (mr *MockRouterMockRecorder) StartModelRefresh(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartModelRefresh", reflect.TypeOf((*MockRouter)(nil).StartModelRefresh), ctx)
+}
+
+// TokenizeHandler mocks base method.
+func (m *MockRouter) TokenizeHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TokenizeHandler", c)
+}
+
+// TokenizeHandler indicates an expected call of TokenizeHandler.
+func (mr *MockRouterMockRecorder) TokenizeHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenizeHandler", reflect.TypeOf((*MockRouter)(nil).TokenizeHandler), c)
+}
+
+// WebSocketHandler mocks base method.
+func (m *MockRouter) WebSocketHandler(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WebSocketHandler", c)
+}
+
+// WebSocketHandler indicates an expected call of WebSocketHandler.
+func (mr *MockRouterMockRecorder) WebSocketHandler(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebSocketHandler", reflect.TypeOf((*MockRouter)(nil).WebSocketHandler), c)
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
@@ -16,6 +17,7 @@ import (
 
 	logger "github.com/inference-gateway/inference-gateway/logger"
 	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
 	registry "github.com/inference-gateway/inference-gateway/providers/registry"
 	transformers "github.com/inference-gateway/inference-gateway/providers/transformers"
 	types "github.com/inference-gateway/inference-gateway/providers/types"
@@ -93,7 +95,7 @@ func TestProviderRegistry(t *testing.T) {
 // TestProviderChatCompletions tests chat completions functionality
 func TestProviderChatCompletions(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "/proxy/openai/chat/completions", r.URL.Path)
+		assert.Equal(t, "/chat/completions", r.URL.Path)
 		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
 
 		w.Header().Set("Content-Type", "application/json")
@@ -129,7 +131,7 @@ func TestProviderChatCompletions(t *testing.T) {
 			assert.Contains(t, req.URL.Path, "/chat/completions")
 			assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
 
-			return http.DefaultClient.Post(server.URL+"/proxy/openai/chat/completions", "application/json", nil)
+			return http.DefaultClient.Do(req)
 		})
 
 	log, err := logger.NewLogger("test")
@@ -174,12 +176,10 @@ func TestProviderChatCompletions(t *testing.T) {
 	assert.Equal(t, "stop", string(resp.Choices[0].FinishReason))
 }
 
-// TestProviderChatCompletionsForwardsAuthToken verifies the caller's auth token is
-// forwarded onto the gateway's internal /proxy hop. With authentication enabled the
-// OIDC middleware also guards /proxy, so without this header the gateway rejects its
-// own self-proxy request with 401. ListModels already forwards the token; this guards
-// the chat path against regressing.
-func TestProviderChatCompletionsForwardsAuthToken(t *testing.T) {
+// TestProviderChatCompletionsUsesProviderCredential verifies providers call the
+// upstream URL directly with their own credential. The caller's gateway token
+// (set on the context by the OIDC middleware) must never reach the provider.
+func TestProviderChatCompletionsUsesProviderCredential(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -210,8 +210,9 @@ func TestProviderChatCompletionsForwardsAuthToken(t *testing.T) {
 	mockClient.EXPECT().
 		Do(gomock.Any()).
 		DoAndReturn(func(req *http.Request) (*http.Response, error) {
-			assert.Equal(t, "Bearer client-token", req.Header.Get("Authorization"))
-			return http.DefaultClient.Post(server.URL+"/proxy/openai/chat/completions", "application/json", nil)
+			assert.Equal(t, server.URL+"/chat/completions", req.URL.String())
+			assert.Equal(t, "Bearer test-token", req.Header.Get("Authorization"))
+			return http.DefaultClient.Do(req)
 		})
 
 	log, err := logger.NewLogger("test")
@@ -242,9 +243,7 @@ func TestProviderChatCompletionsForwardsAuthToken(t *testing.T) {
 		},
 	}
 
-	ctx := context.WithValue(context.Background(), types.AuthTokenContextKey, "client-token")
-
-	resp, err := provider.ChatCompletions(ctx, req)
+	resp, err := provider.ChatCompletions(context.Background(), req)
 	require.NoError(t, err)
 	assert.Equal(t, "test-completion-id", resp.ID)
 }
@@ -280,7 +279,8 @@ func TestProviderChatCompletionsForwardsNewParameters(t *testing.T) {
 			body, err := io.ReadAll(req.Body)
 			require.NoError(t, err)
 			capturedBody = body
-			return http.DefaultClient.Post(server.URL+"/proxy/openai/chat/completions", "application/json", nil)
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			return http.DefaultClient.Do(req)
 		})
 
 	log, err := logger.NewLogger("test")
@@ -378,7 +378,7 @@ func TestProviderChatCompletionsForwardsNewParameters(t *testing.T) {
 // TestProviderListModels tests listing models functionality
 func TestProviderListModels(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "/proxy/openai/models", r.URL.Path)
+		assert.Equal(t, "/models", r.URL.Path)
 		assert.Equal(t, "GET", r.Method)
 
 		w.Header().Set("Content-Type", "application/json")
@@ -413,7 +413,7 @@ func TestProviderListModels(t *testing.T) {
 		DoAndReturn(func(req *http.Request) (*http.Response, error) {
 			assert.Equal(t, "GET", req.Method)
 			assert.Contains(t, req.URL.String(), "/models")
-			return http.DefaultClient.Get(server.URL + "/proxy/openai/models")
+			return http.DefaultClient.Do(req)
 		})
 
 	log, err := logger.NewLogger("test")
@@ -545,7 +545,7 @@ func BenchmarkChatCompletions(b *testing.B) {
 	mockClient.EXPECT().
 		Do(gomock.Any()).
 		DoAndReturn(func(req *http.Request) (*http.Response, error) {
-			return http.DefaultClient.Post(server.URL+"/proxy/openai/chat/completions", "application/json", nil)
+			return http.DefaultClient.Do(req)
 		}).
 		AnyTimes()
 
@@ -614,7 +614,7 @@ func BenchmarkListModels(b *testing.B) {
 	mockClient.EXPECT().
 		Do(gomock.Any()).
 		DoAndReturn(func(req *http.Request) (*http.Response, error) {
-			return http.DefaultClient.Get(server.URL + "/proxy/openai/models")
+			return http.DefaultClient.Do(req)
 		}).
 		AnyTimes()
 
@@ -728,3 +728,93 @@ func TestProviderListModelsClosesResponseBody(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, body.closed)
 }
+
+// providerAnswering builds an OpenAI provider whose upstream answers every
+// request with body, sent with the given Content-Type
+func providerAnswering(t *testing.T, contentType, body string) core.IProvider {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockClient := providersmocks.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil)
+
+	log, err := logger.NewLogger("test")
+	require.NoError(t, err)
+
+	providerRegistry := registry.NewProviderRegistry(map[types.Provider]*registry.ProviderConfig{
+		constants.OpenaiID: {
+			ID:       constants.OpenaiID,
+			Name:     constants.OpenaiDisplayName,
+			URL:      "http://localhost",
+			Token:    "test-token",
+			AuthType: constants.AuthTypeBearer,
+			Endpoints: types.Endpoints{
+				Chat: constants.OpenaiChatEndpoint,
+			},
+		},
+	}, log)
+
+	provider, err := providerRegistry.BuildProvider(constants.OpenaiID, mockClient)
+	require.NoError(t, err)
+	return provider
+}
+
+// A backend ignoring stream=true gets its single completion turned into a
+// chunk sequence.
+func TestProviderStreamChatCompletionsSynthesizesStream(t *testing.T) {
+	provider := providerAnswering(t, "application/json", `{
+		"id": "c1", "object": "chat.completion", "created": 1, "model": "gpt-4o",
+		"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "Hello!"}}],
+		"usage": {"prompt_tokens": 3, "completion_tokens": 2, "total_tokens": 5}
+	}`)
+
+	stream, err := provider.StreamChatCompletions(context.Background(), types.CreateChatCompletionRequest{Model: "gpt-4o"})
+	require.NoError(t, err)
+
+	var content strings.Builder
+	var finishReasons []types.FinishReason
+	var usage *types.CompletionUsage
+	for event := range stream {
+		require.NoError(t, event.Err)
+		for _, choice := range event.Chunk.Choices {
+			assert.Equal(t, types.Assistant, choice.Delta.Role)
+			content.WriteString(choice.Delta.Content)
+			if choice.FinishReason != "" {
+				finishReasons = append(finishReasons, choice.FinishReason)
+			}
+		}
+		if event.Usage != nil {
+			assert.Empty(t, event.Chunk.Choices, "usage comes in a chunk of its own")
+			usage = event.Usage
+		}
+	}
+	assert.Equal(t, "Hello!", content.String())
+	assert.Equal(t, []types.FinishReason{types.Stop}, finishReasons)
+	require.NotNil(t, usage)
+	assert.Equal(t, int64(5), usage.TotalTokens)
+}
+
+// A backend answering stream=false with a stream gets it aggregated into a
+// single completion.
+func TestProviderChatCompletionsAggregatesStream(t *testing.T) {
+	provider := providerAnswering(t, "text/event-stream", strings.Join([]string{
+		`data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+		`data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+		`data: [DONE]`,
+	}, "\n\n")+"\n\n")
+
+	resp, err := provider.ChatCompletions(context.Background(), types.CreateChatCompletionRequest{Model: "gpt-4o"})
+	require.NoError(t, err)
+	assert.Equal(t, "c1", resp.ID)
+	assert.Equal(t, "chat.completion", resp.Object)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "Hello", resp.Choices[0].Message.TextContent())
+	assert.Equal(t, types.Stop, resp.Choices[0].FinishReason)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, int64(5), resp.Usage.TotalTokens)
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	config "github.com/inference-gateway/inference-gateway/config"
+	proxy "github.com/inference-gateway/inference-gateway/internal/proxy"
+	client "github.com/inference-gateway/inference-gateway/providers/client"
+
+	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
+)
+
+func TestDevClient_LogsProviderCalls(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"model":"gpt-4o","messages":[]}`, string(body), "the logged body still reaches the provider")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1"}`))
+	}))
+	defer upstream.Close()
+
+	ctrl := gomock.NewController(t)
+	log := mocks.NewMockLogger(ctrl)
+	log.EXPECT().Debug("proxy request", "method", http.MethodPost, "url", upstream.URL+"/chat/completions", "content_length", gomock.Any(), "body_preview", gomock.Any())
+	log.EXPECT().Debug("proxy response", "status", "200 OK", "content_length", gomock.Any(), "content_type", "application/json", "body", gomock.Any())
+
+	cfg := config.Config{DebugContentTruncateWords: 10, DebugMaxMessages: 10}
+	httpClient := proxy.NewDevClient(client.NewHTTPClient(&client.ClientConfig{}), log, &cfg)
+
+	resp, err := httpClient.Post(upstream.URL+"/chat/completions", "application/json", `{"model":"gpt-4o","messages":[]}`)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"chatcmpl-1"}`, strings.TrimSpace(string(body)))
+}
@@ -7,15 +7,27 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	assert "github.com/stretchr/testify/assert"
 	require "github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
 
 	gin "github.com/gin-gonic/gin"
 
+	mocks "github.com/inference-gateway/inference-gateway/tests/mocks"
+	providersmocks "github.com/inference-gateway/inference-gateway/tests/mocks/providers"
+
+	api "github.com/inference-gateway/inference-gateway/api"
 	middlewares "github.com/inference-gateway/inference-gateway/api/middlewares"
+	config "github.com/inference-gateway/inference-gateway/config"
+	logger "github.com/inference-gateway/inference-gateway/logger"
+	otel "github.com/inference-gateway/inference-gateway/otel"
+	constants "github.com/inference-gateway/inference-gateway/providers/constants"
+	core "github.com/inference-gateway/inference-gateway/providers/core"
+	types "github.com/inference-gateway/inference-gateway/providers/types"
 )
 
 func TestSSEStreamSurvivesServerWriteTimeout(t *testing.T) {
@@ -51,8 +63,8 @@ func TestSSEStreamSurvivesServerWriteTimeout(t *testing.T) {
 	}
 }
 
-// Reverse-proxied SSE (the /proxy path, used by the gateway's own self-proxy
-// hop for chat completions) must also outlive the server write timeout.
+// Reverse-proxied SSE (the public /proxy passthrough) must also outlive the
+// server write timeout.
 func TestProxiedSSEStreamSurvivesServerWriteTimeout(t *testing.T) {
 	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/event-stream")
@@ -88,3 +100,116 @@ func TestProxiedSSEStreamSurvivesServerWriteTimeout(t *testing.T) {
 		assert.Contains(t, string(body), fmt.Sprintf("chunk-%d", i))
 	}
 }
+
+// A silent provider stream gets keep-alive comments until the idle timeout,
+// then ends with an error event instead of [DONE].
+func TestChatCompletionsStreamKeepAliveAndIdleTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	log, cfg := routingTestSetup(t)
+	cfg.Server.StreamKeepaliveInterval = 20 * time.Millisecond
+	cfg.Server.StreamIdleTimeout = 200 * time.Millisecond
+
+	mockClient := providersmocks.NewMockClient(ctrl)
+	prov := providersmocks.NewMockIProvider(ctrl)
+	reg := providersmocks.NewMockProviderRegistry(ctrl)
+
+	prov.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, _ types.CreateChatCompletionRequest) (<-chan core.StreamEvent, error) {
+			ch := make(chan core.StreamEvent, 1)
+			ch <- streamChunk(`{"id":"a","choices":[{"index":0,"delta":{"content":"Hi"}}]}`)
+			return ch, nil
+		})
+	reg.EXPECT().BuildProvider(constants.OpenaiID, mockClient).Return(prov, nil)
+
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
+	r := gin.New()
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/chat/completions", "application/json", chatRequest(t, "openai/gpt-4o", true).Body)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(string(body), `data: {"id":"a"`), "the chunk comes first: %q", body)
+	assert.Contains(t, string(body), ": keep-alive\n\n")
+	assert.True(t, strings.HasSuffix(string(body), "data: {\"error\":{\"message\":\"upstream stream idle for 200ms\",\"type\":\"timeout_error\",\"code\":\"timeout\",\"param\":null}}\n\n"), "the stream ends with the idle error: %q", body)
+	assert.NotContains(t, string(body), "[DONE]")
+}
+
+// Telemetry records the usage and tool calls of a streamed completion from
+// its stream events.
+func TestChatCompletionsStreamTelemetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log, cfg := routingTestSetup(t)
+
+	mockClient := providersmocks.NewMockClient(ctrl)
+	prov := providersmocks.NewMockIProvider(ctrl)
+	reg := providersmocks.NewMockProviderRegistry(ctrl)
+	prov.EXPECT().StreamChatCompletions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, _ types.CreateChatCompletionRequest) (<-chan core.StreamEvent, error) {
+			ch := make(chan core.StreamEvent, 4)
+			ch <- streamChunk(`{"id":"a","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]}`)
+			ch <- streamChunk(`{"id":"a","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`)
+			ch <- streamChunk(`{"id":"a","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`)
+			ch <- streamChunk(`{"id":"a","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`)
+			close(ch)
+			return ch, nil
+		})
+	reg.EXPECT().BuildProvider(constants.OpenaiID, mockClient).Return(prov, nil)
+
+	mockOtel := mocks.NewMockOpenTelemetry(ctrl)
+	mockOtel.EXPECT().RecordRequestDuration(gomock.Any(), otel.SourceGateway, otel.TeamUnknown, "openai", "openai/gpt-4o", "", gomock.Any())
+	mockOtel.EXPECT().RecordTokenUsage(gomock.Any(), otel.SourceGateway, otel.TeamUnknown, "openai", "openai/gpt-4o", int64(12), int64(3))
+	mockOtel.EXPECT().RecordToolCall(gomock.Any(), otel.SourceGateway, otel.TeamUnknown, "openai", "openai/gpt-4o", "standard_tool_use", "get_weather")
+	telemetry, err := middlewares.NewTelemetryMiddleware(cfg, mockOtel, logger.NewNoopLogger())
+	require.NoError(t, err)
+
+	router := api.NewRouter(cfg, log, reg, mockClient, nil, nil, nil, nil)
+	r := gin.New()
+	r.Use(telemetry.Middleware())
+	r.POST("/v1/chat/completions", router.ChatCompletionsHandler)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/chat/completions", "application/json", chatRequest(t, "openai/gpt-4o", true).Body)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// Relayed Anthropic streams end with an Anthropic error event when the
+// upstream goes silent.
+func TestMessagesStreamIdleTimeout(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: message_start\ndata: {\"type\":\"message_start\"}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer upstream.Close()
+
+	router := newMessagesTestRouter(t, upstream.URL, func(cfg *config.Config) {
+		cfg.Server.StreamKeepaliveInterval = 20 * time.Millisecond
+		cfg.Server.StreamIdleTimeout = 200 * time.Millisecond
+	})
+	r := gin.New()
+	r.POST("/v1/messages", router.MessagesHandler)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/messages", "application/json", strings.NewReader(`{"model":"anthropic/claude-sonnet-4-5","max_tokens":16,"stream":true,"messages":[{"role":"user","content":"Hello"}]}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(string(body), "event: message_start\n"), "the upstream event comes first: %q", body)
+	assert.Contains(t, string(body), ": keep-alive\n\n")
+	assert.True(t, strings.HasSuffix(string(body), "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"timeout_error\",\"message\":\"upstream stream idle for 200ms\"}}\n\n"), "the stream ends with the idle error: %q", body)
+}
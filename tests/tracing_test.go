@@ -244,7 +244,7 @@ func TestTracingOutboundClientSpanNames(t *testing.T) {
 			}))
 			defer server.Close()
 
-			httpClient := client.NewHTTPClient(&client.ClientConfig{}, "http", "", "")
+			httpClient := client.NewHTTPClient(&client.ClientConfig{})
 			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
 			require.NoError(t, err)
 
@@ -292,7 +292,7 @@ func TestTracingProxyPropagation(t *testing.T) {
 		},
 		Providers: providerCfg,
 	}
-	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), providersmocks.NewMockClient(ctrl), nil, nil, nil)
+	router := api.NewRouter(cfg, log, registry.NewProviderRegistry(providerCfg, log), providersmocks.NewMockClient(ctrl), nil, nil, nil, nil)
 
 	r := gin.New()
 	r.Use(otelgin.Middleware("inference-gateway"))